@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rnegic/synchronous/internal/app"
+)
+
+func main() {
+
+	app := app.New()
+
+	err := app.RecomputeStats()
+	if err != nil {
+		fmt.Println(err)
+	}
+}
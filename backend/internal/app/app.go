@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rnegic/synchronous/internal/config"
+	"github.com/rnegic/synchronous/internal/interfaces"
 	gormRepo "github.com/rnegic/synchronous/internal/repository/gorm"
+	memoryRepo "github.com/rnegic/synchronous/internal/repository/memory"
 	"github.com/rnegic/synchronous/internal/router"
 	"github.com/rnegic/synchronous/internal/service"
 	"github.com/rnegic/synchronous/internal/transport/http/middleware"
@@ -43,10 +45,18 @@ func (a *App) Run() error {
 	}()
 
 	userRepo := gormRepo.NewUserRepository(db)
+	presetRepo := gormRepo.NewSessionPresetRepository(db)
 	sessionRepo := gormRepo.NewSessionRepository(db)
+	waitlistRepo := gormRepo.NewSessionWaitlistRepository(db)
+	joinRequestRepo := gormRepo.NewSessionJoinRequestRepository(db)
 	taskRepo := gormRepo.NewTaskRepository(db)
+	phaseSegmentRepo := gormRepo.NewPhaseSegmentRepository(db)
+	attendanceRepo := gormRepo.NewAttendanceRepository(db)
+	seriesRepo := gormRepo.NewSessionSeriesRepository(db)
 	messageRepo := gormRepo.NewMessageRepository(db)
 	leaderboardRepo := gormRepo.NewLeaderboardRepository(db)
+	contactRepo := gormRepo.NewContactRepository(db)
+	achievementRepo := gormRepo.NewAchievementRepository(db)
 
 	// Инициализация Telegram API клиента и сервиса
 	telegramAPIService := service.NewTelegramAPIService(cfg.TelegramAPI.BotToken)
@@ -66,23 +76,62 @@ func (a *App) Run() error {
 		log.Printf("[Config] ✅ BOT_TOKEN loaded (length: %d)", len(botToken))
 	}
 	authService := service.NewAuthService(userRepo, tokenManager, botToken)
-	userService := service.NewUserService(userRepo)
-	sessionService := service.NewSessionService(sessionRepo, taskRepo, userRepo, telegramAPIService)
+	userService := service.NewUserService(userRepo, contactRepo, achievementRepo, sessionRepo)
+	presetService := service.NewSessionPresetService(presetRepo)
+	sessionService := service.NewSessionService(sessionRepo, taskRepo, phaseSegmentRepo, attendanceRepo, waitlistRepo, joinRequestRepo, userRepo, contactRepo, achievementRepo, telegramAPIService, cfg.App.MaxSessionSize)
+	// Экземпляры повторяющихся серий создаются на неделю вперед
+	seriesService := service.NewSessionSeriesService(seriesRepo, sessionRepo, sessionService, 7*24*time.Hour)
 	messageService := service.NewMessageService(sessionService, telegramAPIService, userRepo, messageRepo)
-	leaderboardService := service.NewLeaderboardService(leaderboardRepo, sessionRepo, userRepo)
+	// Формула очков лидербордов выбирается в конфиге
+	scoring, err := service.NewScoringStrategy(cfg.App.ScoringStrategy)
+	if err != nil {
+		return fmt.Errorf("error with config: %v", err)
+	}
+	log.Printf("[Config] Leaderboard scoring: %s", scoring.Name())
+	leaderboardService := service.NewLeaderboardService(leaderboardRepo, sessionRepo, userRepo, contactRepo, scoring)
 
 	// Start session cleanup service (cleanup sessions older than 1 hour every 15 minutes)
 	cleanupService := service.NewSessionCleanupService(sessionRepo, 15*time.Minute, 1*time.Hour)
 	cleanupService.Start()
 
+	seriesGenerator := service.NewSessionSeriesGeneratorService(seriesService, 1*time.Hour)
+	seriesGenerator.Start()
+
+	// Серии дней прерываются в полночь по времени пользователя, поэтому проверка идет каждые 5 минут
+	streakResetService := service.NewStreakResetService(userRepo, 5*time.Minute)
+	streakResetService.Start()
+
 	// Инициализация handlers
 	baseHandler := v1.NewBaseHandler()
 
+	// Шина realtime-событий: при нескольких репликах события должны доходить до сокетов на всех узлах
+	var backplane interfaces.EventBackplane
+	switch cfg.App.RealtimeBackplane {
+	case "postgres":
+		backplane = gormRepo.NewEventBackplane(db, dsn)
+	case "memory", "":
+		backplane = memoryRepo.NewEventBackplane()
+	default:
+		return fmt.Errorf("unknown realtime backplane: %s", cfg.App.RealtimeBackplane)
+	}
+	defer backplane.Close()
+	log.Printf("[Config] Realtime backplane: %s", cfg.App.RealtimeBackplane)
+
+	wsHandler := v1.NewWebSocketHandler(baseHandler, sessionService, backplane)
+
+	// Движок фаз: ведет активные сессии по плану циклов, рассылает phase_changed, завершает сессии по окончании плана
+	// и ведет обратный отсчет автостарта по готовности участников
+	timerService := service.NewSessionTimerService(sessionRepo, phaseSegmentRepo, sessionService, wsHandler, 1*time.Second)
+	timerService.Start()
+
+	// Планировщик: автостарт запланированных сессий и напоминания в Telegram за 10 минут и при старте
+	schedulerService := service.NewSessionSchedulerService(sessionRepo, userRepo, sessionService, telegramAPIService, wsHandler, 10*time.Second, 10*time.Minute)
+	schedulerService.Start()
+
 	authHandler := v1.NewAuthHandler(baseHandler, authService, tokenManager)
-	userHandler := v1.NewUserHandler(baseHandler, userService)
-	wsHandler := v1.NewWebSocketHandler(baseHandler)
-	sessionHandler := v1.NewSessionHandler(baseHandler, sessionService, messageService, leaderboardService, wsHandler)
-	webhookHandler := v1.NewWebhookHandler(baseHandler, sessionService, telegramAPIService, authService)
+	userHandler := v1.NewUserHandler(baseHandler, userService, presetService)
+	sessionHandler := v1.NewSessionHandler(baseHandler, sessionService, seriesService, presetService, messageService, leaderboardService, wsHandler)
+	webhookHandler := v1.NewWebhookHandler(baseHandler, sessionService, telegramAPIService, authService, wsHandler)
 
 	// Инициализация роутера на gin
 	appRouter := router.New()
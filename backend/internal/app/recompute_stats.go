@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rnegic/synchronous/internal/config"
+	gormRepo "github.com/rnegic/synchronous/internal/repository/gorm"
+	"github.com/rnegic/synchronous/internal/service"
+)
+
+// RecomputeStats пересчитывает статистику пользователей, контакты из общих сессий и значки по истории завершенных сессий.
+// Запускается вручную (make recompute-stats), например после исправления подсчета
+func (a *App) RecomputeStats() error {
+	cfg := config.New()
+
+	err := cfg.Load("configs/config.toml")
+	if err != nil {
+		return fmt.Errorf("error with config: %v", err)
+	}
+
+	db, err := gormRepo.InitDB(cfg.BuildDSN())
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := gormRepo.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	sessionService := service.NewSessionService(
+		gormRepo.NewSessionRepository(db),
+		gormRepo.NewTaskRepository(db),
+		gormRepo.NewPhaseSegmentRepository(db),
+		gormRepo.NewAttendanceRepository(db),
+		gormRepo.NewSessionWaitlistRepository(db),
+		gormRepo.NewSessionJoinRequestRepository(db),
+		gormRepo.NewUserRepository(db),
+		gormRepo.NewContactRepository(db),
+		gormRepo.NewAchievementRepository(db),
+		nil, // значки за прошлые сессии выдаются без уведомлений
+		cfg.App.MaxSessionSize,
+	)
+
+	sessions, err := sessionService.RecomputeStats()
+	if err != nil {
+		return fmt.Errorf("failed to recompute stats: %v", err)
+	}
+
+	log.Printf("[App] ✅ Stats recomputed from %d completed session(s)", sessions)
+	return nil
+}
@@ -26,11 +26,13 @@ type Config struct {
 		BotToken string
 	}
 	App struct {
-		JWTSecret      string
-		JWTTTL         int // в секундах
-		RefreshTTL     int // в секундах
-		WebSocketPath  string
-		MaxSessionSize int
+		JWTSecret         string
+		JWTTTL            int // в секундах
+		RefreshTTL        int // в секундах
+		WebSocketPath     string
+		MaxSessionSize    int
+		RealtimeBackplane string // memory | postgres
+		ScoringStrategy   string // очки лидербордов: task-weighted | focus-weighted | consistency
 	}
 }
 
@@ -116,6 +118,12 @@ func (c *Config) Load(filePath string) error {
 	if viper.IsSet("APP.MAX_SESSION_SIZE") {
 		c.App.MaxSessionSize = viper.GetInt("APP.MAX_SESSION_SIZE")
 	}
+	if viper.IsSet("APP.REALTIME_BACKPLANE") {
+		c.App.RealtimeBackplane = viper.GetString("APP.REALTIME_BACKPLANE")
+	}
+	if viper.IsSet("APP.SCORING_STRATEGY") {
+		c.App.ScoringStrategy = viper.GetString("APP.SCORING_STRATEGY")
+	}
 
 	// Проверяем переменную окружения DB_DSN (приоритет над config.toml)
 	if envDSN := viper.GetString("DB_DSN"); envDSN != "" {
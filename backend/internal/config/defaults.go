@@ -9,4 +9,8 @@ func (c *Config) SetDefaults() {
 	c.App.RefreshTTL = 604800 // 7 days for refresh token
 	c.App.WebSocketPath = "/ws"
 	c.App.MaxSessionSize = 20
+	// memory — один инстанс; postgres — несколько реплик за балансировщиком (LISTEN/NOTIFY)
+	c.App.RealtimeBackplane = "memory"
+	// Формула очков лидербордов: task-weighted — 10 за задачу и 1 за минуту фокуса
+	c.App.ScoringStrategy = "task-weighted"
 }
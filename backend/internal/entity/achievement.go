@@ -0,0 +1,64 @@
+package entity
+
+import "time"
+
+type AchievementCode string
+
+const (
+	AchievementFirstGroupSession AchievementCode = "first_group_session" // первая завершенная сессия вместе с другими участниками
+	AchievementStreak7           AchievementCode = "streak_7"            // сессии 7 дней подряд
+	AchievementFocus100h         AchievementCode = "focus_100h"          // 100 часов фокуса всего
+	AchievementPerfect5          AchievementCode = "perfect_5"           // все свои задачи выполнены в 5 сессиях подряд
+)
+
+// UserAchievement — полученный пользователем значок. Значок выдается один раз
+type UserAchievement struct {
+	UserID     string          `gorm:"type:varchar(36);primaryKey" json:"userId"`
+	Code       AchievementCode `gorm:"type:varchar(32);primaryKey" json:"code"`
+	SessionID  *string         `gorm:"type:varchar(36)" json:"sessionId,omitempty"` // сессия, после которой значок получен
+	UnlockedAt time.Time       `gorm:"not null;default:CURRENT_TIMESTAMP" json:"unlockedAt"`
+}
+
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
+
+// Title возвращает название значка для показа пользователю
+func (c AchievementCode) Title() string {
+	switch c {
+	case AchievementFirstGroupSession:
+		return "Вместе веселее"
+	case AchievementStreak7:
+		return "Неделя без пропусков"
+	case AchievementFocus100h:
+		return "100 часов фокуса"
+	case AchievementPerfect5:
+		return "Без хвостов"
+	default:
+		return string(c)
+	}
+}
+
+// Description возвращает условие получения значка
+func (c AchievementCode) Description() string {
+	switch c {
+	case AchievementFirstGroupSession:
+		return "Завершить первую сессию вместе с другими участниками"
+	case AchievementStreak7:
+		return "Проводить сессии 7 дней подряд"
+	case AchievementFocus100h:
+		return "Набрать 100 часов фокуса"
+	case AchievementPerfect5:
+		return "Выполнить все свои задачи в 5 сессиях подряд"
+	default:
+		return ""
+	}
+}
+
+// AchievementEvent — завершение сессии участником, по которому проверяются условия значков
+type AchievementEvent struct {
+	Result       *SessionResult // вклад участника в сессию
+	Stats        *UserStats     // статистика участника с учетом этой сессии
+	Mode         SessionMode
+	Participants int // участники сессии без исключенных
+}
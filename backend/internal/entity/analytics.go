@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+type AnalyticsGranularity string
+
+const (
+	AnalyticsGranularityDay  AnalyticsGranularity = "day"
+	AnalyticsGranularityWeek AnalyticsGranularity = "week" // недели с понедельника
+)
+
+// AnalyticsQuery — период личной аналитики. From и To — дни в часовом поясе пользователя включительно,
+// нулевое значение — период по умолчанию
+type AnalyticsQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity AnalyticsGranularity
+}
+
+// FocusSummary — итоги сессий пользователя за период
+type FocusSummary struct {
+	Sessions       int `json:"sessions"`
+	FocusTime      int `json:"focusTime"` // в минутах
+	TasksCompleted int `json:"tasksCompleted"`
+	TasksTotal     int `json:"tasksTotal"`
+	Cycles         int `json:"cycles"`
+}
+
+// Add учитывает в итогах одну сессию
+func (s *FocusSummary) Add(result *SessionResult, cycles int) {
+	s.Sessions++
+	s.FocusTime += result.FocusTime
+	s.TasksCompleted += result.TasksCompleted
+	s.TasksTotal += result.TasksTotal
+	s.Cycles += cycles
+}
+
+// CompletionRate возвращает долю выполненных задач от 0 до 1; без задач — 0
+func (s FocusSummary) CompletionRate() float64 {
+	if s.TasksTotal == 0 {
+		return 0
+	}
+	return float64(s.TasksCompleted) / float64(s.TasksTotal)
+}
+
+// AverageCycles возвращает среднее число циклов за сессию
+func (s FocusSummary) AverageCycles() float64 {
+	if s.Sessions == 0 {
+		return 0
+	}
+	return float64(s.Cycles) / float64(s.Sessions)
+}
+
+// FocusBucket — итоги за день или неделю периода
+type FocusBucket struct {
+	Start time.Time // первый день
+	FocusSummary
+}
+
+// FocusAnalytics — личная аналитика фокуса за период
+type FocusAnalytics struct {
+	From        time.Time
+	To          time.Time
+	Granularity AnalyticsGranularity
+	Buckets     []*FocusBucket
+	Summary     FocusSummary
+	Previous    FocusSummary // такой же по длине период перед From
+	Solo        FocusSummary
+	Group       FocusSummary
+	Hours       [24]int // минуты фокуса по часу начала сессии
+}
@@ -11,4 +11,5 @@ type AuthTokens struct {
 type TelegramAuthRequest struct {
 	InitData string `json:"initData"`
 	DeviceID string `json:"deviceId"`
+	Timezone string `json:"timezone"` // часовой пояс устройства (IANA), запоминается, если пользователь не выбрал свой
 }
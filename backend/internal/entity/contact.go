@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+type ContactSource string
+
+const (
+	ContactSourceSession ContactSource = "session" // были вместе в завершенной сессии
+	ContactSourceFollow  ContactSource = "follow"  // пользователь подписался сам: по Telegram ID или приглашению
+)
+
+// UserContact — контакт пользователя. Связь направленная: после общей сессии она создается в обе стороны,
+// подписка добавляет только связь подписавшегося
+type UserContact struct {
+	UserID    string        `gorm:"type:varchar(36);primaryKey" json:"userId"`
+	ContactID string        `gorm:"type:varchar(36);primaryKey" json:"contactId"`
+	Source    ContactSource `gorm:"type:varchar(16);not null" json:"source"`
+	CreatedAt time.Time     `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Contact *User `gorm:"-" json:"contact,omitempty"` // заполняет сервис
+}
+
+func (UserContact) TableName() string {
+	return "user_contacts"
+}
+
+// ContactInvitePrefix — префикс кода приглашения в контакты в start_param мини-приложения (у сессий — invite_)
+const ContactInvitePrefix = "friend_"
+
+// FollowTarget — кого добавить в контакты: по Telegram ID или по коду приглашения пользователя
+type FollowTarget struct {
+	TelegramUserID int64
+	InviteCode     string
+}
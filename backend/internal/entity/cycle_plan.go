@@ -0,0 +1,97 @@
+package entity
+
+import "database/sql/driver"
+
+// DefaultLongBreakEvery — через сколько циклов идет длинный перерыв, если задана только его длительность
+// (классический помидоро: 4 × 25/5, затем 15 минут)
+const DefaultLongBreakEvery = 4
+
+// PlannedPhase — фаза явного плана сессии
+type PlannedPhase struct {
+	Phase    SessionPhase `json:"phase"`
+	Duration int          `json:"duration"` // в минутах
+}
+
+// PhasePlan — явная последовательность фаз; хранится в sessions.phase_plan как JSON
+type PhasePlan []PlannedPhase
+
+func (p PhasePlan) Value() (driver.Value, error) {
+	return jsonColumnValue(p, len(p) == 0)
+}
+
+func (p *PhasePlan) Scan(value interface{}) error {
+	*p = nil
+	return scanJSONColumn(value, p)
+}
+
+// CyclePlan — план помодоро-циклов новой сессии
+type CyclePlan struct {
+	Cycles         int       // количество циклов, 0 — без ограничения
+	LongBreak      int       // длинный перерыв в минутах, 0 — без длинных перерывов
+	LongBreakEvery int       // длинный перерыв после каждого N-го цикла, 0 — DefaultLongBreakEvery
+	Phases         PhasePlan // явная последовательность фаз вместо циклов фокус/перерыв
+}
+
+// PlannedPhaseAt возвращает фазу плана с номером index (с нуля); false — план исчерпан.
+// Без явного плана цикл — фокус и перерыв после него, перерыв каждого LongBreakEvery-го цикла длинный;
+// план заканчивается перерывом последнего цикла
+func (s *Session) PlannedPhaseAt(index int) (PlannedPhase, bool) {
+	if index < 0 {
+		return PlannedPhase{}, false
+	}
+	if len(s.PhasePlan) > 0 {
+		if index >= len(s.PhasePlan) {
+			return PlannedPhase{}, false
+		}
+		return s.PhasePlan[index], true
+	}
+
+	if s.FocusDuration <= 0 {
+		return PlannedPhase{}, false
+	}
+
+	cycle := index/2 + 1
+	if s.Cycles > 0 && cycle > s.Cycles {
+		return PlannedPhase{}, false
+	}
+	if index%2 == 0 {
+		return PlannedPhase{Phase: SessionPhaseFocus, Duration: s.FocusDuration}, true
+	}
+	if s.LongBreak > 0 && s.LongBreakEvery > 0 && cycle%s.LongBreakEvery == 0 {
+		return PlannedPhase{Phase: SessionPhaseLongBreak, Duration: s.LongBreak}, true
+	}
+	return PlannedPhase{Phase: SessionPhaseBreak, Duration: s.BreakDuration}, true
+}
+
+// PlannedCycles возвращает число циклов в плане; 0 — план без ограничения
+func (s *Session) PlannedCycles() int {
+	if len(s.PhasePlan) == 0 {
+		return s.Cycles
+	}
+	cycles := 0
+	for _, phase := range s.PhasePlan {
+		if phase.Phase == SessionPhaseFocus {
+			cycles++
+		}
+	}
+	return cycles
+}
+
+// PlannedPhases возвращает весь план по фазам; nil — план без ограничения
+func (s *Session) PlannedPhases() PhasePlan {
+	if len(s.PhasePlan) > 0 {
+		return s.PhasePlan
+	}
+	if s.Cycles <= 0 {
+		return nil
+	}
+
+	phases := make(PhasePlan, 0, 2*s.Cycles)
+	for index := 0; ; index++ {
+		phase, ok := s.PlannedPhaseAt(index)
+		if !ok {
+			return phases
+		}
+		phases = append(phases, phase)
+	}
+}
@@ -0,0 +1,80 @@
+package entity
+
+import "testing"
+
+func TestPlannedPhaseAt(t *testing.T) {
+	classic := &Session{FocusDuration: 25, BreakDuration: 5, Cycles: 4, LongBreak: 15, LongBreakEvery: 2}
+	endless := &Session{FocusDuration: 50, BreakDuration: 10}
+	explicit := &Session{
+		FocusDuration: 25,
+		BreakDuration: 5,
+		PhasePlan: PhasePlan{
+			{Phase: SessionPhaseFocus, Duration: 90},
+			{Phase: SessionPhaseLongBreak, Duration: 20},
+			{Phase: SessionPhaseFocus, Duration: 45},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		session *Session
+		index   int
+		want    PlannedPhase
+		wantOK  bool
+	}{
+		{name: "negative index", session: classic, index: -1},
+		{name: "first focus", session: classic, index: 0, want: PlannedPhase{Phase: SessionPhaseFocus, Duration: 25}, wantOK: true},
+		{name: "short break", session: classic, index: 1, want: PlannedPhase{Phase: SessionPhaseBreak, Duration: 5}, wantOK: true},
+		{name: "long break every second cycle", session: classic, index: 3, want: PlannedPhase{Phase: SessionPhaseLongBreak, Duration: 15}, wantOK: true},
+		{name: "short break after third cycle", session: classic, index: 5, want: PlannedPhase{Phase: SessionPhaseBreak, Duration: 5}, wantOK: true},
+		{name: "plan ends with break of last cycle", session: classic, index: 7, want: PlannedPhase{Phase: SessionPhaseLongBreak, Duration: 15}, wantOK: true},
+		{name: "plan is over", session: classic, index: 8},
+		{name: "no limit", session: endless, index: 41, want: PlannedPhase{Phase: SessionPhaseBreak, Duration: 10}, wantOK: true},
+		{name: "no focus duration", session: &Session{}, index: 0},
+		{name: "explicit plan", session: explicit, index: 1, want: PlannedPhase{Phase: SessionPhaseLongBreak, Duration: 20}, wantOK: true},
+		{name: "explicit plan ignores durations", session: explicit, index: 2, want: PlannedPhase{Phase: SessionPhaseFocus, Duration: 45}, wantOK: true},
+		{name: "explicit plan is over", session: explicit, index: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.session.PlannedPhaseAt(tt.index)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("PlannedPhaseAt(%d) = %+v, %v; want %+v, %v", tt.index, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPlannedCyclesAndPhases(t *testing.T) {
+	tests := []struct {
+		name       string
+		session    *Session
+		wantCycles int
+		wantPhases int
+	}{
+		{name: "no limit", session: &Session{FocusDuration: 25, BreakDuration: 5}, wantCycles: 0, wantPhases: 0},
+		{name: "cycles", session: &Session{FocusDuration: 25, BreakDuration: 5, Cycles: 3}, wantCycles: 3, wantPhases: 6},
+		{
+			name: "explicit plan counts focus phases",
+			session: &Session{PhasePlan: PhasePlan{
+				{Phase: SessionPhaseFocus, Duration: 25},
+				{Phase: SessionPhaseBreak, Duration: 5},
+				{Phase: SessionPhaseFocus, Duration: 25},
+			}},
+			wantCycles: 2,
+			wantPhases: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.PlannedCycles(); got != tt.wantCycles {
+				t.Errorf("PlannedCycles() = %d, want %d", got, tt.wantCycles)
+			}
+			if got := len(tt.session.PlannedPhases()); got != tt.wantPhases {
+				t.Errorf("len(PlannedPhases()) = %d, want %d", got, tt.wantPhases)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Доменные ошибки. Сервисы возвращают их (обернутыми или через типы ниже),
+// а транспорт сопоставляет их со статусами ответа через errors.Is
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrForbidden         = errors.New("forbidden")
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrInvalidTransition = errors.New("invalid session transition")
+	ErrSessionFull       = errors.New("session is full")
+	ErrApprovalRequired  = errors.New("join requires approval")
+
+	ErrSessionNotFound     = fmt.Errorf("session %w", ErrNotFound)
+	ErrParticipantNotFound = fmt.Errorf("participant %w", ErrNotFound)
+	ErrTaskNotFound        = fmt.Errorf("task %w", ErrNotFound)
+	ErrSeriesNotFound      = fmt.Errorf("series %w", ErrNotFound)
+	ErrJoinRequestNotFound = fmt.Errorf("join request %w", ErrNotFound)
+	ErrPresetNotFound      = fmt.Errorf("preset %w", ErrNotFound)
+	ErrUserNotFound        = fmt.Errorf("user %w", ErrNotFound)
+	ErrContactNotFound     = fmt.Errorf("contact %w", ErrNotFound)
+	ErrChatNotFound        = fmt.Errorf("chat %w", ErrNotFound)
+)
+
+// DomainError — ошибка с понятным клиенту текстом, относящаяся к одному из видов выше
+type DomainError struct {
+	Kind   error
+	Reason string
+}
+
+func (e *DomainError) Error() string {
+	return e.Reason
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Kind
+}
+
+// Forbidden — у пользователя нет прав на действие
+func Forbidden(reason string) error {
+	return &DomainError{Kind: ErrForbidden, Reason: reason}
+}
+
+// InvalidArgument — запрос некорректен независимо от состояния сессии
+func InvalidArgument(reason string) error {
+	return &DomainError{Kind: ErrInvalidArgument, Reason: reason}
+}
+
+// TransitionError — действие недопустимо в текущем статусе сессии
+type TransitionError struct {
+	Action SessionAction
+	From   SessionStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s in status %s", e.Action.Description(), e.From)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// WaitlistedError — мест в сессии нет, пользователь поставлен в лист ожидания
+type WaitlistedError struct {
+	SessionID string
+	Position  int // 1 — следующий на освободившееся место
+}
+
+func (e *WaitlistedError) Error() string {
+	return fmt.Sprintf("session is full, you are #%d on the waitlist", e.Position)
+}
+
+func (e *WaitlistedError) Unwrap() error {
+	return ErrSessionFull
+}
+
+// JoinRequestPendingError — вход отложен до одобрения заявки
+type JoinRequestPendingError struct {
+	SessionID string
+	RequestID string
+}
+
+func (e *JoinRequestPendingError) Error() string {
+	return "join request sent, waiting for the host's approval"
+}
+
+func (e *JoinRequestPendingError) Unwrap() error {
+	return ErrApprovalRequired
+}
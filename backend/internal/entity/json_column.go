@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonColumnValue сериализует значение для JSONB-колонки; пустое значение хранится как NULL
+func jsonColumnValue(value interface{}, empty bool) (driver.Value, error) {
+	if empty {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// scanJSONColumn читает JSONB-колонку в dest; NULL оставляет dest без изменений
+func scanJSONColumn(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("unsupported JSON column type %T", value)
+	}
+}
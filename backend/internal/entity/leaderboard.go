@@ -1,13 +1,34 @@
 package entity
 
+import (
+	"math"
+	"time"
+)
+
 type LeaderboardEntry struct {
-	Rank           int     `json:"rank"`
-	UserID         string  `json:"userId"`
-	UserName       string  `json:"userName"`
-	AvatarURL      *string `json:"avatarUrl"`
-	TasksCompleted int     `json:"tasksCompleted"`
-	FocusTime      int     `json:"focusTime"` // в минутах
-	Score          int     `json:"score"`
+	Rank              int            `json:"rank"`
+	UserID            string         `json:"userId"`
+	UserName          string         `json:"userName"`
+	AvatarURL         *string        `json:"avatarUrl"`
+	SessionsCompleted int            `json:"sessionsCompleted"` // сессии, завершенные в окне периода
+	TasksCompleted    int            `json:"tasksCompleted"`
+	FocusTime         int            `json:"focusTime"`     // в минутах
+	CurrentStreak     int            `json:"currentStreak"` // серия на сегодня, а не на конец окна
+	Score             int            `json:"score"`
+	Breakdown         ScoreBreakdown `json:"breakdown"`
+}
+
+// ScoreBreakdown — из чего сложился счет записи: очки за задачи и фокус, умноженные на множитель
+type ScoreBreakdown struct {
+	Strategy    string  `json:"strategy"`
+	TaskPoints  int     `json:"taskPoints"`
+	FocusPoints int     `json:"focusPoints"`
+	Multiplier  float64 `json:"multiplier"` // 1 — без множителя
+}
+
+// Total возвращает итоговый счет
+func (b ScoreBreakdown) Total() int {
+	return int(math.Round(float64(b.TaskPoints+b.FocusPoints) * b.Multiplier))
 }
 
 type LeaderboardPeriod string
@@ -18,3 +39,23 @@ const (
 	LeaderboardPeriodMonth LeaderboardPeriod = "month"
 	LeaderboardPeriodAll   LeaderboardPeriod = "all"
 )
+
+// Since возвращает начало окна периода в часовом поясе пользователя loc; nil — без ограничения.
+// День начинается в местную полночь, неделя и месяц включают текущий день
+func (p LeaderboardPeriod) Since(now time.Time, loc *time.Location) *time.Time {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var since time.Time
+	switch p {
+	case LeaderboardPeriodDay:
+		since = today
+	case LeaderboardPeriodWeek:
+		since = today.AddDate(0, 0, -6)
+	case LeaderboardPeriodMonth:
+		since = today.AddDate(0, -1, 1)
+	default:
+		return nil
+	}
+	return &since
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaderboardPeriodSince(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	// 22:30 UTC 15 марта — уже 16 марта в Москве
+	now := time.Date(2026, 3, 15, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		period LeaderboardPeriod
+		loc    *time.Location
+		want   *time.Time
+	}{
+		{name: "day", period: LeaderboardPeriodDay, loc: time.UTC, want: timePtr(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))},
+		{name: "day in user timezone", period: LeaderboardPeriodDay, loc: moscow, want: timePtr(time.Date(2026, 3, 16, 0, 0, 0, 0, moscow))},
+		{name: "week includes today", period: LeaderboardPeriodWeek, loc: time.UTC, want: timePtr(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))},
+		{name: "month includes today", period: LeaderboardPeriodMonth, loc: time.UTC, want: timePtr(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC))},
+		{name: "month in user timezone", period: LeaderboardPeriodMonth, loc: moscow, want: timePtr(time.Date(2026, 2, 17, 0, 0, 0, 0, moscow))},
+		{name: "all time", period: LeaderboardPeriodAll, loc: time.UTC},
+		{name: "unknown period", period: LeaderboardPeriod("year"), loc: time.UTC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.period.Since(now, tt.loc)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("Since() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreBreakdownTotal(t *testing.T) {
+	tests := []struct {
+		name      string
+		breakdown ScoreBreakdown
+		want      int
+	}{
+		{name: "no multiplier", breakdown: ScoreBreakdown{TaskPoints: 30, FocusPoints: 50, Multiplier: 1}, want: 80},
+		{name: "multiplier", breakdown: ScoreBreakdown{TaskPoints: 30, FocusPoints: 50, Multiplier: 1.5}, want: 120},
+		{name: "rounds to nearest", breakdown: ScoreBreakdown{TaskPoints: 0, FocusPoints: 25, Multiplier: 1.1}, want: 28},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.breakdown.Total(); got != tt.want {
+				t.Errorf("Total() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "encoding/json"
+
+// RealtimeEvent — событие для WebSocket-клиентов, которое передаётся между инстансами бэкенда.
+// Адресат определяется полями: SessionID — комната сессии, UserID — все подключения пользователя,
+// оба пустые — все подключённые клиенты
+type RealtimeEvent struct {
+	SessionID string          `json:"sessionId,omitempty"`
+	UserID    string          `json:"userId,omitempty"`
+	Seq       int64           `json:"seq,omitempty"` // порядковый номер события в рамках сессии, назначается бэкплейном
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+	Control   bool            `json:"control,omitempty"` // служебное событие для хабов (подписка на комнаты), клиентам не отправляется
+}
@@ -23,23 +23,104 @@ const (
 	SessionStatusCancelled SessionStatus = "cancelled"
 )
 
+// SessionAction действие над сессией, допустимость которого зависит от ее статуса
+type SessionAction string
+
+const (
+	SessionActionStart             SessionAction = "start"
+	SessionActionPause             SessionAction = "pause"
+	SessionActionResume            SessionAction = "resume"
+	SessionActionComplete          SessionAction = "complete"
+	SessionActionCancel            SessionAction = "cancel"
+	SessionActionJoin              SessionAction = "join"
+	SessionActionRejoin            SessionAction = "rejoin"
+	SessionActionLeave             SessionAction = "leave"
+	SessionActionRemoveParticipant SessionAction = "remove_participant"
+	SessionActionTransferOwnership SessionAction = "transfer_ownership"
+	SessionActionConfigureStart    SessionAction = "configure_start"
+	SessionActionManageCoHosts     SessionAction = "manage_co_hosts"
+	SessionActionReviewJoinRequest SessionAction = "review_join_request"
+)
+
+var sessionActionDescriptions = map[SessionAction]string{
+	SessionActionStart:             "start session",
+	SessionActionPause:             "pause session",
+	SessionActionResume:            "resume session",
+	SessionActionComplete:          "complete session",
+	SessionActionCancel:            "cancel session",
+	SessionActionJoin:              "join session",
+	SessionActionRejoin:            "rejoin session",
+	SessionActionLeave:             "leave session",
+	SessionActionRemoveParticipant: "remove participants",
+	SessionActionTransferOwnership: "transfer ownership",
+	SessionActionConfigureStart:    "configure session start",
+	SessionActionManageCoHosts:     "manage co-hosts",
+	SessionActionReviewJoinRequest: "review join requests",
+}
+
+// Description возвращает действие в виде фразы для текстов ошибок ("start session")
+func (a SessionAction) Description() string {
+	if description, ok := sessionActionDescriptions[a]; ok {
+		return description
+	}
+	return string(a)
+}
+
+// StartPolicy определяет, когда ожидающая групповая сессия стартует без создателя
+type StartPolicy string
+
+const (
+	StartPolicyManual   StartPolicy = "manual"    // старт только вручную
+	StartPolicyAllReady StartPolicy = "all_ready" // все присутствующие участники готовы
+	StartPolicyQuorum   StartPolicy = "quorum"    // готовы не меньше StartQuorum участников
+)
+
+// DefaultStartCountdown — обратный отсчет перед автостартом, если у сессии он не задан (в секундах)
+const DefaultStartCountdown = 10
+
+// SessionPhase текущая фаза помодоро-цикла активной сессии
+type SessionPhase string
+
+const (
+	SessionPhaseFocus     SessionPhase = "focus"
+	SessionPhaseBreak     SessionPhase = "break"
+	SessionPhaseLongBreak SessionPhase = "long_break"
+)
+
 type Session struct {
 	ID               string         `gorm:"type:varchar(36);primaryKey" json:"id"`
 	Mode             SessionMode    `gorm:"type:session_mode;not null" json:"mode"`
 	Status           SessionStatus  `gorm:"type:session_status;not null;default:'pending'" json:"status"`
-	FocusDuration    int            `gorm:"not null" json:"focusDuration"` // в минутах
-	BreakDuration    int            `gorm:"not null" json:"breakDuration"` // в минутах
+	FocusDuration    int            `gorm:"not null" json:"focusDuration"`            // в минутах
+	BreakDuration    int            `gorm:"not null" json:"breakDuration"`            // в минутах
+	Cycles           int            `gorm:"not null;default:0" json:"cycles"`         // 0 — без ограничения
+	LongBreak        int            `gorm:"not null;default:0" json:"longBreak"`      // в минутах
+	LongBreakEvery   int            `gorm:"not null;default:0" json:"longBreakEvery"` // длинный перерыв после каждого N-го цикла
+	PhasePlan        PhasePlan      `gorm:"type:jsonb" json:"phasePlan,omitempty"`    // явная последовательность фаз
 	GroupName        *string        `gorm:"type:varchar(255)" json:"groupName"`
 	IsPrivate        bool           `gorm:"not null;default:false" json:"isPrivate"`
+	RequireApproval  bool           `gorm:"not null;default:false" json:"requireApproval"`
+	MaxParticipants  int            `gorm:"not null;default:0" json:"maxParticipants"` // 0 — без ограничения (сессии до появления лимита)
 	CreatorID        string         `gorm:"type:varchar(36);not null;index:idx_creator_id" json:"creatorId"`
 	InviteLink       string         `gorm:"type:varchar(50);uniqueIndex:idx_invite_link;not null" json:"inviteLink"`
 	TelegramChatID   *int64         `gorm:"index:idx_telegram_chat_id" json:"telegramChatId,omitempty"` // ID чата в Telegram API
 	TelegramChatLink *string        `gorm:"type:varchar(500)" json:"telegramChatLink,omitempty"`        // Ссылка на чат в Telegram
+	SeriesID         *string        `gorm:"type:varchar(36);index:idx_sessions_series_id" json:"seriesId,omitempty"`
+	ScheduledAt      *time.Time     `gorm:"index:idx_scheduled_at" json:"scheduledAt,omitempty"` // плановое время автоматического старта
+	ReminderSentAt   *time.Time     `json:"-"`                                                   // напоминание о скором старте уже разослано
+	StartPolicy      StartPolicy    `gorm:"type:varchar(20);not null;default:'manual'" json:"startPolicy"`
+	StartQuorum      int            `gorm:"not null;default:0" json:"startQuorum,omitempty"` // для политики quorum
+	StartCountdown   *int           `json:"startCountdown,omitempty"`                        // в секундах, nil — DefaultStartCountdown
+	AutoStartAt      *time.Time     `json:"autoStartAt,omitempty"`                           // идет обратный отсчет до автостарта
 	StartedAt        *time.Time     `json:"startedAt"`
 	CompletedAt      *time.Time     `json:"completedAt"`
 	PausedAt         *time.Time     `json:"pausedAt"`
 	TotalPauseTime   int64          `gorm:"not null;default:0" json:"totalPauseTime"` // в миллисекундах
 	CurrentCycle     int            `gorm:"not null;default:0" json:"currentCycle"`
+	PhaseIndex       int            `gorm:"not null;default:0" json:"phaseIndex"` // номер текущей фазы в плане (с нуля)
+	CurrentPhase     SessionPhase   `gorm:"type:varchar(20);not null;default:''" json:"currentPhase,omitempty"`
+	PhaseStartedAt   *time.Time     `json:"phaseStartedAt,omitempty"`
+	PhaseEndsAt      *time.Time     `gorm:"index:idx_phase_ends_at" json:"phaseEndsAt,omitempty"` // дедлайн текущей фазы, по нему движок переключает фазы
 	CreatedAt        time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_created_at" json:"createdAt"`
 	UpdatedAt        time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
@@ -54,14 +135,72 @@ func (Session) TableName() string {
 	return "sessions"
 }
 
+// CountdownDuration возвращает длительность обратного отсчета перед автостартом
+func (s *Session) CountdownDuration() time.Duration {
+	if s.StartCountdown == nil {
+		return DefaultStartCountdown * time.Second
+	}
+	return time.Duration(*s.StartCountdown) * time.Second
+}
+
+// SessionSettings — параметры новой сессии, задаваемые создателем
+type SessionSettings struct {
+	Mode           SessionMode
+	Tasks          []string
+	FocusDuration  int // в минутах
+	BreakDuration  int // в минутах
+	GroupName      *string
+	IsPrivate      bool
+	Approval       bool
+	ScheduledAt    *time.Time  // nil — сессию запускает создатель вручную
+	SeriesID       *string     // экземпляр серии SessionSeries
+	StartPolicy    StartPolicy // пусто — StartPolicyManual
+	StartQuorum    int
+	StartCountdown *int // в секундах, nil — DefaultStartCountdown
+	Capacity       int  // максимум участников, 0 — лимит из конфигурации
+	Plan           CyclePlan
+}
+
+// TaskCopyMode — какие задачи исходной сессии переносятся в ее повтор
+type TaskCopyMode string
+
+const (
+	TaskCopyAll        TaskCopyMode = "all"
+	TaskCopyIncomplete TaskCopyMode = "incomplete"
+	TaskCopyNone       TaskCopyMode = "none"
+)
+
+// CloneOptions — параметры повтора сессии из истории
+type CloneOptions struct {
+	Tasks    TaskCopyMode // пусто — TaskCopyIncomplete
+	Reinvite bool         // разослать прежним участникам приглашение в новую сессию
+}
+
+// SessionPhaseSegment — непрерывный отрезок фазы без пауз.
+// По отрезкам считается фактическое время фокуса и перерывов; EndedAt == nil у текущего отрезка
+type SessionPhaseSegment struct {
+	ID        string       `gorm:"type:varchar(36);primaryKey" json:"id"`
+	SessionID string       `gorm:"type:varchar(36);not null;index:idx_phase_segments_session_id" json:"sessionId"`
+	Phase     SessionPhase `gorm:"type:varchar(20);not null" json:"phase"`
+	Cycle     int          `gorm:"not null" json:"cycle"`
+	StartedAt time.Time    `gorm:"not null" json:"startedAt"`
+	EndedAt   *time.Time   `json:"endedAt"`
+}
+
+func (SessionPhaseSegment) TableName() string {
+	return "session_phase_segments"
+}
+
 type Participant struct {
 	SessionID string     `gorm:"type:varchar(36);primaryKey;index:idx_session_id" json:"sessionId"`
 	UserID    string     `gorm:"type:varchar(36);primaryKey;index:idx_user_id" json:"userId"`
 	UserName  string     `gorm:"type:varchar(255);not null" json:"userName"`
 	AvatarURL *string    `gorm:"type:text" json:"avatarUrl"`
 	IsReady   bool       `gorm:"not null;default:false" json:"isReady"`
+	IsCoHost  bool       `gorm:"not null;default:false" json:"isCoHost"`
 	JoinedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"joinedAt"`
 	LeftAt    *time.Time `json:"leftAt,omitempty"`
+	BannedAt  *time.Time `json:"bannedAt,omitempty"` // исключен создателем без права вернуться
 
 	// Relations
 	Session *Session `gorm:"foreignKey:SessionID" json:"session,omitempty"`
@@ -72,6 +211,20 @@ func (Participant) TableName() string {
 	return "session_participants"
 }
 
+// SessionAttendance — интервал присутствия участника в сессии (от входа до выхода).
+// При повторном входе открывается новый интервал; LeftAt == nil у текущего
+type SessionAttendance struct {
+	ID        string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	SessionID string     `gorm:"type:varchar(36);not null;index:idx_attendance_session_user" json:"sessionId"`
+	UserID    string     `gorm:"type:varchar(36);not null;index:idx_attendance_session_user" json:"userId"`
+	JoinedAt  time.Time  `gorm:"not null" json:"joinedAt"`
+	LeftAt    *time.Time `json:"leftAt"`
+}
+
+func (SessionAttendance) TableName() string {
+	return "session_attendance"
+}
+
 type Task struct {
 	ID          string         `gorm:"type:varchar(36);primaryKey" json:"id"`
 	SessionID   string         `gorm:"type:varchar(36);not null;index:idx_session_id" json:"sessionId"`
@@ -99,8 +252,12 @@ type SessionReport struct {
 	FocusTime       int                 `json:"focusTime"` // в минутах
 	BreakTime       int                 `json:"breakTime"` // в минутах
 	CyclesCompleted int                 `json:"cyclesCompleted"`
+	CyclesPlanned   int                 `json:"cyclesPlanned"` // 0 — план без ограничения
+	PlanCompleted   bool                `json:"planCompleted"` // сессия прошла план целиком
 	Participants    []ParticipantReport `json:"participants"`
 	CompletedAt     time.Time           `json:"completedAt"`
+
+	Achievements []*UserAchievement `json:"achievements,omitempty"` // значки, полученные участниками за эту сессию
 }
 
 type ParticipantReport struct {
@@ -108,7 +265,30 @@ type ParticipantReport struct {
 	UserName       string  `json:"userName"`
 	AvatarURL      *string `json:"avatarUrl"`
 	TasksCompleted int     `json:"tasksCompleted"`
-	FocusTime      int     `json:"focusTime"` // в минутах
+	TasksTotal     int     `json:"tasksTotal"` // задачи, закрепленные за участником
+	FocusTime      int     `json:"focusTime"`  // в минутах
+}
+
+// SessionResult — вклад участника в завершенную сессию; журнал хранится в session_leaderboard, по нему
+// строятся лидерборды за период. Очки в журнал не пишутся: их считает стратегия из конфига при чтении.
+// Запись по паре сессия/участник одна, поэтому повторное завершение не учитывается в статистике дважды
+type SessionResult struct {
+	SessionID      string    `gorm:"type:varchar(36);primaryKey" json:"sessionId"`
+	UserID         string    `gorm:"type:varchar(36);primaryKey" json:"userId"`
+	TasksCompleted int       `gorm:"not null;default:0" json:"tasksCompleted"`
+	TasksTotal     int       `gorm:"not null;default:0" json:"tasksTotal"`
+	FocusTime      int       `gorm:"not null;default:0" json:"focusTime"` // в минутах
+	CompletedAt    time.Time `gorm:"not null" json:"completedAt"`
+	UpdatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+func (SessionResult) TableName() string {
+	return "session_leaderboard"
+}
+
+// Perfect сообщает, что участник выполнил все закрепленные за ним задачи; без задач сессия идеальной не считается
+func (r *SessionResult) Perfect() bool {
+	return r.TasksTotal > 0 && r.TasksCompleted >= r.TasksTotal
 }
 
 // ParticipantProgress represents real-time progress of a participant
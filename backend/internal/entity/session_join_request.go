@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+type JoinRequestStatus string
+
+const (
+	JoinRequestStatusPending  JoinRequestStatus = "pending"
+	JoinRequestStatusApproved JoinRequestStatus = "approved"
+	JoinRequestStatusDenied   JoinRequestStatus = "denied"
+)
+
+// SessionJoinRequest — заявка на вход в сессию с RequireApproval.
+// Рассматривают создатель и со-ведущие: в приложении или кнопками в личных сообщениях Telegram
+type SessionJoinRequest struct {
+	ID         string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	SessionID  string            `gorm:"type:varchar(36);not null;index:idx_join_requests_session_id" json:"sessionId"`
+	UserID     string            `gorm:"type:varchar(36);not null" json:"userId"`
+	Status     JoinRequestStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ReviewedBy *string           `gorm:"type:varchar(36)" json:"reviewedBy,omitempty"`
+	CreatedAt  time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+	ReviewedAt *time.Time        `json:"reviewedAt,omitempty"`
+}
+
+func (SessionJoinRequest) TableName() string {
+	return "session_join_requests"
+}
+
+// JoinRequestDecision — итог рассмотрения заявки
+type JoinRequestDecision struct {
+	Request  *SessionJoinRequest
+	Session  *Session // сессия после входа заявителя; nil, если заявка отклонена или заявитель в листе ожидания
+	Waitlist int      // место в листе ожидания, если свободных мест не было
+}
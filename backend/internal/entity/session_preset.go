@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// TaskTitles — список задач по умолчанию; хранится в session_presets.tasks как JSON
+type TaskTitles []string
+
+func (t TaskTitles) Value() (driver.Value, error) {
+	return jsonColumnValue(t, len(t) == 0)
+}
+
+func (t *TaskTitles) Scan(value interface{}) error {
+	*t = nil
+	return scanJSONColumn(value, t)
+}
+
+// SessionPreset — именованный набор настроек новой сессии, сохраненный пользователем.
+// Сессия из пресета создается с его настройками, отдельные поля запроса их перекрывают
+type SessionPreset struct {
+	ID             string      `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID         string      `gorm:"type:varchar(36);not null;uniqueIndex:idx_session_presets_user_name" json:"userId"`
+	Name           string      `gorm:"type:varchar(100);not null;uniqueIndex:idx_session_presets_user_name" json:"name"`
+	Mode           SessionMode `gorm:"type:session_mode;not null" json:"mode"`
+	FocusDuration  int         `gorm:"not null" json:"focusDuration"` // в минутах
+	BreakDuration  int         `gorm:"not null" json:"breakDuration"` // в минутах
+	Cycles         int         `gorm:"not null;default:0" json:"cycles"`
+	LongBreak      int         `gorm:"not null;default:0" json:"longBreak"`
+	LongBreakEvery int         `gorm:"not null;default:0" json:"longBreakEvery"`
+	PhasePlan      PhasePlan   `gorm:"type:jsonb" json:"phasePlan,omitempty"`
+	IsPrivate      bool        `gorm:"not null;default:false" json:"isPrivate"`
+	GroupName      *string     `gorm:"type:varchar(255)" json:"groupName"`
+	Tasks          TaskTitles  `gorm:"type:jsonb" json:"tasks"`
+	CreatedAt      time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt      time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+func (SessionPreset) TableName() string {
+	return "session_presets"
+}
+
+// Plan возвращает план циклов пресета
+func (p *SessionPreset) Plan() CyclePlan {
+	return CyclePlan{
+		Cycles:         p.Cycles,
+		LongBreak:      p.LongBreak,
+		LongBreakEvery: p.LongBreakEvery,
+		Phases:         p.PhasePlan,
+	}
+}
+
+// Settings возвращает настройки новой сессии по пресету
+func (p *SessionPreset) Settings() SessionSettings {
+	tasks := make([]string, len(p.Tasks))
+	copy(tasks, p.Tasks)
+
+	return SessionSettings{
+		Mode:          p.Mode,
+		Tasks:         tasks,
+		FocusDuration: p.FocusDuration,
+		BreakDuration: p.BreakDuration,
+		GroupName:     p.GroupName,
+		IsPrivate:     p.IsPrivate,
+		Plan:          p.Plan(),
+	}
+}
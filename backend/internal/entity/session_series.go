@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionSeries — повторяющаяся групповая сессия. Экземпляры (обычные Session с SeriesID)
+// создаются заранее по правилу повторения; InviteLink серии не меняется и ведет на ближайший экземпляр
+type SessionSeries struct {
+	ID             string         `gorm:"type:varchar(36);primaryKey" json:"id"`
+	CreatorID      string         `gorm:"type:varchar(36);not null;index:idx_series_creator_id" json:"creatorId"`
+	GroupName      *string        `gorm:"type:varchar(255)" json:"groupName"`
+	IsPrivate      bool           `gorm:"not null;default:false" json:"isPrivate"`
+	FocusDuration  int            `gorm:"not null" json:"focusDuration"`                        // в минутах
+	BreakDuration  int            `gorm:"not null" json:"breakDuration"`                        // в минутах
+	RRule          string         `gorm:"column:rrule;type:varchar(255);not null" json:"rrule"` // FREQ=DAILY|WEEKLY;INTERVAL;BYDAY;UNTIL
+	Timezone       string         `gorm:"type:varchar(64);not null" json:"timezone"`            // IANA, например Europe/Moscow
+	StartsAt       time.Time      `gorm:"not null" json:"startsAt"`                             // первое повторение, задает время суток
+	InviteLink     string         `gorm:"type:varchar(50);uniqueIndex:idx_series_invite_link;not null" json:"inviteLink"`
+	GeneratedUntil *time.Time     `json:"-"` // экземпляры созданы на все повторения до этого момента включительно
+	CreatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (SessionSeries) TableName() string {
+	return "session_series"
+}
+
+// SessionSeriesSubscriber — пользователь, которого автоматически добавляют в каждый новый экземпляр серии
+type SessionSeriesSubscriber struct {
+	SeriesID     string    `gorm:"type:varchar(36);primaryKey" json:"seriesId"`
+	UserID       string    `gorm:"type:varchar(36);primaryKey;index:idx_series_subscribers_user_id" json:"userId"`
+	SubscribedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"subscribedAt"`
+}
+
+func (SessionSeriesSubscriber) TableName() string {
+	return "session_series_subscribers"
+}
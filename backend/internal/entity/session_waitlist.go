@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// SessionWaitlistEntry — пользователь, ожидающий места в заполненной сессии.
+// Места освобождаются по очереди записи (CreatedAt)
+type SessionWaitlistEntry struct {
+	SessionID string    `gorm:"type:varchar(36);primaryKey" json:"sessionId"`
+	UserID    string    `gorm:"type:varchar(36);primaryKey;index:idx_session_waitlist_user_id" json:"userId"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+}
+
+func (SessionWaitlistEntry) TableName() string {
+	return "session_waitlist"
+}
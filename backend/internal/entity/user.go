@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,6 +12,8 @@ type User struct {
 	Name           string         `gorm:"type:varchar(255);not null" json:"name"`
 	AvatarURL      *string        `gorm:"type:text" json:"avatarUrl"`
 	TelegramUserID int64          `gorm:"uniqueIndex:idx_telegram_user_id;not null" json:"telegramUserId"`
+	Timezone       string         `gorm:"type:varchar(64);not null;default:''" json:"timezone"`        // IANA, пустой — UTC
+	InviteCode     *string        `gorm:"type:varchar(16);uniqueIndex:idx_users_invite_code" json:"-"` // приглашение в контакты, создается по запросу
 	CreatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
 	UpdatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -23,15 +26,118 @@ func (User) TableName() string {
 	return "users"
 }
 
+// UserSettings — изменяемые пользователем настройки; nil-поля не меняются
+type UserSettings struct {
+	Timezone *string // IANA, пустая строка — UTC
+}
+
+// Location возвращает часовой пояс пользователя, в котором считаются его дни; без пояса — UTC
+func (u *User) Location() *time.Location {
+	loc, err := LoadTimezone(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LoadTimezone возвращает часовой пояс по названию IANA (например, Europe/Moscow); пустое название — UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if name == "Local" {
+		return nil, InvalidArgument("timezone must be an IANA name")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, InvalidArgument("unknown timezone: " + name)
+	}
+	return loc, nil
+}
+
+// LocalDay возвращает календарный день момента времени в часовом поясе loc
+func LocalDay(at time.Time, loc *time.Location) time.Time {
+	at = at.In(loc)
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 type UserStats struct {
-	UserID          string     `gorm:"type:varchar(36);primaryKey" json:"userId"`
-	TotalSessions   int        `gorm:"not null;default:0" json:"totalSessions"`
-	TotalFocusTime  int        `gorm:"not null;default:0" json:"totalFocusTime"` // в минутах
-	CurrentStreak   int        `gorm:"not null;default:0" json:"currentStreak"`  // в днях
-	LastSessionDate *time.Time `gorm:"type:date" json:"lastSessionDate,omitempty"`
-	UpdatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	UserID              string     `gorm:"type:varchar(36);primaryKey" json:"userId"`
+	TotalSessions       int        `gorm:"not null;default:0" json:"totalSessions"`
+	TotalFocusTime      int        `gorm:"not null;default:0" json:"totalFocusTime"` // в минутах
+	TotalTasksCompleted int        `gorm:"not null;default:0" json:"totalTasksCompleted"`
+	CurrentStreak       int        `gorm:"not null;default:0" json:"currentStreak"` // в днях
+	PerfectStreak       int        `gorm:"not null;default:0" json:"perfectStreak"` // сессии подряд со всеми выполненными задачами
+	LastSessionDate     *time.Time `gorm:"type:date" json:"lastSessionDate,omitempty"`
+	UpdatedAt           time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
 }
 
 func (UserStats) TableName() string {
 	return "user_stats"
 }
+
+// AddSession учитывает в статистике результат завершенной сессии. Дни считаются в часовом поясе пользователя loc:
+// серия растет, если предыдущая сессия была накануне, и начинается заново после пропущенного дня
+func (s *UserStats) AddSession(result *SessionResult, loc *time.Location) {
+	s.TotalSessions++
+	s.TotalFocusTime += result.FocusTime
+	s.TotalTasksCompleted += result.TasksCompleted
+	if result.Perfect() {
+		s.PerfectStreak++
+	} else {
+		s.PerfectStreak = 0
+	}
+
+	day := LocalDay(result.CompletedAt, loc)
+	if s.LastSessionDate == nil {
+		s.CurrentStreak = 1
+		s.LastSessionDate = &day
+		return
+	}
+
+	last := s.lastSessionDay()
+	switch {
+	case !day.After(last):
+		// Тот же день — серия не меняется
+		return
+	case day.Equal(last.AddDate(0, 0, 1)):
+		s.CurrentStreak++
+	default:
+		s.CurrentStreak = 1
+	}
+	s.LastSessionDate = &day
+}
+
+// BuildStats считает статистику пользователей заново по итогам их сессий в хронологическом порядке.
+// loc возвращает часовой пояс пользователя, в котором считаются его дни
+func BuildStats(results []*SessionResult, loc func(userID string) *time.Location) map[string]*UserStats {
+	ordered := make([]*SessionResult, len(results))
+	copy(ordered, results)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CompletedAt.Before(ordered[j].CompletedAt)
+	})
+
+	stats := make(map[string]*UserStats)
+	for _, result := range ordered {
+		userStats, exists := stats[result.UserID]
+		if !exists {
+			userStats = &UserStats{UserID: result.UserID}
+			stats[result.UserID] = userStats
+		}
+		userStats.AddSession(result, loc(result.UserID))
+	}
+	return stats
+}
+
+// StreakLapsed сообщает, что серия прервана: к дню today (календарный день пользователя) пропущен хотя бы один день
+func (s *UserStats) StreakLapsed(today time.Time) bool {
+	if s.CurrentStreak == 0 || s.LastSessionDate == nil {
+		return false
+	}
+	return s.lastSessionDay().AddDate(0, 0, 1).Before(today)
+}
+
+// lastSessionDay возвращает день последней сессии; столбец date хранит его без часового пояса
+func (s *UserStats) lastSessionDay() time.Time {
+	return LocalDay(*s.LastSessionDate, s.LastSessionDate.Location())
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserStatsAddSession(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC)
+	}
+	perfect := func(at time.Time) *SessionResult {
+		return &SessionResult{TasksCompleted: 2, TasksTotal: 2, FocusTime: 25, CompletedAt: at}
+	}
+	partial := func(at time.Time) *SessionResult {
+		return &SessionResult{TasksCompleted: 1, TasksTotal: 2, FocusTime: 25, CompletedAt: at}
+	}
+	noTasks := func(at time.Time) *SessionResult {
+		return &SessionResult{FocusTime: 25, CompletedAt: at}
+	}
+
+	tests := []struct {
+		name        string
+		loc         *time.Location
+		results     []*SessionResult
+		wantStreak  int
+		wantPerfect int
+		wantLast    time.Time
+	}{
+		{name: "first session", loc: time.UTC, results: []*SessionResult{perfect(day(1, 10))}, wantStreak: 1, wantPerfect: 1, wantLast: day(1, 0)},
+		{name: "same day keeps streak", loc: time.UTC, results: []*SessionResult{perfect(day(1, 10)), perfect(day(1, 20))}, wantStreak: 1, wantPerfect: 2, wantLast: day(1, 0)},
+		{name: "next day grows streak", loc: time.UTC, results: []*SessionResult{partial(day(1, 10)), partial(day(2, 10)), partial(day(3, 10))}, wantStreak: 3, wantPerfect: 0, wantLast: day(3, 0)},
+		{name: "missed day restarts streak", loc: time.UTC, results: []*SessionResult{perfect(day(1, 10)), perfect(day(3, 10))}, wantStreak: 1, wantPerfect: 2, wantLast: day(3, 0)},
+		{name: "older session does not move last day", loc: time.UTC, results: []*SessionResult{perfect(day(3, 10)), perfect(day(2, 10))}, wantStreak: 1, wantPerfect: 2, wantLast: day(3, 0)},
+		{name: "partial session breaks perfect streak", loc: time.UTC, results: []*SessionResult{perfect(day(1, 10)), partial(day(1, 11)), perfect(day(1, 12))}, wantStreak: 1, wantPerfect: 1, wantLast: day(1, 0)},
+		{name: "session without tasks is not perfect", loc: time.UTC, results: []*SessionResult{perfect(day(1, 10)), noTasks(day(1, 11))}, wantStreak: 1, wantPerfect: 0, wantLast: day(1, 0)},
+		// 22:00 UTC 1 марта — уже 2 марта в Москве
+		{name: "days in user timezone", loc: moscow, results: []*SessionResult{perfect(day(1, 10)), perfect(day(1, 22))}, wantStreak: 2, wantPerfect: 2, wantLast: day(2, 0)},
+		{name: "same local day in timezone", loc: moscow, results: []*SessionResult{perfect(day(1, 22)), perfect(day(2, 10))}, wantStreak: 1, wantPerfect: 2, wantLast: day(2, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &UserStats{}
+			for _, result := range tt.results {
+				stats.AddSession(result, tt.loc)
+			}
+
+			if stats.TotalSessions != len(tt.results) {
+				t.Errorf("TotalSessions = %d, want %d", stats.TotalSessions, len(tt.results))
+			}
+			if stats.TotalFocusTime != 25*len(tt.results) {
+				t.Errorf("TotalFocusTime = %d, want %d", stats.TotalFocusTime, 25*len(tt.results))
+			}
+			if stats.CurrentStreak != tt.wantStreak {
+				t.Errorf("CurrentStreak = %d, want %d", stats.CurrentStreak, tt.wantStreak)
+			}
+			if stats.PerfectStreak != tt.wantPerfect {
+				t.Errorf("PerfectStreak = %d, want %d", stats.PerfectStreak, tt.wantPerfect)
+			}
+			if stats.LastSessionDate == nil || !stats.LastSessionDate.Equal(tt.wantLast) {
+				t.Errorf("LastSessionDate = %v, want %v", stats.LastSessionDate, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestUserStatsStreakLapsed(t *testing.T) {
+	last := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		stats UserStats
+		today time.Time
+		want  bool
+	}{
+		{name: "no streak", stats: UserStats{LastSessionDate: &last}, today: last.AddDate(0, 0, 5), want: false},
+		{name: "session today", stats: UserStats{CurrentStreak: 3, LastSessionDate: &last}, today: last, want: false},
+		{name: "session yesterday", stats: UserStats{CurrentStreak: 3, LastSessionDate: &last}, today: last.AddDate(0, 0, 1), want: false},
+		{name: "missed a day", stats: UserStats{CurrentStreak: 3, LastSessionDate: &last}, today: last.AddDate(0, 0, 2), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.StreakLapsed(tt.today); got != tt.want {
+				t.Errorf("StreakLapsed(%v) = %v, want %v", tt.today, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildStats(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	// Итоги идут не по порядку: серия считается по времени завершения
+	results := []*SessionResult{
+		{UserID: "a", TasksCompleted: 1, TasksTotal: 1, FocusTime: 25, CompletedAt: day(3)},
+		{UserID: "b", FocusTime: 50, CompletedAt: day(1)},
+		{UserID: "a", TasksCompleted: 1, TasksTotal: 1, FocusTime: 25, CompletedAt: day(1)},
+		{UserID: "a", TasksCompleted: 0, TasksTotal: 1, FocusTime: 25, CompletedAt: day(2)},
+	}
+
+	stats := BuildStats(results, func(string) *time.Location { return time.UTC })
+
+	a := stats["a"]
+	if a == nil || a.UserID != "a" || a.TotalSessions != 3 || a.CurrentStreak != 3 || a.PerfectStreak != 1 {
+		t.Errorf("stats of a = %+v, want 3 sessions, streak 3, perfect streak 1", a)
+	}
+	b := stats["b"]
+	if b == nil || b.TotalFocusTime != 50 || b.CurrentStreak != 1 {
+		t.Errorf("stats of b = %+v, want 50 minutes, streak 1", b)
+	}
+	if results[0].UserID != "a" || !results[0].CompletedAt.Equal(day(3)) {
+		t.Error("BuildStats reordered the caller's results")
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 type AuthService interface {
-	Login(initData, deviceID string) (*entity.AuthTokens, *entity.User, error)
+	Login(initData, deviceID, timezone string) (*entity.AuthTokens, *entity.User, error)
 	RefreshToken(refreshToken string) (*entity.AuthTokens, error)
 	ValidateToken(token string) (string, error) // возвращает userID
 	Logout(userID string) error
@@ -0,0 +1,12 @@
+package interfaces
+
+import "github.com/rnegic/synchronous/internal/entity"
+
+// EventBackplane — шина realtime-событий между инстансами бэкенда.
+// Событие, опубликованное на одном узле, доставляется подписчикам на всех узлах (включая сам узел)
+// в одном и том же порядке. Для событий сессии бэкплейн назначает сквозной Seq
+type EventBackplane interface {
+	Publish(event *entity.RealtimeEvent) error
+	Subscribe(handler func(event *entity.RealtimeEvent))
+	Close() error
+}
@@ -7,4 +7,5 @@ import (
 type LeaderboardService interface {
 	GetSessionLeaderboard(sessionID string, userID string) ([]*entity.LeaderboardEntry, error)
 	GetGlobalLeaderboard(userID string, period entity.LeaderboardPeriod, limit int) ([]*entity.LeaderboardEntry, error)
+	GetFriendsLeaderboard(userID string, period entity.LeaderboardPeriod) ([]*entity.LeaderboardEntry, error) // пользователь и его контакты
 }
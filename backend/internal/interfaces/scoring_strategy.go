@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/rnegic/synchronous/internal/entity"
+
+// ScoringStrategy считает очки записи лидерборда. Выбирается в конфиге (APP.SCORING_STRATEGY)
+// и применяется в LeaderboardService одинаково для всех хранилищ
+type ScoringStrategy interface {
+	Name() string
+	Score(entry *entity.LeaderboardEntry) entity.ScoreBreakdown // по задачам, фокусу и серии дней записи
+}
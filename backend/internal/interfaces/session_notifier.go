@@ -0,0 +1,7 @@
+package interfaces
+
+// SessionNotifier доставляет realtime-события клиентам (реализуется WebSocket-хабом)
+type SessionNotifier interface {
+	SendToSession(sessionID string, event string, data interface{})
+	SendToUser(userID string, event string, data interface{})
+}
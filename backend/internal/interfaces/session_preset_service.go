@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+type SessionPresetService interface {
+	CreatePreset(userID string, preset *entity.SessionPreset) (*entity.SessionPreset, error)
+	GetPresets(userID string) ([]*entity.SessionPreset, error)
+	GetPreset(presetID string, userID string) (*entity.SessionPreset, error) // только свои пресеты
+	UpdatePreset(presetID string, userID string, preset *entity.SessionPreset) (*entity.SessionPreset, error)
+	DeletePreset(presetID string, userID string) error
+}
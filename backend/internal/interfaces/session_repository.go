@@ -9,15 +9,75 @@ import (
 type SessionRepository interface {
 	Create(session *entity.Session) error
 	GetByID(id string) (*entity.Session, error)
+	GetByIDs(ids []string) ([]*entity.Session, error)
 	GetByInviteLink(inviteLink string) (*entity.Session, error)
 	GetActiveByUserID(userID string) (*entity.Session, error)
 	GetHistory(userID string, page, limit int) ([]*entity.Session, int, error)
 	GetAll() ([]*entity.Session, error)
 	Update(session *entity.Session) error
+	Delete(id string) error // удаляет сессию вместе с задачами и участниками
 	AddParticipant(sessionID string, participant *entity.Participant) error
+	JoinWithinCapacity(sessionID string, participant *entity.Participant) (bool, error) // false — мест нет; вышедший участник возвращается
 	RemoveParticipant(sessionID string, userID string) error
 	UpdateParticipantReady(sessionID string, userID string, isReady bool) error
+	UpdateParticipantLeftAt(sessionID string, userID string, leftAt *time.Time) error // nil — участник снова в сессии
+	UpdateParticipantBannedAt(sessionID string, userID string, bannedAt *time.Time) error
+	UpdateParticipantCoHost(sessionID string, userID string, isCoHost bool) error
+	UpdateCreator(sessionID string, creatorID string) error
 	GetSessionsByStatus(status entity.SessionStatus) ([]*entity.Session, error)
+	GetSessionsWithPhaseEndingBefore(deadline time.Time) ([]*entity.Session, error)   // активные сессии с истекшей фазой
+	UpdatePhase(session *entity.Session, previousPhaseEndsAt time.Time) (bool, error) // compare-and-set по дедлайну фазы
+	UpdateStatus(sessionID string, from, to entity.SessionStatus) (bool, error)       // compare-and-set по статусу
+	Start(session *entity.Session) (bool, error)                                      // pending -> active с первой фазой; compare-and-set по статусу
+	Pause(sessionID string, pausedAt time.Time) (bool, error)                         // active -> paused; фазу не трогает
+	Resume(session *entity.Session, pausedAt time.Time) (bool, error)                 // paused -> active со сдвинутой фазой; compare-and-set по началу паузы
+	Finish(session *entity.Session, from entity.SessionStatus) (bool, error)          // from -> completed/cancelled без фазы; compare-and-set по статусу
+	GetScheduledSessionsBefore(deadline time.Time) ([]*entity.Session, error)         // ожидающие сессии, запланированные не позже deadline
+	MarkReminderSent(sessionID string, sentAt time.Time) (bool, error)                // false — напоминание уже отправлено
+	GetUpcomingBySeriesID(seriesID string) ([]*entity.Session, error)                 // ожидающие экземпляры серии по времени старта
+	GetPendingWithStartPolicy() ([]*entity.Session, error)                            // ожидающие сессии с автостартом по готовности
+	UpdateAutoStartAt(sessionID string, previous, next *time.Time) (bool, error)      // compare-and-set обратного отсчета
+	CancelAutoStart(sessionID string, startsAt time.Time) (bool, error)               // отменяет отсчет до startsAt и сбрасывает готовность участников
+}
+
+type SessionSeriesRepository interface {
+	Create(series *entity.SessionSeries) error
+	GetByID(id string) (*entity.SessionSeries, error)
+	GetByInviteLink(inviteLink string) (*entity.SessionSeries, error)
+	GetAll() ([]*entity.SessionSeries, error)
+	Delete(id string) error
+	AdvanceGeneratedUntil(seriesID string, previous *time.Time, next time.Time) (bool, error) // compare-and-set: генерирует экземпляры один инстанс
+	AddSubscriber(subscriber *entity.SessionSeriesSubscriber) error
+	RemoveSubscriber(seriesID string, userID string) error
+	GetSubscribers(seriesID string) ([]*entity.SessionSeriesSubscriber, error)
+}
+
+type SessionWaitlistRepository interface {
+	Add(entry *entity.SessionWaitlistEntry) error // повторная запись не меняет место в очереди
+	Remove(sessionID string, userID string) error
+	GetBySessionID(sessionID string) ([]*entity.SessionWaitlistEntry, error) // в порядке очереди
+}
+
+type SessionJoinRequestRepository interface {
+	Create(request *entity.SessionJoinRequest) error
+	GetByID(id string) (*entity.SessionJoinRequest, error)
+	GetPending(sessionID string, userID string) (*entity.SessionJoinRequest, error) // nil — нерассмотренной заявки нет
+	GetPendingBySessionID(sessionID string) ([]*entity.SessionJoinRequest, error)
+	Resolve(id string, status entity.JoinRequestStatus, reviewerID string, at time.Time) (bool, error) // false — заявку уже рассмотрели
+	Reopen(id string, status entity.JoinRequestStatus) (bool, error)                                   // откат Resolve: false — статус заявки уже другой
+}
+
+type PhaseSegmentRepository interface {
+	Create(segment *entity.SessionPhaseSegment) error
+	CloseOpen(sessionID string, endedAt time.Time) error // закрывает текущий (открытый) отрезок сессии
+	GetBySessionID(sessionID string) ([]*entity.SessionPhaseSegment, error)
+}
+
+type AttendanceRepository interface {
+	Create(attendance *entity.SessionAttendance) error
+	Close(sessionID string, userID string, leftAt time.Time) error // закрывает открытый интервал участника
+	CloseAll(sessionID string, leftAt time.Time) error             // закрывает открытые интервалы всех участников
+	GetBySessionID(sessionID string) ([]*entity.SessionAttendance, error)
 }
 
 type TaskRepository interface {
@@ -36,8 +96,9 @@ type MessageRepository interface {
 	GetByID(id string) (*entity.Message, error)
 }
 
+// LeaderboardRepository возвращает задачи и фокус участников без счета и мест: их считает LeaderboardService
 type LeaderboardRepository interface {
 	GetSessionLeaderboard(sessionID string) ([]*entity.LeaderboardEntry, error)
-	GetGlobalLeaderboard(period entity.LeaderboardPeriod, limit int) ([]*entity.LeaderboardEntry, error)
-	UpdateUserScore(userID string, sessionID string, score int) error
+	GetGlobalLeaderboard(since *time.Time) ([]*entity.LeaderboardEntry, error)                  // since — начало окна периода, nil — за все время
+	GetUsersLeaderboard(userIDs []string, since *time.Time) ([]*entity.LeaderboardEntry, error) // все перечисленные пользователи, без итогов в окне — с нулями
 }
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+type SessionSeriesService interface {
+	CreateSeries(userID string, series *entity.SessionSeries) (*entity.SessionSeries, error)
+	GetSeries(seriesID string, userID string) (*entity.SessionSeries, []*entity.Session, error) // серия и ее ожидающие экземпляры
+	DeleteSeries(seriesID string, userID string) (cancelledSessionIDs []string, err error)
+	Subscribe(seriesID string, userID string) error
+	Unsubscribe(seriesID string, userID string) error
+	JoinByInviteLink(inviteLink string, userID string, subscribe bool) (*entity.Session, error) // присоединяет к ближайшему экземпляру
+	GenerateOccurrences() error
+}
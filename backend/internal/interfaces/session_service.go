@@ -1,23 +1,41 @@
 package interfaces
 
 import (
+	"time"
+
 	"github.com/rnegic/synchronous/internal/entity"
 )
 
 type SessionService interface {
-	CreateSession(userID string, mode entity.SessionMode, tasks []string, focusDuration, breakDuration int, groupName *string, isPrivate bool) (*entity.Session, error)
+	CreateSession(userID string, settings entity.SessionSettings) (*entity.Session, error)
+	CloneSession(sessionID string, userID string, options entity.CloneOptions) (clone *entity.Session, invitedUserIDs []string, err error)
 	GetSession(sessionID string, userID string) (*entity.Session, error)
 	GetActiveSession(userID string) (*entity.Session, error)
 	GetHistory(userID string, page, limit int) ([]*entity.Session, int, error)
 	GetPublicSessions(page, limit int) ([]*entity.Session, int, error)
 	JoinSession(sessionID string, userID string) (*entity.Session, error)
 	JoinByInviteLink(inviteLink string, userID string) (*entity.Session, error)
+	LeaveSession(sessionID string, userID string) (newCreatorID string, promotedUserIDs []string, err error)
+	RemoveParticipant(sessionID string, userID string, targetUserID string, ban bool) (promotedUserIDs []string, err error)
+	LeaveWaitlist(sessionID string, userID string) error
+	TransferOwnership(sessionID string, userID string, newCreatorID string) error
+	SetCoHost(sessionID string, userID string, targetUserID string, isCoHost bool) error
+	ListJoinRequests(sessionID string, userID string) ([]*entity.SessionJoinRequest, error)
+	ReviewJoinRequest(sessionID string, requestID string, reviewerID string, approve bool) (*entity.JoinRequestDecision, error)
+	HandleJoinRequestCallback(update interface{}) (*entity.JoinRequestDecision, error) // кнопки заявки в Telegram
 	SetReady(sessionID string, userID string, isReady bool) error
-	StartSession(sessionID string, userID string) error
+	StartSession(sessionID string, userID string, force bool) (lateUserIDs []string, err error)
+	AutoStartSession(sessionID string) (*entity.Session, error)
+	UpdateStartPolicy(sessionID string, userID string, policy entity.StartPolicy, quorum int, countdown *int) (*entity.Session, error)
+	CancelAutoStart(sessionID string, userID string) error
 	PauseSession(sessionID string, userID string) error
 	ResumeSession(sessionID string, userID string) error
 	CompleteSession(sessionID string, userID string) (*entity.SessionReport, error)
+	AutoCompleteSession(sessionID string, at time.Time, elapsed []*entity.SessionPhaseSegment) (*entity.SessionReport, error) // план циклов закончился
+	CancelSession(sessionID string, userID string) error
+	DeleteSession(sessionID string, userID string) error
 	GetSessionReport(sessionID string, userID string) (*entity.SessionReport, error)
+	RecomputeStats() (int, error) // пересчет статистики пользователей по истории; число учтенных сессий
 	DeleteChatAfterDiscussion(sessionID string, userID string) error
 	HandleChatCreated(update interface{}) error
 	UpdateTask(sessionID string, taskID string, userID string, completed bool) (*entity.Task, error)
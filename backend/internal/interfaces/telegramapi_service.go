@@ -7,6 +7,7 @@ type TelegramAPIService interface {
 	GetProfileByToken(accessToken string) (*telegramapi.BotInfo, error)
 	SendMessage(chatID int64, text string) error
 	SendMessageToUser(userID int64, message *telegramapi.SendMessageRequest) (*telegramapi.SendMessageResponse, error)
+	AnswerCallback(callbackID string, text string) error
 	GetChat(chatID int64) (*telegramapi.Chat, error)
 	GetChatByLink(chatLink string) (*telegramapi.Chat, error)
 	GetUserInfo(userID int64) (*telegramapi.TelegramUser, error)
@@ -1,12 +1,44 @@
 package interfaces
 
-import "github.com/rnegic/synchronous/internal/entity"
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
 
 type UserRepository interface {
 	Create(user *entity.User) error
 	GetByID(id string) (*entity.User, error)
+	GetByIDs(ids []string) ([]*entity.User, error) // найденные пользователи в любом порядке
 	GetByTelegramUserID(telegramUserID int64) (*entity.User, error)
+	GetByInviteCode(inviteCode string) (*entity.User, error)
 	Update(user *entity.User) error
+	SetInviteCode(userID string, inviteCode string) (bool, error) // false — код уже создан
 	UpdateStats(userID string, stats *entity.UserStats) error
 	GetStats(userID string) (*entity.UserStats, error)
+	GetSessionResults(userID string, from, to time.Time) ([]*entity.SessionResult, error)
+	RecordSessionResult(result *entity.SessionResult, loc *time.Location) (bool, error) // false — итог сессии уже учтен; loc — пояс для серии дней
+	RebuildStats(results []*entity.SessionResult) error                                 // дописывает итоги и пересчитывает статистику всех по журналу в одной транзакции
+	GetStreakTimezones() ([]string, error)                                              // часовые пояса пользователей с непрерванной серией
+	ResetLapsedStreaks(timezone string, before time.Time) (int, error)                  // обнуляет серии пояса с последней сессией раньше дня before
+}
+
+type ContactRepository interface {
+	AddSessionContacts(userIDs []string, at time.Time) error  // связывает участников общей сессии друг с другом
+	Follow(contact *entity.UserContact) error                 // существующий контакт не меняется
+	Delete(userID string, contactID string) (bool, error)     // false — такого контакта нет
+	GetByUserID(userID string) ([]*entity.UserContact, error) // сначала новые
+}
+
+type AchievementRepository interface {
+	Unlock(achievement *entity.UserAchievement) (bool, error)     // false — значок уже получен
+	GetByUserID(userID string) ([]*entity.UserAchievement, error) // в порядке получения
+}
+
+type SessionPresetRepository interface {
+	Create(preset *entity.SessionPreset) error
+	GetByID(id string) (*entity.SessionPreset, error)
+	GetByUserID(userID string) ([]*entity.SessionPreset, error) // по имени
+	Update(preset *entity.SessionPreset) error
+	Delete(id string) error
 }
@@ -6,5 +6,11 @@ import (
 
 type UserService interface {
 	GetProfile(userID string) (*entity.User, *entity.UserStats, error)
-	GetContacts(userID string) ([]*entity.User, error)
+	GetAchievements(userID string) ([]*entity.UserAchievement, error)
+	GetAnalytics(userID string, query entity.AnalyticsQuery) (*entity.FocusAnalytics, error)
+	GetContacts(userID string) ([]*entity.UserContact, error)
+	GetInviteCode(userID string) (string, error)
+	FollowUser(userID string, target entity.FollowTarget) (*entity.UserContact, error)
+	RemoveContact(userID string, contactID string) error
+	UpdateSettings(userID string, settings entity.UserSettings) (*entity.User, error)
 }
@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type achievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) interfaces.AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+func (r *achievementRepository) Unlock(achievement *entity.UserAchievement) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(achievement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *achievementRepository) GetByUserID(userID string) ([]*entity.UserAchievement, error) {
+	var achievements []*entity.UserAchievement
+	err := r.db.Where("user_id = ?", userID).
+		Order("unlocked_at ASC").
+		Find(&achievements).Error
+	if err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+)
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) interfaces.AttendanceRepository {
+	return &attendanceRepository{db: db}
+}
+
+func (r *attendanceRepository) Create(attendance *entity.SessionAttendance) error {
+	return r.db.Create(attendance).Error
+}
+
+func (r *attendanceRepository) Close(sessionID string, userID string, leftAt time.Time) error {
+	return r.db.Model(&entity.SessionAttendance{}).
+		Where("session_id = ? AND user_id = ? AND left_at IS NULL", sessionID, userID).
+		Update("left_at", leftAt).Error
+}
+
+func (r *attendanceRepository) CloseAll(sessionID string, leftAt time.Time) error {
+	return r.db.Model(&entity.SessionAttendance{}).
+		Where("session_id = ? AND left_at IS NULL", sessionID).
+		Update("left_at", leftAt).Error
+}
+
+func (r *attendanceRepository) GetBySessionID(sessionID string) ([]*entity.SessionAttendance, error) {
+	var intervals []*entity.SessionAttendance
+	err := r.db.Where("session_id = ?", sessionID).Order("joined_at ASC").Find(&intervals).Error
+	if err != nil {
+		return nil, err
+	}
+	return intervals, nil
+}
@@ -0,0 +1,64 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contactRepository struct {
+	db *gorm.DB
+}
+
+func NewContactRepository(db *gorm.DB) interfaces.ContactRepository {
+	return &contactRepository{db: db}
+}
+
+func (r *contactRepository) AddSessionContacts(userIDs []string, at time.Time) error {
+	contacts := make([]*entity.UserContact, 0, len(userIDs)*len(userIDs))
+	for _, userID := range userIDs {
+		for _, contactID := range userIDs {
+			if userID == contactID {
+				continue
+			}
+			contacts = append(contacts, &entity.UserContact{
+				UserID:    userID,
+				ContactID: contactID,
+				Source:    entity.ContactSourceSession,
+				CreatedAt: at,
+			})
+		}
+	}
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&contacts).Error
+}
+
+func (r *contactRepository) Follow(contact *entity.UserContact) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(contact).Error
+}
+
+func (r *contactRepository) Delete(userID string, contactID string) (bool, error) {
+	result := r.db.Where("user_id = ? AND contact_id = ?", userID, contactID).
+		Delete(&entity.UserContact{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *contactRepository) GetByUserID(userID string) ([]*entity.UserContact, error) {
+	var contacts []*entity.UserContact
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&contacts).Error
+	if err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
@@ -0,0 +1,230 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+)
+
+const (
+	realtimeEventsChannel = "synchronous_realtime_events"
+
+	// Postgres ограничивает payload NOTIFY 8000 байтами
+	maxNotifyPayloadSize = 7999
+
+	// Столько хранится тело события, не поместившегося в NOTIFY
+	storedPayloadTTL = 5 * time.Minute
+
+	listenMaxBackoff = 30 * time.Second
+)
+
+// eventBackplane рассылает события между инстансами через Postgres LISTEN/NOTIFY.
+// Публикация идёт через общий пул gorm, прослушивание — через отдельное pgx-соединение
+type eventBackplane struct {
+	db       *gorm.DB
+	dsn      string
+	handlers []func(event *entity.RealtimeEvent)
+	mu       sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewEventBackplane(db *gorm.DB, dsn string) interfaces.EventBackplane {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &eventBackplane{
+		db:     db,
+		dsn:    dsn,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go b.listen()
+
+	return b
+}
+
+func (b *eventBackplane) Publish(event *entity.RealtimeEvent) error {
+	// Seq выдаётся и NOTIFY отправляется в одной транзакции: строка счётчика остаётся
+	// заблокированной до коммита, поэтому уведомления одной сессии приходят в порядке Seq
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		if event.SessionID != "" {
+			var seq int64
+			err := tx.Raw(`INSERT INTO realtime_event_seqs (session_id, seq) VALUES (?, 1)
+				ON CONFLICT (session_id) DO UPDATE SET seq = realtime_event_seqs.seq + 1
+				RETURNING seq`, event.SessionID).Scan(&seq).Error
+			if err != nil {
+				return fmt.Errorf("failed to allocate event seq: %w", err)
+			}
+			event.Seq = seq
+		}
+
+		payload, err := notificationPayload(event, func(payload []byte) (int64, error) {
+			return storePayload(tx, payload)
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec("SELECT pg_notify(?, ?)", realtimeEventsChannel, payload).Error
+	})
+}
+
+// eventNotification — payload NOTIFY: само событие или ссылка на его тело в realtime_event_payloads
+type eventNotification struct {
+	entity.RealtimeEvent
+	PayloadID int64 `json:"payloadId,omitempty"`
+}
+
+// notificationPayload кодирует событие для NOTIFY. Событие больше maxNotifyPayloadSize
+// сохраняется через store, а по каналу уходит только ссылка на него
+func notificationPayload(event *entity.RealtimeEvent, store func(payload []byte) (int64, error)) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if len(payload) <= maxNotifyPayloadSize {
+		return string(payload), nil
+	}
+
+	id, err := store(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to store event %s payload (%d bytes): %w", event.Event, len(payload), err)
+	}
+
+	reference, err := json.Marshal(map[string]int64{"payloadId": id})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event reference: %w", err)
+	}
+	return string(reference), nil
+}
+
+// parseNotification разбирает payload NOTIFY; тело события по ссылке загружается через load
+func parseNotification(payload string, load func(id int64) ([]byte, error)) (*entity.RealtimeEvent, error) {
+	var notification eventNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return nil, err
+	}
+	if notification.PayloadID == 0 {
+		return &notification.RealtimeEvent, nil
+	}
+
+	stored, err := load(notification.PayloadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load event payload %d: %w", notification.PayloadID, err)
+	}
+
+	var event entity.RealtimeEvent
+	if err := json.Unmarshal(stored, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// storePayload сохраняет тело события и заодно удаляет устаревшие тела
+func storePayload(tx *gorm.DB, payload []byte) (int64, error) {
+	if err := tx.Exec("DELETE FROM realtime_event_payloads WHERE created_at < ?", time.Now().Add(-storedPayloadTTL)).Error; err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := tx.Raw("INSERT INTO realtime_event_payloads (payload) VALUES (?) RETURNING id", string(payload)).Scan(&id).Error
+	return id, err
+}
+
+func (b *eventBackplane) loadPayload(id int64) ([]byte, error) {
+	var payload string
+	result := b.db.Raw("SELECT payload FROM realtime_event_payloads WHERE id = ?", id).Scan(&payload)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("payload %d not found", id)
+	}
+	return []byte(payload), nil
+}
+
+func (b *eventBackplane) Subscribe(handler func(event *entity.RealtimeEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *eventBackplane) Close() error {
+	b.cancel()
+	return nil
+}
+
+// listen держит LISTEN-соединение и переподключается с экспоненциальной задержкой
+func (b *eventBackplane) listen() {
+	backoff := time.Second
+
+	for {
+		connected, err := b.listenOnce()
+		if b.ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = time.Second
+		}
+
+		log.Printf("[Backplane] ❌ LISTEN connection lost: %v, reconnecting in %v\n", err, backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-b.ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > listenMaxBackoff {
+			backoff = listenMaxBackoff
+		}
+	}
+}
+
+func (b *eventBackplane) listenOnce() (bool, error) {
+	conn, err := pgx.Connect(b.ctx, b.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(b.ctx, "LISTEN "+pgx.Identifier{realtimeEventsChannel}.Sanitize()); err != nil {
+		return false, err
+	}
+
+	log.Printf("[Backplane] ✅ Listening for realtime events on %s\n", realtimeEventsChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(b.ctx)
+		if err != nil {
+			return true, err
+		}
+
+		event, err := parseNotification(notification.Payload, b.loadPayload)
+		if err != nil {
+			log.Printf("[Backplane] Failed to parse notification: %v\n", err)
+			continue
+		}
+
+		b.dispatch(event)
+	}
+}
+
+func (b *eventBackplane) dispatch(event *entity.RealtimeEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func TestNotificationPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		dataSize  int
+		wantStore bool
+	}{
+		{name: "small event goes inline", dataSize: 100},
+		{name: "event at the limit goes inline", dataSize: maxNotifyPayloadSize - 200},
+		{name: "oversized event is stored", dataSize: maxNotifyPayloadSize, wantStore: true},
+		{name: "large report is stored", dataSize: 64 * 1024, wantStore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := json.Marshal(map[string]string{"report": strings.Repeat("x", tt.dataSize)})
+			event := &entity.RealtimeEvent{SessionID: "session", Seq: 7, Event: "session_completed", Data: data}
+
+			stored := make(map[int64][]byte)
+			payload, err := notificationPayload(event, func(payload []byte) (int64, error) {
+				id := int64(len(stored) + 1)
+				stored[id] = payload
+				return id, nil
+			})
+			if err != nil {
+				t.Fatalf("notificationPayload: %v", err)
+			}
+			if len(payload) > maxNotifyPayloadSize {
+				t.Fatalf("NOTIFY payload is %d bytes, limit %d", len(payload), maxNotifyPayloadSize)
+			}
+			if (len(stored) > 0) != tt.wantStore {
+				t.Errorf("stored %d payloads, want stored = %v", len(stored), tt.wantStore)
+			}
+
+			got, err := parseNotification(payload, func(id int64) ([]byte, error) {
+				if body, ok := stored[id]; ok {
+					return body, nil
+				}
+				return nil, errors.New("not found")
+			})
+			if err != nil {
+				t.Fatalf("parseNotification: %v", err)
+			}
+			if got.SessionID != event.SessionID || got.Seq != event.Seq || got.Event != event.Event || string(got.Data) != string(event.Data) {
+				t.Errorf("parsed event %s #%d of session %s (%d bytes of data), want the published one", got.Event, got.Seq, got.SessionID, len(got.Data))
+			}
+		})
+	}
+}
+
+func TestNotificationPayloadStoreError(t *testing.T) {
+	data, _ := json.Marshal(strings.Repeat("x", maxNotifyPayloadSize))
+	event := &entity.RealtimeEvent{SessionID: "session", Event: "session_completed", Data: data}
+
+	_, err := notificationPayload(event, func([]byte) (int64, error) {
+		return 0, errors.New("connection lost")
+	})
+	if err == nil {
+		t.Fatal("notificationPayload succeeded without storing an oversized event")
+	}
+}
+
+func TestParseNotificationMissingPayload(t *testing.T) {
+	_, err := parseNotification(`{"payloadId":42}`, func(int64) ([]byte, error) {
+		return nil, errors.New("not found")
+	})
+	if err == nil {
+		t.Fatal("parseNotification succeeded for a missing stored payload")
+	}
+}
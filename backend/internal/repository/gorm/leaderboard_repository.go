@@ -16,109 +16,94 @@ func NewLeaderboardRepository(db *gorm.DB) interfaces.LeaderboardRepository {
 	return &leaderboardRepository{db: db}
 }
 
+// GetSessionLeaderboard возвращает вклад участников в завершенную сессию по журналу session_leaderboard
 func (r *leaderboardRepository) GetSessionLeaderboard(sessionID string) ([]*entity.LeaderboardEntry, error) {
 	var entries []*entity.LeaderboardEntry
 
-	err := r.db.Table("session_participants").
+	err := r.db.Table("session_leaderboard").
 		Select(`
-			session_participants.user_id,
-			session_participants.user_name,
-			session_participants.avatar_url,
-			COALESCE(COUNT(CASE WHEN tasks.completed = true THEN 1 END), 0) as tasks_completed,
-			COALESCE(SUM(sessions.focus_duration), 0) as focus_time
+			users.id as user_id,
+			users.name as user_name,
+			users.avatar_url,
+			1 as sessions_completed,
+			session_leaderboard.tasks_completed,
+			session_leaderboard.focus_time,
+			COALESCE(user_stats.current_streak, 0) as current_streak
 		`).
-		Joins("JOIN sessions ON session_participants.session_id = sessions.id").
-		Joins("LEFT JOIN tasks ON tasks.session_id = sessions.id").
-		Where("session_participants.session_id = ?", sessionID).
-		Group("session_participants.user_id, session_participants.user_name, session_participants.avatar_url").
-		Order("tasks_completed DESC, focus_time DESC").
+		Joins("JOIN users ON users.id = session_leaderboard.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN user_stats ON user_stats.user_id = users.id").
+		Where("session_leaderboard.session_id = ?", sessionID).
 		Scan(&entries).Error
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Добавляем rank и score
-	for i := range entries {
-		entries[i].Rank = i + 1
-		entries[i].Score = entries[i].TasksCompleted*10 + entries[i].FocusTime
+	return entries, nil
+}
+
+// GetGlobalLeaderboard суммирует итоги сессий, завершенных в окне периода: в рейтинг попадают только
+// задачи и фокус за это окно, а не статистика за все время
+func (r *leaderboardRepository) GetGlobalLeaderboard(since *time.Time) ([]*entity.LeaderboardEntry, error) {
+	return r.scan(r.windowQuery(since))
+}
+
+// GetUsersLeaderboard строится от users, чтобы пользователи без итогов в окне попали в рейтинг с нулями
+func (r *leaderboardRepository) GetUsersLeaderboard(userIDs []string, since *time.Time) ([]*entity.LeaderboardEntry, error) {
+	if len(userIDs) == 0 {
+		return []*entity.LeaderboardEntry{}, nil
 	}
 
-	return entries, nil
+	results := "session_leaderboard.user_id = users.id"
+	var args []interface{}
+	if since != nil {
+		results += " AND session_leaderboard.completed_at >= ?"
+		args = append(args, *since)
+	}
+
+	return r.scan(r.db.Table("users").
+		Select(`
+			users.id as user_id,
+			users.name as user_name,
+			users.avatar_url,
+			COUNT(session_leaderboard.session_id) as sessions_completed,
+			COALESCE(SUM(session_leaderboard.tasks_completed), 0) as tasks_completed,
+			COALESCE(SUM(session_leaderboard.focus_time), 0) as focus_time,
+			COALESCE(user_stats.current_streak, 0) as current_streak
+		`).
+		Joins("LEFT JOIN session_leaderboard ON "+results, args...).
+		Joins("LEFT JOIN user_stats ON user_stats.user_id = users.id").
+		Where("users.id IN ? AND users.deleted_at IS NULL", userIDs).
+		Group("users.id, users.name, users.avatar_url, user_stats.current_streak"))
 }
 
-func (r *leaderboardRepository) GetGlobalLeaderboard(period entity.LeaderboardPeriod, limit int) ([]*entity.LeaderboardEntry, error) {
-	var entries []*entity.LeaderboardEntry
-	query := r.db.Table("users").
+// windowQuery суммирует итоги сессий по пользователям вместе с их текущей серией одним запросом;
+// since — начало окна, nil — за все время
+func (r *leaderboardRepository) windowQuery(since *time.Time) *gorm.DB {
+	query := r.db.Table("session_leaderboard").
 		Select(`
 			users.id as user_id,
 			users.name as user_name,
 			users.avatar_url,
-			COALESCE(user_stats.total_sessions, 0) as tasks_completed,
-			COALESCE(user_stats.total_focus_time, 0) as focus_time
+			COUNT(*) as sessions_completed,
+			COALESCE(SUM(session_leaderboard.tasks_completed), 0) as tasks_completed,
+			COALESCE(SUM(session_leaderboard.focus_time), 0) as focus_time,
+			COALESCE(user_stats.current_streak, 0) as current_streak
 		`).
-		Joins("LEFT JOIN user_stats ON users.id = user_stats.user_id")
+		Joins("JOIN users ON users.id = session_leaderboard.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN user_stats ON user_stats.user_id = users.id").
+		Group("users.id, users.name, users.avatar_url, user_stats.current_streak")
 
-	// Фильтр по периоду
-	now := time.Now()
-	switch period {
-	case entity.LeaderboardPeriodDay:
-		query = query.Where("user_stats.updated_at >= ?", now.AddDate(0, 0, -1))
-	case entity.LeaderboardPeriodWeek:
-		query = query.Where("user_stats.updated_at >= ?", now.AddDate(0, 0, -7))
-	case entity.LeaderboardPeriodMonth:
-		query = query.Where("user_stats.updated_at >= ?", now.AddDate(0, -1, 0))
-		// LeaderboardPeriodAll - без фильтра
+	if since != nil {
+		query = query.Where("session_leaderboard.completed_at >= ?", *since)
 	}
+	return query
+}
 
-	err := query.
-		Order("tasks_completed DESC, focus_time DESC").
-		Limit(limit).
-		Scan(&entries).Error
-
-	if err != nil {
+func (r *leaderboardRepository) scan(query *gorm.DB) ([]*entity.LeaderboardEntry, error) {
+	var entries []*entity.LeaderboardEntry
+	if err := query.Scan(&entries).Error; err != nil {
 		return nil, err
 	}
-
-	// Добавляем rank и score
-	for i := range entries {
-		entries[i].Rank = i + 1
-		entries[i].Score = entries[i].TasksCompleted*10 + entries[i].FocusTime
-	}
-
 	return entries, nil
 }
-
-func (r *leaderboardRepository) UpdateUserScore(userID string, sessionID string, score int) error {
-	// Обновляем статистику пользователя на основе завершенной сессии
-	var session entity.Session
-	if err := r.db.Where("id = ?", sessionID).First(&session).Error; err != nil {
-		return err
-	}
-
-	// Подсчитываем выполненные задачи
-	var tasksCompleted int64
-	r.db.Model(&entity.Task{}).
-		Where("session_id = ? AND completed = ?", sessionID, true).
-		Count(&tasksCompleted)
-
-	// Обновляем или создаем статистику
-	stats := entity.UserStats{
-		UserID:         userID,
-		TotalSessions:  1,
-		TotalFocusTime: session.FocusDuration,
-	}
-
-	err := r.db.Where("user_id = ?", userID).FirstOrCreate(&stats).Error
-	if err != nil {
-		return err
-	}
-
-	// Обновляем статистику
-	return r.db.Model(&stats).
-		Updates(map[string]interface{}{
-			"total_sessions":   gorm.Expr("total_sessions + 1"),
-			"total_focus_time": gorm.Expr("total_focus_time + ?", session.FocusDuration),
-			"updated_at":       time.Now(),
-		}).Error
-}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+)
+
+type phaseSegmentRepository struct {
+	db *gorm.DB
+}
+
+func NewPhaseSegmentRepository(db *gorm.DB) interfaces.PhaseSegmentRepository {
+	return &phaseSegmentRepository{db: db}
+}
+
+func (r *phaseSegmentRepository) Create(segment *entity.SessionPhaseSegment) error {
+	return r.db.Create(segment).Error
+}
+
+func (r *phaseSegmentRepository) CloseOpen(sessionID string, endedAt time.Time) error {
+	return r.db.Model(&entity.SessionPhaseSegment{}).
+		Where("session_id = ? AND ended_at IS NULL", sessionID).
+		Update("ended_at", endedAt).Error
+}
+
+func (r *phaseSegmentRepository) GetBySessionID(sessionID string) ([]*entity.SessionPhaseSegment, error) {
+	var segments []*entity.SessionPhaseSegment
+	err := r.db.Where("session_id = ?", sessionID).Order("started_at ASC").Find(&segments).Error
+	if err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
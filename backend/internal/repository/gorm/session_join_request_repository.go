@@ -0,0 +1,88 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+)
+
+type sessionJoinRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionJoinRequestRepository(db *gorm.DB) interfaces.SessionJoinRequestRepository {
+	return &sessionJoinRequestRepository{db: db}
+}
+
+func (r *sessionJoinRequestRepository) Create(request *entity.SessionJoinRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *sessionJoinRequestRepository) GetByID(id string) (*entity.SessionJoinRequest, error) {
+	var request entity.SessionJoinRequest
+	err := r.db.Where("id = ?", id).First(&request).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *sessionJoinRequestRepository) GetPending(sessionID string, userID string) (*entity.SessionJoinRequest, error) {
+	var request entity.SessionJoinRequest
+	err := r.db.Where("session_id = ? AND user_id = ? AND status = ?", sessionID, userID, entity.JoinRequestStatusPending).
+		First(&request).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *sessionJoinRequestRepository) GetPendingBySessionID(sessionID string) ([]*entity.SessionJoinRequest, error) {
+	var requests []*entity.SessionJoinRequest
+	err := r.db.Where("session_id = ? AND status = ?", sessionID, entity.JoinRequestStatusPending).
+		Order("created_at ASC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Resolve рассматривает заявку, только если ее еще никто не рассмотрел (двое ведущих могут нажать кнопки одновременно)
+func (r *sessionJoinRequestRepository) Resolve(id string, status entity.JoinRequestStatus, reviewerID string, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.SessionJoinRequest{}).
+		Where("id = ? AND status = ?", id, entity.JoinRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Reopen возвращает заявку в ожидание, если решение по ней не успело измениться, — откат Resolve,
+// когда применить решение не удалось
+func (r *sessionJoinRequestRepository) Reopen(id string, status entity.JoinRequestStatus) (bool, error) {
+	result := r.db.Model(&entity.SessionJoinRequest{}).
+		Where("id = ? AND status = ?", id, status).
+		Updates(map[string]interface{}{
+			"status":      entity.JoinRequestStatusPending,
+			"reviewed_by": nil,
+			"reviewed_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+)
+
+type sessionPresetRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionPresetRepository(db *gorm.DB) interfaces.SessionPresetRepository {
+	return &sessionPresetRepository{db: db}
+}
+
+func (r *sessionPresetRepository) Create(preset *entity.SessionPreset) error {
+	return r.db.Create(preset).Error
+}
+
+func (r *sessionPresetRepository) GetByID(id string) (*entity.SessionPreset, error) {
+	var preset entity.SessionPreset
+	err := r.db.Where("id = ?", id).First(&preset).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preset, nil
+}
+
+func (r *sessionPresetRepository) GetByUserID(userID string) ([]*entity.SessionPreset, error) {
+	var presets []*entity.SessionPreset
+	err := r.db.Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&presets).Error
+	if err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+func (r *sessionPresetRepository) Update(preset *entity.SessionPreset) error {
+	return r.db.Save(preset).Error
+}
+
+func (r *sessionPresetRepository) Delete(id string) error {
+	return r.db.Delete(&entity.SessionPreset{}, "id = ?", id).Error
+}
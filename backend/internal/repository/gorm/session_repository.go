@@ -1,9 +1,12 @@
 package gorm
 
 import (
+	"time"
+
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type sessionRepository struct {
@@ -30,6 +33,19 @@ func (r *sessionRepository) GetByID(id string) (*entity.Session, error) {
 	return &session, nil
 }
 
+func (r *sessionRepository) GetByIDs(ids []string) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	if len(ids) == 0 {
+		return sessions, nil
+	}
+
+	err := r.db.Where("id IN ?", ids).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
 func (r *sessionRepository) GetByInviteLink(inviteLink string) (*entity.Session, error) {
 	var session entity.Session
 	err := r.db.Preload("Tasks").Preload("Participants").Where("invite_link = ?", inviteLink).First(&session).Error
@@ -46,7 +62,7 @@ func (r *sessionRepository) GetActiveByUserID(userID string) (*entity.Session, e
 	var session entity.Session
 	err := r.db.Preload("Tasks").Preload("Participants").
 		Joins("JOIN session_participants ON sessions.id = session_participants.session_id").
-		Where("session_participants.user_id = ? AND sessions.status IN ?",
+		Where("session_participants.user_id = ? AND session_participants.left_at IS NULL AND sessions.status IN ?",
 			userID,
 			[]entity.SessionStatus{entity.SessionStatusActive, entity.SessionStatusPaused},
 		).
@@ -94,11 +110,75 @@ func (r *sessionRepository) Update(session *entity.Session) error {
 	return r.db.Save(session).Error
 }
 
+// Delete удаляет сессию без возможности восстановления. Задачи и участники удаляются явно,
+// остальные связанные таблицы чистятся каскадом по внешним ключам
+func (r *sessionRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("session_id = ?", id).Delete(&entity.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id = ?", id).Delete(&entity.Participant{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", id).Delete(&entity.Session{}).Error
+	})
+}
+
 func (r *sessionRepository) AddParticipant(sessionID string, participant *entity.Participant) error {
 	participant.SessionID = sessionID
 	return r.db.Create(participant).Error
 }
 
+// JoinWithinCapacity добавляет участника (или возвращает вышедшего), только если в сессии есть место.
+// Строка сессии блокируется на время проверки, поэтому параллельные входы не превышают лимит
+func (r *sessionRepository) JoinWithinCapacity(sessionID string, participant *entity.Participant) (bool, error) {
+	joined := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var session entity.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "max_participants").
+			Where("id = ?", sessionID).
+			First(&session).Error; err != nil {
+			return err
+		}
+
+		if session.MaxParticipants > 0 {
+			var present int64
+			if err := tx.Model(&entity.Participant{}).
+				Where("session_id = ? AND left_at IS NULL", sessionID).
+				Count(&present).Error; err != nil {
+				return err
+			}
+			if int(present) >= session.MaxParticipants {
+				return nil
+			}
+		}
+
+		result := tx.Model(&entity.Participant{}).
+			Where("session_id = ? AND user_id = ?", sessionID, participant.UserID).
+			Updates(map[string]interface{}{
+				"left_at":  nil,
+				"is_ready": false,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			participant.SessionID = sessionID
+			if err := tx.Create(participant).Error; err != nil {
+				return err
+			}
+		}
+
+		joined = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return joined, nil
+}
+
 func (r *sessionRepository) RemoveParticipant(sessionID string, userID string) error {
 	return r.db.Where("session_id = ? AND user_id = ?", sessionID, userID).
 		Delete(&entity.Participant{}).Error
@@ -110,6 +190,36 @@ func (r *sessionRepository) UpdateParticipantReady(sessionID string, userID stri
 		Update("is_ready", isReady).Error
 }
 
+func (r *sessionRepository) UpdateParticipantLeftAt(sessionID string, userID string, leftAt *time.Time) error {
+	return r.db.Model(&entity.Participant{}).
+		Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Updates(map[string]interface{}{
+			"left_at":  leftAt,
+			"is_ready": false,
+		}).Error
+}
+
+func (r *sessionRepository) UpdateParticipantBannedAt(sessionID string, userID string, bannedAt *time.Time) error {
+	return r.db.Model(&entity.Participant{}).
+		Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Update("banned_at", bannedAt).Error
+}
+
+func (r *sessionRepository) UpdateParticipantCoHost(sessionID string, userID string, isCoHost bool) error {
+	return r.db.Model(&entity.Participant{}).
+		Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Update("is_co_host", isCoHost).Error
+}
+
+func (r *sessionRepository) UpdateCreator(sessionID string, creatorID string) error {
+	return r.db.Model(&entity.Session{}).
+		Where("id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"creator_id": creatorID,
+			"updated_at": time.Now(),
+		}).Error
+}
+
 func (r *sessionRepository) GetSessionsByStatus(status entity.SessionStatus) ([]*entity.Session, error) {
 	var sessions []*entity.Session
 	err := r.db.Preload("Tasks").Preload("Participants").
@@ -121,6 +231,224 @@ func (r *sessionRepository) GetSessionsByStatus(status entity.SessionStatus) ([]
 	return sessions, nil
 }
 
+func (r *sessionRepository) GetSessionsWithPhaseEndingBefore(deadline time.Time) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.db.Where("status = ? AND phase_ends_at IS NOT NULL AND phase_ends_at <= ?", entity.SessionStatusActive, deadline).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdatePhase обновляет фазу только если сессия всё ещё активна и дедлайн не менялся,
+// чтобы параллельная пауза или другой инстанс не были перезаписаны
+func (r *sessionRepository) UpdatePhase(session *entity.Session, previousPhaseEndsAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ? AND phase_ends_at = ?", session.ID, entity.SessionStatusActive, previousPhaseEndsAt).
+		Updates(map[string]interface{}{
+			"current_phase":    session.CurrentPhase,
+			"current_cycle":    session.CurrentCycle,
+			"phase_index":      session.PhaseIndex,
+			"phase_started_at": session.PhaseStartedAt,
+			"phase_ends_at":    session.PhaseEndsAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Start запускает ожидающую сессию, записывая только статус, время старта и первую фазу:
+// участники, готовность и настройки, которые могли измениться параллельно, запись не перетирает
+func (r *sessionRepository) Start(session *entity.Session) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ?", session.ID, entity.SessionStatusPending).
+		Updates(map[string]interface{}{
+			"status":           entity.SessionStatusActive,
+			"started_at":       session.StartedAt,
+			"auto_start_at":    nil,
+			"current_cycle":    session.CurrentCycle,
+			"phase_index":      session.PhaseIndex,
+			"current_phase":    session.CurrentPhase,
+			"phase_started_at": session.PhaseStartedAt,
+			"phase_ends_at":    session.PhaseEndsAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Pause ставит активную сессию на паузу, меняя только статус и начало паузы: фазу, которую
+// параллельно переключил таймер, запись не перетирает
+func (r *sessionRepository) Pause(sessionID string, pausedAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ?", sessionID, entity.SessionStatusActive).
+		Updates(map[string]interface{}{
+			"status":     entity.SessionStatusPaused,
+			"paused_at":  pausedAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Resume снимает сессию с паузы, только если это та же пауза, что прочитал вызывающий
+func (r *sessionRepository) Resume(session *entity.Session, pausedAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ? AND paused_at = ?", session.ID, entity.SessionStatusPaused, pausedAt).
+		Updates(map[string]interface{}{
+			"status":           entity.SessionStatusActive,
+			"paused_at":        nil,
+			"total_pause_time": session.TotalPauseTime,
+			"phase_started_at": session.PhaseStartedAt,
+			"phase_ends_at":    session.PhaseEndsAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Finish завершает или отменяет сессию, только если она всё ещё в статусе from. Вместе со статусом
+// сохраняются время завершения и итог пауз, а фаза сбрасывается — таймер больше не трогает сессию
+func (r *sessionRepository) Finish(session *entity.Session, from entity.SessionStatus) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ?", session.ID, from).
+		Updates(map[string]interface{}{
+			"status":           session.Status,
+			"completed_at":     session.CompletedAt,
+			"paused_at":        nil,
+			"total_pause_time": session.TotalPauseTime,
+			"current_phase":    "",
+			"phase_started_at": nil,
+			"phase_ends_at":    nil,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateStatus меняет статус, только если сессия всё ещё в статусе from:
+// из нескольких инстансов переход выполнит ровно один
+func (r *sessionRepository) UpdateStatus(sessionID string, from, to entity.SessionStatus) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND status = ?", sessionID, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) GetScheduledSessionsBefore(deadline time.Time) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.db.Preload("Participants").
+		Where("status = ? AND scheduled_at IS NOT NULL AND scheduled_at <= ?", entity.SessionStatusPending, deadline).
+		Order("scheduled_at ASC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) MarkReminderSent(sessionID string, sentAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND reminder_sent_at IS NULL", sessionID).
+		Update("reminder_sent_at", sentAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) GetUpcomingBySeriesID(seriesID string) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.db.Preload("Participants").
+		Where("series_id = ? AND status = ?", seriesID, entity.SessionStatusPending).
+		Order("scheduled_at ASC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) GetPendingWithStartPolicy() ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.db.Preload("Participants").
+		Where("status = ? AND start_policy <> ?", entity.SessionStatusPending, entity.StartPolicyManual).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdateAutoStartAt запускает или отменяет обратный отсчет, только если его никто не изменил раньше
+func (r *sessionRepository) UpdateAutoStartAt(sessionID string, previous, next *time.Time) (bool, error) {
+	query := r.db.Model(&entity.Session{}).Where("id = ? AND status = ?", sessionID, entity.SessionStatusPending)
+	if previous == nil {
+		query = query.Where("auto_start_at IS NULL")
+	} else {
+		query = query.Where("auto_start_at = ?", *previous)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"auto_start_at": next,
+		"updated_at":    time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CancelAutoStart в одной транзакции отменяет обратный отсчет и сбрасывает готовность участников,
+// чтобы условие автостарта не выполнилось снова до того, как готовность сброшена
+func (r *sessionRepository) CancelAutoStart(sessionID string, startsAt time.Time) (bool, error) {
+	cancelled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Session{}).
+			Where("id = ? AND status = ? AND auto_start_at = ?", sessionID, entity.SessionStatusPending, startsAt).
+			Updates(map[string]interface{}{
+				"auto_start_at": nil,
+				"updated_at":    time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&entity.Participant{}).
+			Where("session_id = ?", sessionID).
+			Update("is_ready", false).Error; err != nil {
+			return err
+		}
+
+		cancelled = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return cancelled, nil
+}
+
 func (r *sessionRepository) GetAll() ([]*entity.Session, error) {
 	var sessions []*entity.Session
 	err := r.db.Preload("Tasks").Preload("Participants").
@@ -0,0 +1,95 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionSeriesRepository(db *gorm.DB) interfaces.SessionSeriesRepository {
+	return &sessionSeriesRepository{db: db}
+}
+
+func (r *sessionSeriesRepository) Create(series *entity.SessionSeries) error {
+	return r.db.Create(series).Error
+}
+
+func (r *sessionSeriesRepository) GetByID(id string) (*entity.SessionSeries, error) {
+	var series entity.SessionSeries
+	err := r.db.Where("id = ?", id).First(&series).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *sessionSeriesRepository) GetByInviteLink(inviteLink string) (*entity.SessionSeries, error) {
+	var series entity.SessionSeries
+	err := r.db.Where("invite_link = ?", inviteLink).First(&series).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *sessionSeriesRepository) GetAll() ([]*entity.SessionSeries, error) {
+	var series []*entity.SessionSeries
+	if err := r.db.Order("created_at ASC").Find(&series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *sessionSeriesRepository) Delete(id string) error {
+	return r.db.Delete(&entity.SessionSeries{}, "id = ?", id).Error
+}
+
+// AdvanceGeneratedUntil сдвигает горизонт генерации, только если его никто не сдвинул раньше
+func (r *sessionSeriesRepository) AdvanceGeneratedUntil(seriesID string, previous *time.Time, next time.Time) (bool, error) {
+	query := r.db.Model(&entity.SessionSeries{}).Where("id = ?", seriesID)
+	if previous == nil {
+		query = query.Where("generated_until IS NULL")
+	} else {
+		query = query.Where("generated_until = ?", *previous)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"generated_until": next,
+		"updated_at":      time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionSeriesRepository) AddSubscriber(subscriber *entity.SessionSeriesSubscriber) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(subscriber).Error
+}
+
+func (r *sessionSeriesRepository) RemoveSubscriber(seriesID string, userID string) error {
+	return r.db.Where("series_id = ? AND user_id = ?", seriesID, userID).
+		Delete(&entity.SessionSeriesSubscriber{}).Error
+}
+
+func (r *sessionSeriesRepository) GetSubscribers(seriesID string) ([]*entity.SessionSeriesSubscriber, error) {
+	var subscribers []*entity.SessionSeriesSubscriber
+	err := r.db.Where("series_id = ?", seriesID).Order("subscribed_at ASC").Find(&subscribers).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscribers, nil
+}
@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionWaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionWaitlistRepository(db *gorm.DB) interfaces.SessionWaitlistRepository {
+	return &sessionWaitlistRepository{db: db}
+}
+
+func (r *sessionWaitlistRepository) Add(entry *entity.SessionWaitlistEntry) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error
+}
+
+func (r *sessionWaitlistRepository) Remove(sessionID string, userID string) error {
+	return r.db.Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Delete(&entity.SessionWaitlistEntry{}).Error
+}
+
+func (r *sessionWaitlistRepository) GetBySessionID(sessionID string) ([]*entity.SessionWaitlistEntry, error) {
+	var entries []*entity.SessionWaitlistEntry
+	err := r.db.Where("session_id = ?", sessionID).Order("created_at ASC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
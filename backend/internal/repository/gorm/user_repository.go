@@ -1,9 +1,12 @@
 package gorm
 
 import (
+	"time"
+
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepository struct {
@@ -30,6 +33,19 @@ func (r *userRepository) GetByID(id string) (*entity.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDs(ids []string) ([]*entity.User, error) {
+	var users []*entity.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByTelegramUserID(telegramUserID int64) (*entity.User, error) {
 	var user entity.User
 	err := r.db.Where("telegram_user_id = ?", telegramUserID).First(&user).Error
@@ -42,10 +58,36 @@ func (r *userRepository) GetByTelegramUserID(telegramUserID int64) (*entity.User
 	return &user, nil
 }
 
+func (r *userRepository) GetByInviteCode(inviteCode string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Where("invite_code = ?", inviteCode).First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(user *entity.User) error {
 	return r.db.Save(user).Error
 }
 
+// SetInviteCode задает код приглашения, только если его еще нет: параллельный запрос не перезапишет выданный код
+func (r *userRepository) SetInviteCode(userID string, inviteCode string) (bool, error) {
+	result := r.db.Model(&entity.User{}).
+		Where("id = ? AND invite_code IS NULL", userID).
+		Updates(map[string]interface{}{
+			"invite_code": inviteCode,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *userRepository) UpdateStats(userID string, stats *entity.UserStats) error {
 	stats.UserID = userID
 	return r.db.Where("user_id = ?", userID).Save(stats).Error
@@ -69,3 +111,139 @@ func (r *userRepository) GetStats(userID string) (*entity.UserStats, error) {
 	}
 	return &stats, nil
 }
+
+// GetSessionResults возвращает итоги сессий пользователя, завершенных в [from, to), по времени завершения
+func (r *userRepository) GetSessionResults(userID string, from, to time.Time) ([]*entity.SessionResult, error) {
+	var results []*entity.SessionResult
+	err := r.db.Where("user_id = ? AND completed_at >= ? AND completed_at < ?", userID, from, to).
+		Order("completed_at ASC").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// RecordSessionResult сохраняет итог сессии участника и в той же транзакции учитывает его в статистике.
+// Если итог по этой сессии уже записан, статистика не меняется
+func (r *userRepository) RecordSessionResult(result *entity.SessionResult, loc *time.Location) (bool, error) {
+	recorded := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		inserted := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(result)
+		if inserted.Error != nil {
+			return inserted.Error
+		}
+		if inserted.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.UserStats{UserID: result.UserID}).Error; err != nil {
+			return err
+		}
+
+		var stats entity.UserStats
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", result.UserID).
+			First(&stats).Error; err != nil {
+			return err
+		}
+
+		stats.AddSession(result, loc)
+		if err := tx.Save(&stats).Error; err != nil {
+			return err
+		}
+
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
+
+func (r *userRepository) RebuildStats(results []*entity.SessionResult) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Завершения сессий ждут конца пересчета и учитываются уже поверх новой статистики
+		if err := tx.Exec("LOCK TABLE session_leaderboard IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		if len(results) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "session_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"tasks_completed", "tasks_total", "focus_time", "completed_at", "updated_at"}),
+			}).CreateInBatches(results, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		var ledger []*entity.SessionResult
+		if err := tx.Find(&ledger).Error; err != nil {
+			return err
+		}
+
+		var users []*entity.User
+		if err := tx.Unscoped().
+			Select("id", "timezone").
+			Where("id IN (?)", tx.Model(&entity.SessionResult{}).Distinct("user_id")).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		locations := make(map[string]*time.Location, len(users))
+		for _, user := range users {
+			locations[user.ID] = user.Location()
+		}
+
+		if err := tx.Model(&entity.UserStats{}).Where("1 = 1").Updates(map[string]interface{}{
+			"total_sessions":        0,
+			"total_focus_time":      0,
+			"total_tasks_completed": 0,
+			"current_streak":        0,
+			"perfect_streak":        0,
+			"last_session_date":     nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		stats := entity.BuildStats(ledger, func(userID string) *time.Location {
+			if loc, exists := locations[userID]; exists {
+				return loc
+			}
+			return time.UTC
+		})
+		if len(stats) == 0 {
+			return nil
+		}
+		rebuilt := make([]*entity.UserStats, 0, len(stats))
+		for _, userStats := range stats {
+			rebuilt = append(rebuilt, userStats)
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rebuilt, 500).Error
+	})
+}
+
+func (r *userRepository) GetStreakTimezones() ([]string, error) {
+	var timezones []string
+	err := r.db.Model(&entity.User{}).
+		Joins("JOIN user_stats ON user_stats.user_id = users.id").
+		Where("user_stats.current_streak > 0").
+		Distinct().
+		Pluck("users.timezone", &timezones).Error
+	if err != nil {
+		return nil, err
+	}
+	return timezones, nil
+}
+
+func (r *userRepository) ResetLapsedStreaks(timezone string, before time.Time) (int, error) {
+	result := r.db.Model(&entity.UserStats{}).
+		Where("current_streak > 0 AND last_session_date < ?", before.Format("2006-01-02")).
+		Where("user_id IN (?)", r.db.Model(&entity.User{}).Select("id").Where("timezone = ?", timezone)).
+		Update("current_streak", 0)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type AchievementRepository struct {
+	achievements map[string]map[entity.AchievementCode]*entity.UserAchievement // userID -> код -> значок
+	mu           sync.RWMutex
+}
+
+func NewAchievementRepository() interfaces.AchievementRepository {
+	return &AchievementRepository{
+		achievements: make(map[string]map[entity.AchievementCode]*entity.UserAchievement),
+	}
+}
+
+func (r *AchievementRepository) Unlock(achievement *entity.UserAchievement) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.achievements[achievement.UserID] == nil {
+		r.achievements[achievement.UserID] = make(map[entity.AchievementCode]*entity.UserAchievement)
+	}
+	if _, exists := r.achievements[achievement.UserID][achievement.Code]; exists {
+		return false, nil
+	}
+
+	stored := *achievement
+	r.achievements[achievement.UserID][achievement.Code] = &stored
+	return true, nil
+}
+
+func (r *AchievementRepository) GetByUserID(userID string) ([]*entity.UserAchievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	achievements := make([]*entity.UserAchievement, 0, len(r.achievements[userID]))
+	for _, achievement := range r.achievements[userID] {
+		copied := *achievement
+		achievements = append(achievements, &copied)
+	}
+
+	sort.Slice(achievements, func(i, j int) bool {
+		return achievements[i].UnlockedAt.Before(achievements[j].UnlockedAt)
+	})
+
+	return achievements, nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type AttendanceRepository struct {
+	intervals map[string][]*entity.SessionAttendance // sessionID -> intervals
+	mu        sync.RWMutex
+}
+
+func NewAttendanceRepository() interfaces.AttendanceRepository {
+	return &AttendanceRepository{
+		intervals: make(map[string][]*entity.SessionAttendance),
+	}
+}
+
+func (r *AttendanceRepository) Create(attendance *entity.SessionAttendance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.intervals[attendance.SessionID] {
+		if existing.ID == attendance.ID {
+			return fmt.Errorf("attendance with ID %s already exists", attendance.ID)
+		}
+	}
+
+	r.intervals[attendance.SessionID] = append(r.intervals[attendance.SessionID], attendance)
+	return nil
+}
+
+func (r *AttendanceRepository) Close(sessionID string, userID string, leftAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interval := range r.intervals[sessionID] {
+		if interval.UserID == userID && interval.LeftAt == nil {
+			left := leftAt
+			interval.LeftAt = &left
+		}
+	}
+	return nil
+}
+
+func (r *AttendanceRepository) CloseAll(sessionID string, leftAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interval := range r.intervals[sessionID] {
+		if interval.LeftAt == nil {
+			left := leftAt
+			interval.LeftAt = &left
+		}
+	}
+	return nil
+}
+
+func (r *AttendanceRepository) GetBySessionID(sessionID string) ([]*entity.SessionAttendance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	intervals := make([]*entity.SessionAttendance, len(r.intervals[sessionID]))
+	copy(intervals, r.intervals[sessionID])
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].JoinedAt.Before(intervals[j].JoinedAt)
+	})
+
+	return intervals, nil
+}
+
+// deleteBySessionID удаляет интервалы присутствия при удалении сессии
+func (r *AttendanceRepository) deleteBySessionID(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.intervals, sessionID)
+}
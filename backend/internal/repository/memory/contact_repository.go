@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type ContactRepository struct {
+	contacts map[string]map[string]*entity.UserContact // userID -> contactID -> контакт
+	mu       sync.RWMutex
+}
+
+func NewContactRepository() interfaces.ContactRepository {
+	return &ContactRepository{
+		contacts: make(map[string]map[string]*entity.UserContact),
+	}
+}
+
+func (r *ContactRepository) AddSessionContacts(userIDs []string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, userID := range userIDs {
+		for _, contactID := range userIDs {
+			if userID == contactID {
+				continue
+			}
+			r.add(&entity.UserContact{
+				UserID:    userID,
+				ContactID: contactID,
+				Source:    entity.ContactSourceSession,
+				CreatedAt: at,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (r *ContactRepository) Follow(contact *entity.UserContact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add(contact)
+	return nil
+}
+
+// add сохраняет контакт, если его еще нет; вызывается под блокировкой
+func (r *ContactRepository) add(contact *entity.UserContact) {
+	if r.contacts[contact.UserID] == nil {
+		r.contacts[contact.UserID] = make(map[string]*entity.UserContact)
+	}
+	if _, exists := r.contacts[contact.UserID][contact.ContactID]; exists {
+		return
+	}
+
+	stored := *contact
+	r.contacts[contact.UserID][contact.ContactID] = &stored
+}
+
+func (r *ContactRepository) Delete(userID string, contactID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.contacts[userID][contactID]; !exists {
+		return false, nil
+	}
+
+	delete(r.contacts[userID], contactID)
+	return true, nil
+}
+
+func (r *ContactRepository) GetByUserID(userID string) ([]*entity.UserContact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contacts := make([]*entity.UserContact, 0, len(r.contacts[userID]))
+	for _, contact := range r.contacts[userID] {
+		copied := *contact
+		contacts = append(contacts, &copied)
+	}
+
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].CreatedAt.After(contacts[j].CreatedAt)
+	})
+
+	return contacts, nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// EventBackplane доставляет события внутри одного процесса (single-node и тесты)
+type EventBackplane struct {
+	handlers []func(event *entity.RealtimeEvent)
+	seqs     map[string]int64 // sessionID -> последний выданный Seq
+	mu       sync.Mutex
+}
+
+func NewEventBackplane() interfaces.EventBackplane {
+	return &EventBackplane{
+		seqs: make(map[string]int64),
+	}
+}
+
+func (b *EventBackplane) Publish(event *entity.RealtimeEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.SessionID != "" {
+		b.seqs[event.SessionID]++
+		event.Seq = b.seqs[event.SessionID]
+	}
+
+	// Доставляем под блокировкой, чтобы подписчики видели события в порядке Seq
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+
+	return nil
+}
+
+func (b *EventBackplane) Subscribe(handler func(event *entity.RealtimeEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *EventBackplane) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = nil
+	return nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func TestEventBackplaneSeq(t *testing.T) {
+	backplane := NewEventBackplane()
+
+	var delivered []*entity.RealtimeEvent
+	backplane.Subscribe(func(event *entity.RealtimeEvent) {
+		delivered = append(delivered, event)
+	})
+
+	events := []*entity.RealtimeEvent{
+		{SessionID: "a", Event: "phase_changed"},
+		{SessionID: "b", Event: "phase_changed"},
+		{UserID: "user", Event: "achievement_unlocked"},
+		{SessionID: "a", Event: "session_completed"},
+		{Event: "broadcast"},
+	}
+	for _, event := range events {
+		if err := backplane.Publish(event); err != nil {
+			t.Fatalf("Publish(%s): %v", event.Event, err)
+		}
+	}
+
+	// Номера идут по сессиям, события без сессии номера не получают
+	wantSeqs := []int64{1, 1, 0, 2, 0}
+	if len(delivered) != len(events) {
+		t.Fatalf("delivered %d events, want %d", len(delivered), len(events))
+	}
+	for i, event := range delivered {
+		if event != events[i] {
+			t.Errorf("event %d delivered out of order: %s", i, event.Event)
+		}
+		if event.Seq != wantSeqs[i] {
+			t.Errorf("event %d (%s of session %q) seq = %d, want %d", i, event.Event, event.SessionID, event.Seq, wantSeqs[i])
+		}
+	}
+
+	if err := backplane.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := backplane.Publish(&entity.RealtimeEvent{SessionID: "a", Event: "late"}); err != nil {
+		t.Fatalf("Publish after Close: %v", err)
+	}
+	if len(delivered) != len(events) {
+		t.Errorf("event published after Close was delivered")
+	}
+}
@@ -1,112 +1,113 @@
 package memory
 
 import (
-	"sort"
-	"sync"
+	"time"
 
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
 )
 
+// LeaderboardRepository строит лидерборды по итогам сессий, которые хранит UserRepository
 type LeaderboardRepository struct {
-	sessionScores map[string]map[string]int // sessionID -> userID -> score
-	globalScores  map[string]int            // userID -> totalScore
-	mu            sync.RWMutex
+	users *UserRepository
 }
 
-func NewLeaderboardRepository() interfaces.LeaderboardRepository {
-	return &LeaderboardRepository{
-		sessionScores: make(map[string]map[string]int),
-		globalScores:  make(map[string]int),
-	}
+func NewLeaderboardRepository(users *UserRepository) interfaces.LeaderboardRepository {
+	return &LeaderboardRepository{users: users}
 }
 
 func (r *LeaderboardRepository) GetSessionLeaderboard(sessionID string) ([]*entity.LeaderboardEntry, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	scores, exists := r.sessionScores[sessionID]
-	if !exists {
-		return []*entity.LeaderboardEntry{}, nil
-	}
-
-	type userScore struct {
-		userID string
-		score  int
-	}
+	r.users.mu.RLock()
+	defer r.users.mu.RUnlock()
 
-	var userScores []userScore
-	for userID, score := range scores {
-		userScores = append(userScores, userScore{userID: userID, score: score})
-	}
-
-	// Сортируем по убыванию score
-	sort.Slice(userScores, func(i, j int) bool {
-		return userScores[i].score > userScores[j].score
-	})
-
-	// Формируем результат
-	var entries []*entity.LeaderboardEntry
-	for rank, us := range userScores {
-		entries = append(entries, &entity.LeaderboardEntry{
-			Rank:   rank + 1,
-			UserID: us.userID,
-			Score:  us.score,
-		})
+	entries := make([]*entity.LeaderboardEntry, 0, len(r.users.results[sessionID]))
+	for _, result := range r.users.results[sessionID] {
+		entries = append(entries, r.entry(result.UserID, result))
 	}
 
 	return entries, nil
 }
 
-func (r *LeaderboardRepository) GetGlobalLeaderboard(period entity.LeaderboardPeriod, limit int) ([]*entity.LeaderboardEntry, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+func (r *LeaderboardRepository) GetGlobalLeaderboard(since *time.Time) ([]*entity.LeaderboardEntry, error) {
+	r.users.mu.RLock()
+	defer r.users.mu.RUnlock()
 
-	type userScore struct {
-		userID string
-		score  int
-	}
+	return r.aggregate(since, nil), nil
+}
 
-	var userScores []userScore
-	for userID, score := range r.globalScores {
-		userScores = append(userScores, userScore{userID: userID, score: score})
+func (r *LeaderboardRepository) GetUsersLeaderboard(userIDs []string, since *time.Time) ([]*entity.LeaderboardEntry, error) {
+	r.users.mu.RLock()
+	defer r.users.mu.RUnlock()
+
+	only := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		only[userID] = true
 	}
 
-	// Сортируем по убыванию score
-	sort.Slice(userScores, func(i, j int) bool {
-		return userScores[i].score > userScores[j].score
-	})
+	entries := r.aggregate(since, only)
 
-	// Ограничиваем количество
-	if limit > 0 && len(userScores) > limit {
-		userScores = userScores[:limit]
+	// Пользователи без итогов в окне попадают в рейтинг с нулями
+	listed := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		listed[entry.UserID] = true
 	}
-
-	// Формируем результат
-	var entries []*entity.LeaderboardEntry
-	for rank, us := range userScores {
-		entries = append(entries, &entity.LeaderboardEntry{
-			Rank:   rank + 1,
-			UserID: us.userID,
-			Score:  us.score,
-		})
+	for userID := range only {
+		if _, exists := r.users.users[userID]; exists && !listed[userID] {
+			entries = append(entries, r.entry(userID, nil))
+		}
 	}
 
 	return entries, nil
 }
 
-func (r *LeaderboardRepository) UpdateUserScore(userID string, sessionID string, score int) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	// Обновляем счет для сессии
-	if r.sessionScores[sessionID] == nil {
-		r.sessionScores[sessionID] = make(map[string]int)
+// aggregate суммирует итоги сессий, завершенных в окне периода; only — только эти пользователи, nil — все.
+// Вызывается под блокировкой UserRepository
+func (r *LeaderboardRepository) aggregate(since *time.Time, only map[string]bool) []*entity.LeaderboardEntry {
+	byUser := make(map[string]*entity.LeaderboardEntry)
+	for _, results := range r.users.results {
+		for userID, result := range results {
+			if since != nil && result.CompletedAt.Before(*since) {
+				continue
+			}
+			if only != nil && !only[userID] {
+				continue
+			}
+			if _, exists := r.users.users[userID]; !exists {
+				continue
+			}
+
+			entry, exists := byUser[userID]
+			if !exists {
+				entry = r.entry(userID, nil)
+				byUser[userID] = entry
+			}
+			entry.SessionsCompleted++
+			entry.TasksCompleted += result.TasksCompleted
+			entry.FocusTime += result.FocusTime
+		}
 	}
-	r.sessionScores[sessionID][userID] = score
 
-	// Обновляем глобальный счет
-	r.globalScores[userID] += score
+	entries := make([]*entity.LeaderboardEntry, 0, len(byUser))
+	for _, entry := range byUser {
+		entries = append(entries, entry)
+	}
+	return entries
+}
 
-	return nil
+// entry создает запись лидерборда пользователя с его текущей серией; result — итог одной сессии, nil — пустая запись
+func (r *LeaderboardRepository) entry(userID string, result *entity.SessionResult) *entity.LeaderboardEntry {
+	entry := &entity.LeaderboardEntry{UserID: userID}
+	if user, exists := r.users.users[userID]; exists {
+		entry.UserName = user.Name
+		entry.AvatarURL = user.AvatarURL
+	}
+	if stats, exists := r.users.stats[userID]; exists {
+		entry.CurrentStreak = stats.CurrentStreak
+	}
+	if result != nil {
+		entry.SessionsCompleted = 1
+		entry.TasksCompleted = result.TasksCompleted
+		entry.FocusTime = result.FocusTime
+	}
+	return entry
 }
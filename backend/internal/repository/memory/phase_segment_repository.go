@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type PhaseSegmentRepository struct {
+	segments map[string][]*entity.SessionPhaseSegment // sessionID -> segments
+	mu       sync.RWMutex
+}
+
+func NewPhaseSegmentRepository() interfaces.PhaseSegmentRepository {
+	return &PhaseSegmentRepository{
+		segments: make(map[string][]*entity.SessionPhaseSegment),
+	}
+}
+
+func (r *PhaseSegmentRepository) Create(segment *entity.SessionPhaseSegment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.segments[segment.SessionID] {
+		if existing.ID == segment.ID {
+			return fmt.Errorf("phase segment with ID %s already exists", segment.ID)
+		}
+	}
+
+	r.segments[segment.SessionID] = append(r.segments[segment.SessionID], segment)
+	return nil
+}
+
+func (r *PhaseSegmentRepository) CloseOpen(sessionID string, endedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, segment := range r.segments[sessionID] {
+		if segment.EndedAt == nil {
+			ended := endedAt
+			segment.EndedAt = &ended
+		}
+	}
+	return nil
+}
+
+func (r *PhaseSegmentRepository) GetBySessionID(sessionID string) ([]*entity.SessionPhaseSegment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	segments := make([]*entity.SessionPhaseSegment, len(r.segments[sessionID]))
+	copy(segments, r.segments[sessionID])
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].StartedAt.Before(segments[j].StartedAt)
+	})
+
+	return segments, nil
+}
+
+// deleteBySessionID удаляет отрезки фаз при удалении сессии
+func (r *PhaseSegmentRepository) deleteBySessionID(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.segments, sessionID)
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type SessionJoinRequestRepository struct {
+	requests map[string]*entity.SessionJoinRequest
+	mu       sync.RWMutex
+}
+
+func NewSessionJoinRequestRepository() interfaces.SessionJoinRequestRepository {
+	return &SessionJoinRequestRepository{
+		requests: make(map[string]*entity.SessionJoinRequest),
+	}
+}
+
+func (r *SessionJoinRequestRepository) Create(request *entity.SessionJoinRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.requests[request.ID]; exists {
+		return fmt.Errorf("join request with ID %s already exists", request.ID)
+	}
+	for _, existing := range r.requests {
+		if existing.SessionID == request.SessionID && existing.UserID == request.UserID &&
+			existing.Status == entity.JoinRequestStatusPending {
+			return fmt.Errorf("user %s already has a pending join request in session %s", request.UserID, request.SessionID)
+		}
+	}
+
+	r.requests[request.ID] = request
+	return nil
+}
+
+func (r *SessionJoinRequestRepository) GetByID(id string) (*entity.SessionJoinRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	request, exists := r.requests[id]
+	if !exists {
+		return nil, fmt.Errorf("join request with ID %s not found", id)
+	}
+	return request, nil
+}
+
+func (r *SessionJoinRequestRepository) GetPending(sessionID string, userID string) (*entity.SessionJoinRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, request := range r.requests {
+		if request.SessionID == sessionID && request.UserID == userID && request.Status == entity.JoinRequestStatusPending {
+			return request, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *SessionJoinRequestRepository) GetPendingBySessionID(sessionID string) ([]*entity.SessionJoinRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entity.SessionJoinRequest
+	for _, request := range r.requests {
+		if request.SessionID == sessionID && request.Status == entity.JoinRequestStatusPending {
+			result = append(result, request)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (r *SessionJoinRequestRepository) Resolve(id string, status entity.JoinRequestStatus, reviewerID string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, exists := r.requests[id]
+	if !exists {
+		return false, fmt.Errorf("join request with ID %s not found", id)
+	}
+	if request.Status != entity.JoinRequestStatusPending {
+		return false, nil
+	}
+
+	request.Status = status
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &at
+	return true, nil
+}
+
+func (r *SessionJoinRequestRepository) Reopen(id string, status entity.JoinRequestStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, exists := r.requests[id]
+	if !exists {
+		return false, fmt.Errorf("join request with ID %s not found", id)
+	}
+	if request.Status != status {
+		return false, nil
+	}
+
+	request.Status = entity.JoinRequestStatusPending
+	request.ReviewedBy = nil
+	request.ReviewedAt = nil
+	return true, nil
+}
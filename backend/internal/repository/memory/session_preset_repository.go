@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type SessionPresetRepository struct {
+	presets map[string]*entity.SessionPreset
+	mu      sync.RWMutex
+}
+
+func NewSessionPresetRepository() interfaces.SessionPresetRepository {
+	return &SessionPresetRepository{
+		presets: make(map[string]*entity.SessionPreset),
+	}
+}
+
+func (r *SessionPresetRepository) Create(preset *entity.SessionPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.presets[preset.ID]; exists {
+		return fmt.Errorf("preset with ID %s already exists", preset.ID)
+	}
+	for _, existing := range r.presets {
+		if existing.UserID == preset.UserID && existing.Name == preset.Name {
+			return fmt.Errorf("preset named %q already exists", preset.Name)
+		}
+	}
+
+	stored := *preset
+	r.presets[preset.ID] = &stored
+	return nil
+}
+
+func (r *SessionPresetRepository) GetByID(id string) (*entity.SessionPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preset, exists := r.presets[id]
+	if !exists {
+		return nil, fmt.Errorf("preset with ID %s not found", id)
+	}
+
+	copied := *preset
+	return &copied, nil
+}
+
+func (r *SessionPresetRepository) GetByUserID(userID string) ([]*entity.SessionPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	presets := make([]*entity.SessionPreset, 0)
+	for _, preset := range r.presets {
+		if preset.UserID == userID {
+			copied := *preset
+			presets = append(presets, &copied)
+		}
+	}
+
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+
+	return presets, nil
+}
+
+func (r *SessionPresetRepository) Update(preset *entity.SessionPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.presets[preset.ID]; !exists {
+		return fmt.Errorf("preset with ID %s not found", preset.ID)
+	}
+	for id, existing := range r.presets {
+		if id != preset.ID && existing.UserID == preset.UserID && existing.Name == preset.Name {
+			return fmt.Errorf("preset named %q already exists", preset.Name)
+		}
+	}
+
+	stored := *preset
+	r.presets[preset.ID] = &stored
+	return nil
+}
+
+func (r *SessionPresetRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.presets, id)
+	return nil
+}
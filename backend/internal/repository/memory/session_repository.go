@@ -2,7 +2,9 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
@@ -13,13 +15,21 @@ type SessionRepository struct {
 	inviteLinks  map[string]string   // inviteLink -> sessionID
 	userSessions map[string][]string // userID -> []sessionID
 	mu           sync.RWMutex
+
+	// Данные сессии в других репозиториях удаляются вместе с ней, как по внешним ключам в БД
+	tasks      *TaskRepository
+	attendance *AttendanceRepository
+	segments   *PhaseSegmentRepository
 }
 
-func NewSessionRepository() interfaces.SessionRepository {
+func NewSessionRepository(tasks *TaskRepository, attendance *AttendanceRepository, segments *PhaseSegmentRepository) interfaces.SessionRepository {
 	return &SessionRepository{
 		sessions:     make(map[string]*entity.Session),
 		inviteLinks:  make(map[string]string),
 		userSessions: make(map[string][]string),
+		tasks:        tasks,
+		attendance:   attendance,
+		segments:     segments,
 	}
 }
 
@@ -52,6 +62,20 @@ func (r *SessionRepository) GetByID(id string) (*entity.Session, error) {
 	return session, nil
 }
 
+func (r *SessionRepository) GetByIDs(ids []string) ([]*entity.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*entity.Session, 0, len(ids))
+	for _, id := range ids {
+		if session, exists := r.sessions[id]; exists {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
 func (r *SessionRepository) GetByInviteLink(inviteLink string) (*entity.Session, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -80,8 +104,13 @@ func (r *SessionRepository) GetActiveByUserID(userID string) (*entity.Session, e
 
 	for _, sessionID := range sessionIDs {
 		session, exists := r.sessions[sessionID]
-		if exists && session.Status == entity.SessionStatusActive {
-			return session, nil
+		if !exists || session.Status != entity.SessionStatusActive {
+			continue
+		}
+		for _, p := range session.Participants {
+			if p.UserID == userID && p.LeftAt == nil {
+				return session, nil
+			}
 		}
 	}
 
@@ -133,6 +162,35 @@ func (r *SessionRepository) Update(session *entity.Session) error {
 	return nil
 }
 
+func (r *SessionRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return fmt.Errorf("session with ID %s not found", id)
+	}
+
+	delete(r.sessions, id)
+	delete(r.inviteLinks, session.InviteLink)
+
+	for userID, sessionIDs := range r.userSessions {
+		filtered := sessionIDs[:0]
+		for _, sessionID := range sessionIDs {
+			if sessionID != id {
+				filtered = append(filtered, sessionID)
+			}
+		}
+		r.userSessions[userID] = filtered
+	}
+
+	r.tasks.deleteBySessionID(id)
+	r.attendance.deleteBySessionID(id)
+	r.segments.deleteBySessionID(id)
+
+	return nil
+}
+
 func (r *SessionRepository) AddParticipant(sessionID string, participant *entity.Participant) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -158,6 +216,40 @@ func (r *SessionRepository) AddParticipant(sessionID string, participant *entity
 	return nil
 }
 
+func (r *SessionRepository) JoinWithinCapacity(sessionID string, participant *entity.Participant) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	present := 0
+	for _, p := range session.Participants {
+		if p.LeftAt == nil {
+			present++
+		}
+	}
+	if session.MaxParticipants > 0 && present >= session.MaxParticipants {
+		return false, nil
+	}
+
+	for i, p := range session.Participants {
+		if p.UserID == participant.UserID {
+			session.Participants[i].LeftAt = nil
+			session.Participants[i].IsReady = false
+			return true, nil
+		}
+	}
+
+	participant.SessionID = sessionID
+	session.Participants = append(session.Participants, *participant)
+	r.userSessions[participant.UserID] = append(r.userSessions[participant.UserID], sessionID)
+
+	return true, nil
+}
+
 func (r *SessionRepository) RemoveParticipant(sessionID string, userID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -196,6 +288,78 @@ func (r *SessionRepository) UpdateParticipantReady(sessionID string, userID stri
 	return fmt.Errorf("participant with userID %s not found in session %s", userID, sessionID)
 }
 
+func (r *SessionRepository) UpdateParticipantLeftAt(sessionID string, userID string, leftAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	for i, p := range session.Participants {
+		if p.UserID == userID {
+			session.Participants[i].LeftAt = leftAt
+			session.Participants[i].IsReady = false
+			return nil
+		}
+	}
+
+	return fmt.Errorf("participant with userID %s not found in session %s", userID, sessionID)
+}
+
+func (r *SessionRepository) UpdateParticipantCoHost(sessionID string, userID string, isCoHost bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	for i, p := range session.Participants {
+		if p.UserID == userID {
+			session.Participants[i].IsCoHost = isCoHost
+			return nil
+		}
+	}
+
+	return fmt.Errorf("participant with userID %s not found in session %s", userID, sessionID)
+}
+
+func (r *SessionRepository) UpdateParticipantBannedAt(sessionID string, userID string, bannedAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	for i, p := range session.Participants {
+		if p.UserID == userID {
+			session.Participants[i].BannedAt = bannedAt
+			return nil
+		}
+	}
+
+	return fmt.Errorf("participant with userID %s not found in session %s", userID, sessionID)
+}
+
+func (r *SessionRepository) UpdateCreator(sessionID string, creatorID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	session.CreatorID = creatorID
+	session.UpdatedAt = time.Now()
+	return nil
+}
+
 func (r *SessionRepository) GetSessionsByStatus(status entity.SessionStatus) ([]*entity.Session, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -210,6 +374,269 @@ func (r *SessionRepository) GetSessionsByStatus(status entity.SessionStatus) ([]
 	return sessions, nil
 }
 
+func (r *SessionRepository) GetSessionsWithPhaseEndingBefore(deadline time.Time) ([]*entity.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.Status == entity.SessionStatusActive &&
+			session.PhaseEndsAt != nil &&
+			!session.PhaseEndsAt.After(deadline) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) UpdatePhase(session *entity.Session, previousPhaseEndsAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[session.ID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", session.ID)
+	}
+
+	if stored.Status != entity.SessionStatusActive ||
+		stored.PhaseEndsAt == nil ||
+		!stored.PhaseEndsAt.Equal(previousPhaseEndsAt) {
+		return false, nil
+	}
+
+	stored.CurrentPhase = session.CurrentPhase
+	stored.CurrentCycle = session.CurrentCycle
+	stored.PhaseIndex = session.PhaseIndex
+	stored.PhaseStartedAt = session.PhaseStartedAt
+	stored.PhaseEndsAt = session.PhaseEndsAt
+
+	return true, nil
+}
+
+func (r *SessionRepository) Start(session *entity.Session) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[session.ID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", session.ID)
+	}
+
+	if stored.Status != entity.SessionStatusPending {
+		return false, nil
+	}
+
+	stored.Status = entity.SessionStatusActive
+	stored.StartedAt = session.StartedAt
+	stored.AutoStartAt = nil
+	stored.CurrentCycle = session.CurrentCycle
+	stored.PhaseIndex = session.PhaseIndex
+	stored.CurrentPhase = session.CurrentPhase
+	stored.PhaseStartedAt = session.PhaseStartedAt
+	stored.PhaseEndsAt = session.PhaseEndsAt
+
+	return true, nil
+}
+
+func (r *SessionRepository) Pause(sessionID string, pausedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	if stored.Status != entity.SessionStatusActive {
+		return false, nil
+	}
+	stored.Status = entity.SessionStatusPaused
+	stored.PausedAt = &pausedAt
+
+	return true, nil
+}
+
+func (r *SessionRepository) Resume(session *entity.Session, pausedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[session.ID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", session.ID)
+	}
+
+	if stored.Status != entity.SessionStatusPaused ||
+		stored.PausedAt == nil ||
+		!stored.PausedAt.Equal(pausedAt) {
+		return false, nil
+	}
+
+	stored.Status = entity.SessionStatusActive
+	stored.PausedAt = nil
+	stored.TotalPauseTime = session.TotalPauseTime
+	stored.PhaseStartedAt = session.PhaseStartedAt
+	stored.PhaseEndsAt = session.PhaseEndsAt
+
+	return true, nil
+}
+
+func (r *SessionRepository) Finish(session *entity.Session, from entity.SessionStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[session.ID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", session.ID)
+	}
+
+	if stored.Status != from {
+		return false, nil
+	}
+
+	stored.Status = session.Status
+	stored.CompletedAt = session.CompletedAt
+	stored.PausedAt = nil
+	stored.TotalPauseTime = session.TotalPauseTime
+	stored.CurrentPhase = ""
+	stored.PhaseStartedAt = nil
+	stored.PhaseEndsAt = nil
+
+	return true, nil
+}
+
+func (r *SessionRepository) UpdateStatus(sessionID string, from, to entity.SessionStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	if stored.Status != from {
+		return false, nil
+	}
+	stored.Status = to
+
+	return true, nil
+}
+
+func (r *SessionRepository) GetScheduledSessionsBefore(deadline time.Time) ([]*entity.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.Status == entity.SessionStatusPending &&
+			session.ScheduledAt != nil &&
+			!session.ScheduledAt.After(deadline) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) MarkReminderSent(sessionID string, sentAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	if stored.ReminderSentAt != nil {
+		return false, nil
+	}
+	stored.ReminderSentAt = &sentAt
+
+	return true, nil
+}
+
+func (r *SessionRepository) GetUpcomingBySeriesID(seriesID string) ([]*entity.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.SeriesID != nil && *session.SeriesID == seriesID &&
+			session.Status == entity.SessionStatusPending {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return scheduledBefore(sessions[i], sessions[j])
+	})
+
+	return sessions, nil
+}
+
+// scheduledBefore упорядочивает сессии по времени старта; незапланированные — в конце
+func scheduledBefore(a, b *entity.Session) bool {
+	if a.ScheduledAt == nil || b.ScheduledAt == nil {
+		return a.ScheduledAt != nil
+	}
+	return a.ScheduledAt.Before(*b.ScheduledAt)
+}
+
+func (r *SessionRepository) GetPendingWithStartPolicy() ([]*entity.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.Status == entity.SessionStatusPending &&
+			session.StartPolicy != "" && session.StartPolicy != entity.StartPolicyManual {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) UpdateAutoStartAt(sessionID string, previous, next *time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	current := stored.AutoStartAt
+	if stored.Status != entity.SessionStatusPending ||
+		(current == nil) != (previous == nil) ||
+		(current != nil && !current.Equal(*previous)) {
+		return false, nil
+	}
+	stored.AutoStartAt = next
+
+	return true, nil
+}
+
+func (r *SessionRepository) CancelAutoStart(sessionID string, startsAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[sessionID]
+	if !exists {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	if session.Status != entity.SessionStatusPending || session.AutoStartAt == nil || !session.AutoStartAt.Equal(startsAt) {
+		return false, nil
+	}
+	session.AutoStartAt = nil
+	for i := range session.Participants {
+		session.Participants[i].IsReady = false
+	}
+
+	return true, nil
+}
+
 func (r *SessionRepository) GetAll() ([]*entity.Session, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type SessionSeriesRepository struct {
+	series      map[string]*entity.SessionSeries
+	inviteLinks map[string]string                                     // inviteLink -> seriesID
+	subscribers map[string]map[string]*entity.SessionSeriesSubscriber // seriesID -> userID -> subscriber
+	mu          sync.RWMutex
+}
+
+func NewSessionSeriesRepository() interfaces.SessionSeriesRepository {
+	return &SessionSeriesRepository{
+		series:      make(map[string]*entity.SessionSeries),
+		inviteLinks: make(map[string]string),
+		subscribers: make(map[string]map[string]*entity.SessionSeriesSubscriber),
+	}
+}
+
+func (r *SessionSeriesRepository) Create(series *entity.SessionSeries) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.series[series.ID]; exists {
+		return fmt.Errorf("series with ID %s already exists", series.ID)
+	}
+	if _, exists := r.inviteLinks[series.InviteLink]; exists {
+		return fmt.Errorf("series with invite link %s already exists", series.InviteLink)
+	}
+
+	r.series[series.ID] = series
+	r.inviteLinks[series.InviteLink] = series.ID
+	return nil
+}
+
+func (r *SessionSeriesRepository) GetByID(id string) (*entity.SessionSeries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Как и gorm-репозиторий, отсутствие серии — не ошибка
+	return r.series[id], nil
+}
+
+func (r *SessionSeriesRepository) GetByInviteLink(inviteLink string) (*entity.SessionSeries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seriesID, exists := r.inviteLinks[inviteLink]
+	if !exists {
+		return nil, nil
+	}
+
+	return r.series[seriesID], nil
+}
+
+func (r *SessionSeriesRepository) GetAll() ([]*entity.SessionSeries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.SessionSeries, 0, len(r.series))
+	for _, series := range r.series {
+		result = append(result, series)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (r *SessionSeriesRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	series, exists := r.series[id]
+	if !exists {
+		return fmt.Errorf("series with ID %s not found", id)
+	}
+
+	delete(r.inviteLinks, series.InviteLink)
+	delete(r.subscribers, id)
+	delete(r.series, id)
+	return nil
+}
+
+func (r *SessionSeriesRepository) AdvanceGeneratedUntil(seriesID string, previous *time.Time, next time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	series, exists := r.series[seriesID]
+	if !exists {
+		return false, fmt.Errorf("series with ID %s not found", seriesID)
+	}
+
+	current := series.GeneratedUntil
+	if (current == nil) != (previous == nil) || (current != nil && !current.Equal(*previous)) {
+		return false, nil
+	}
+
+	series.GeneratedUntil = &next
+	return true, nil
+}
+
+func (r *SessionSeriesRepository) AddSubscriber(subscriber *entity.SessionSeriesSubscriber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.series[subscriber.SeriesID]; !exists {
+		return fmt.Errorf("series with ID %s not found", subscriber.SeriesID)
+	}
+
+	if r.subscribers[subscriber.SeriesID] == nil {
+		r.subscribers[subscriber.SeriesID] = make(map[string]*entity.SessionSeriesSubscriber)
+	}
+	if _, exists := r.subscribers[subscriber.SeriesID][subscriber.UserID]; !exists {
+		r.subscribers[subscriber.SeriesID][subscriber.UserID] = subscriber
+	}
+	return nil
+}
+
+func (r *SessionSeriesRepository) RemoveSubscriber(seriesID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.subscribers[seriesID], userID)
+	return nil
+}
+
+func (r *SessionSeriesRepository) GetSubscribers(seriesID string) ([]*entity.SessionSeriesSubscriber, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.SessionSeriesSubscriber, 0, len(r.subscribers[seriesID]))
+	for _, subscriber := range r.subscribers[seriesID] {
+		result = append(result, subscriber)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SubscribedAt.Before(result[j].SubscribedAt)
+	})
+
+	return result, nil
+}
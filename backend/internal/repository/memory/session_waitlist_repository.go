@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+type SessionWaitlistRepository struct {
+	entries map[string]map[string]*entity.SessionWaitlistEntry // sessionID -> userID -> entry
+	mu      sync.RWMutex
+}
+
+func NewSessionWaitlistRepository() interfaces.SessionWaitlistRepository {
+	return &SessionWaitlistRepository{
+		entries: make(map[string]map[string]*entity.SessionWaitlistEntry),
+	}
+}
+
+func (r *SessionWaitlistRepository) Add(entry *entity.SessionWaitlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.entries[entry.SessionID] == nil {
+		r.entries[entry.SessionID] = make(map[string]*entity.SessionWaitlistEntry)
+	}
+	if _, exists := r.entries[entry.SessionID][entry.UserID]; !exists {
+		r.entries[entry.SessionID][entry.UserID] = entry
+	}
+	return nil
+}
+
+func (r *SessionWaitlistRepository) Remove(sessionID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries[sessionID], userID)
+	return nil
+}
+
+func (r *SessionWaitlistRepository) GetBySessionID(sessionID string) ([]*entity.SessionWaitlistEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.SessionWaitlistEntry, 0, len(r.entries[sessionID]))
+	for _, entry := range r.entries[sessionID] {
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
@@ -112,3 +112,15 @@ func (r *TaskRepository) Delete(id string) error {
 	delete(r.tasks, id)
 	return nil
 }
+
+// deleteBySessionID удаляет задачи сессии при ее удалении
+func (r *TaskRepository) deleteBySessionID(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, task := range r.tasks {
+		if task.SessionID == sessionID {
+			delete(r.tasks, id)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
@@ -11,7 +13,8 @@ import (
 type UserRepository struct {
 	users      map[string]*entity.User
 	stats      map[string]*entity.UserStats
-	telegramID map[int64]string // маппинг telegramUserID -> userID
+	results    map[string]map[string]*entity.SessionResult // sessionID -> userID -> итог
+	telegramID map[int64]string                            // маппинг telegramUserID -> userID
 	mu         sync.RWMutex
 }
 
@@ -19,6 +22,7 @@ func NewUserRepository() interfaces.UserRepository {
 	return &UserRepository{
 		users:      make(map[string]*entity.User),
 		stats:      make(map[string]*entity.UserStats),
+		results:    make(map[string]map[string]*entity.SessionResult),
 		telegramID: make(map[int64]string),
 	}
 }
@@ -50,6 +54,20 @@ func (r *UserRepository) GetByID(id string) (*entity.User, error) {
 	return user, nil
 }
 
+func (r *UserRepository) GetByIDs(ids []string) ([]*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*entity.User, 0, len(ids))
+	for _, id := range ids {
+		if user, exists := r.users[id]; exists {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) GetByTelegramUserID(telegramUserID int64) (*entity.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -79,6 +97,36 @@ func (r *UserRepository) Update(user *entity.User) error {
 	return nil
 }
 
+func (r *UserRepository) SetInviteCode(userID string, inviteCode string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[userID]
+	if !exists {
+		return false, fmt.Errorf("user with ID %s not found", userID)
+	}
+	if user.InviteCode != nil {
+		return false, nil
+	}
+
+	user.InviteCode = &inviteCode
+	user.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (r *UserRepository) GetByInviteCode(inviteCode string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.InviteCode != nil && *user.InviteCode == inviteCode {
+			return user, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user with invite code %s not found", inviteCode)
+}
+
 func (r *UserRepository) UpdateStats(userID string, stats *entity.UserStats) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -102,3 +150,118 @@ func (r *UserRepository) GetStats(userID string) (*entity.UserStats, error) {
 
 	return stats, nil
 }
+
+func (r *UserRepository) GetSessionResults(userID string, from, to time.Time) ([]*entity.SessionResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []*entity.SessionResult
+	for _, bySession := range r.results {
+		result, exists := bySession[userID]
+		if !exists || result.CompletedAt.Before(from) || !result.CompletedAt.Before(to) {
+			continue
+		}
+		copied := *result
+		results = append(results, &copied)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CompletedAt.Before(results[j].CompletedAt)
+	})
+
+	return results, nil
+}
+
+func (r *UserRepository) RecordSessionResult(result *entity.SessionResult, loc *time.Location) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.results[result.SessionID][result.UserID]; exists {
+		return false, nil
+	}
+	if r.results[result.SessionID] == nil {
+		r.results[result.SessionID] = make(map[string]*entity.SessionResult)
+	}
+	r.results[result.SessionID][result.UserID] = result
+
+	stats, exists := r.stats[result.UserID]
+	if !exists {
+		stats = &entity.UserStats{}
+		r.stats[result.UserID] = stats
+	}
+	stats.UserID = result.UserID
+	stats.AddSession(result, loc)
+
+	return true, nil
+}
+
+func (r *UserRepository) RebuildStats(results []*entity.SessionResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, result := range results {
+		if r.results[result.SessionID] == nil {
+			r.results[result.SessionID] = make(map[string]*entity.SessionResult)
+		}
+		r.results[result.SessionID][result.UserID] = result
+	}
+
+	var ledger []*entity.SessionResult
+	for _, bySession := range r.results {
+		for _, result := range bySession {
+			ledger = append(ledger, result)
+		}
+	}
+
+	stats := entity.BuildStats(ledger, func(userID string) *time.Location {
+		if user, exists := r.users[userID]; exists {
+			return user.Location()
+		}
+		return time.UTC
+	})
+	for userID := range r.stats {
+		if _, exists := stats[userID]; !exists {
+			stats[userID] = &entity.UserStats{UserID: userID}
+		}
+	}
+	r.stats = stats
+
+	return nil
+}
+
+func (r *UserRepository) GetStreakTimezones() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var timezones []string
+	for userID, stats := range r.stats {
+		user, exists := r.users[userID]
+		if !exists || stats.CurrentStreak == 0 || seen[user.Timezone] {
+			continue
+		}
+		seen[user.Timezone] = true
+		timezones = append(timezones, user.Timezone)
+	}
+
+	return timezones, nil
+}
+
+func (r *UserRepository) ResetLapsedStreaks(timezone string, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Серия прервана, если с последней сессии до before пропущен хотя бы один день
+	today := before.AddDate(0, 0, 1)
+	reset := 0
+	for userID, stats := range r.stats {
+		user, exists := r.users[userID]
+		if !exists || user.Timezone != timezone || !stats.StreakLapsed(today) {
+			continue
+		}
+		stats.CurrentStreak = 0
+		reset++
+	}
+
+	return reset, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+const focus100hMinutes = 100 * 60
+
+// achievementRule — условие получения значка по завершению сессии участником
+type achievementRule struct {
+	code     entity.AchievementCode
+	unlocked func(event *entity.AchievementEvent) bool
+}
+
+// achievementRules проверяются по порядку после учета каждой сессии в статистике
+var achievementRules = []achievementRule{
+	{
+		code: entity.AchievementFirstGroupSession,
+		unlocked: func(event *entity.AchievementEvent) bool {
+			return event.Mode == entity.SessionModeGroup && event.Participants >= 2
+		},
+	},
+	{
+		code: entity.AchievementStreak7,
+		unlocked: func(event *entity.AchievementEvent) bool {
+			return event.Stats.CurrentStreak >= 7
+		},
+	},
+	{
+		code: entity.AchievementFocus100h,
+		unlocked: func(event *entity.AchievementEvent) bool {
+			return event.Stats.TotalFocusTime >= focus100hMinutes
+		},
+	},
+	{
+		code: entity.AchievementPerfect5,
+		unlocked: func(event *entity.AchievementEvent) bool {
+			return event.Stats.PerfectStreak >= 5
+		},
+	},
+}
+
+// unlockAchievements проверяет условия значков по только что учтенной сессии участника и сохраняет выполненные.
+// stats — статистика участника с учетом этой сессии. Возвращает только новые значки: полученный ранее значок повторно не выдается
+func (s *SessionService) unlockAchievements(session *entity.Session, result *entity.SessionResult, stats *entity.UserStats) ([]*entity.UserAchievement, error) {
+	event := &entity.AchievementEvent{
+		Result:       result,
+		Stats:        stats,
+		Mode:         session.Mode,
+		Participants: activeParticipants(session),
+	}
+
+	var unlocked []*entity.UserAchievement
+	for _, rule := range achievementRules {
+		if !rule.unlocked(event) {
+			continue
+		}
+
+		sessionID := result.SessionID
+		achievement := &entity.UserAchievement{
+			UserID:     result.UserID,
+			Code:       rule.code,
+			SessionID:  &sessionID,
+			UnlockedAt: result.CompletedAt,
+		}
+		ok, err := s.achievementRepo.Unlock(achievement)
+		if err != nil {
+			return unlocked, err
+		}
+		if ok {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+
+	return unlocked, nil
+}
+
+// announceAchievements поздравляет получивших значки в личных сообщениях Telegram. Ошибки доставки только логируются
+func (s *SessionService) announceAchievements(achievements []*entity.UserAchievement) {
+	for _, achievement := range achievements {
+		log.Printf("[SessionService] 🏅 User %s unlocked achievement %s\n", achievement.UserID, achievement.Code)
+
+		user, err := s.userRepo.GetByID(achievement.UserID)
+		if err != nil || user == nil || user.TelegramUserID == 0 {
+			continue
+		}
+
+		text := fmt.Sprintf("🏅 Новый значок «%s»: %s", achievement.Code.Title(), achievement.Code.Description())
+		if _, err := s.telegramAPIService.SendMessageToUser(user.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+			log.Printf("[SessionService] ❌ Failed to announce achievement %s to user %s: %v\n", achievement.Code, achievement.UserID, err)
+		}
+	}
+}
+
+// activeParticipants возвращает число участников сессии без исключенных
+func activeParticipants(session *entity.Session) int {
+	count := 0
+	for _, participant := range session.Participants {
+		if participant.BannedAt == nil {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+)
+
+// announcements возвращает, сколько поздравлений со значками отправлено пользователю в Telegram
+func (e *testEnv) announcements(tgID int64) int {
+	count := 0
+	for _, message := range e.telegram.messages(tgID) {
+		if strings.HasPrefix(message, "🏅") {
+			count++
+		}
+	}
+	return count
+}
+
+// achievementCodes возвращает значки пользователя и сессии, за которые они получены
+func (e *testEnv) achievementCodes(t *testing.T, userID string) map[entity.AchievementCode]string {
+	t.Helper()
+
+	achievements, err := e.achievements.GetByUserID(userID)
+	if err != nil {
+		t.Fatalf("GetByUserID(%s): %v", userID, err)
+	}
+	codes := make(map[entity.AchievementCode]string, len(achievements))
+	for _, achievement := range achievements {
+		if _, exists := codes[achievement.Code]; exists {
+			t.Errorf("%s got %s twice", userID, achievement.Code)
+		}
+		sessionID := ""
+		if achievement.SessionID != nil {
+			sessionID = *achievement.SessionID
+		}
+		codes[achievement.Code] = sessionID
+	}
+	return codes
+}
+
+func TestAchievementUnlockedOnce(t *testing.T) {
+	env := newTestEnv(t, 2)
+
+	var sessionIDs []string
+	for i := 0; i < 2; i++ {
+		session := env.startedSession(t)
+		env.completeWithTasks(t, session, testUserID(1), testUserID(2))
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	// Значок за первую групповую сессию не выдается повторно за вторую
+	for i, userID := range []string{testUserID(1), testUserID(2)} {
+		codes := env.achievementCodes(t, userID)
+		if len(codes) != 1 || codes[entity.AchievementFirstGroupSession] != sessionIDs[0] {
+			t.Errorf("achievements of %s = %v, want %s for session %s", userID, codes, entity.AchievementFirstGroupSession, sessionIDs[0])
+		}
+		if got := env.announcements(int64(101 + i)); got != 1 {
+			t.Errorf("%s got %d achievement announcements, want 1", userID, got)
+		}
+	}
+}
+
+func TestRecomputeStatsBackfillsAchievements(t *testing.T) {
+	env := newTestEnv(t, 2)
+	session := env.startedSession(t)
+	env.completeWithTasks(t, session, testUserID(1), testUserID(2))
+
+	// Сессия завершена до появления значков: начинаем с пустого репозитория
+	env.achievements = memory.NewAchievementRepository()
+	env.service.achievementRepo = env.achievements
+	announced := env.announcements(101) + env.announcements(102)
+
+	// Повторный пересчет не выдает значки второй раз
+	for i := 0; i < 2; i++ {
+		if _, err := env.service.RecomputeStats(); err != nil {
+			t.Fatalf("RecomputeStats: %v", err)
+		}
+	}
+
+	completedAt := env.session(t, session.ID).CompletedAt
+	for _, userID := range []string{testUserID(1), testUserID(2)} {
+		codes := env.achievementCodes(t, userID)
+		if len(codes) != 1 || codes[entity.AchievementFirstGroupSession] != session.ID {
+			t.Errorf("achievements of %s = %v, want %s for session %s", userID, codes, entity.AchievementFirstGroupSession, session.ID)
+		}
+
+		achievements, err := env.achievements.GetByUserID(userID)
+		if err != nil {
+			t.Fatalf("GetByUserID(%s): %v", userID, err)
+		}
+		if len(achievements) == 1 && !achievements[0].UnlockedAt.Equal(*completedAt) {
+			t.Errorf("%s unlocked at %v, want session completion %v", userID, achievements[0].UnlockedAt, *completedAt)
+		}
+	}
+
+	// Значки за прошлые сессии выдаются без уведомлений
+	if got := env.announcements(101) + env.announcements(102); got != announced {
+		t.Errorf("recompute sent %d achievement announcements, want none", got-announced)
+	}
+}
@@ -35,7 +35,7 @@ func NewAuthService(
 	}
 }
 
-func (s *AuthService) Login(initData, deviceID string) (*entity.AuthTokens, *entity.User, error) {
+func (s *AuthService) Login(initData, deviceID, timezone string) (*entity.AuthTokens, *entity.User, error) {
 	_ = deviceID
 
 	fmt.Printf("[Auth Service] 📥 Login attempt\n")
@@ -84,6 +84,12 @@ func (s *AuthService) Login(initData, deviceID string) (*entity.AuthTokens, *ent
 		avatarURL = &avatar
 	}
 
+	// Часовой пояс устройства запоминаем, только если он известен; неизвестный не мешает входу
+	if _, err := entity.LoadTimezone(timezone); err != nil {
+		fmt.Printf("[Auth Service] ⚠️ Ignoring unknown timezone: %q\n", timezone)
+		timezone = ""
+	}
+
 	user, err := s.userRepo.GetByTelegramUserID(initUser.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get user: %w", err)
@@ -97,6 +103,7 @@ func (s *AuthService) Login(initData, deviceID string) (*entity.AuthTokens, *ent
 			Name:           displayName,
 			AvatarURL:      avatarURL,
 			TelegramUserID: initUser.ID,
+			Timezone:       timezone,
 			CreatedAt:      now,
 			UpdatedAt:      now,
 		}
@@ -117,6 +124,12 @@ func (s *AuthService) Login(initData, deviceID string) (*entity.AuthTokens, *ent
 			needsUpdate = true
 		}
 
+		// Выбранный в настройках пояс не перезаписываем
+		if user.Timezone == "" && timezone != "" {
+			user.Timezone = timezone
+			needsUpdate = true
+		}
+
 		if needsUpdate {
 			user.UpdatedAt = now
 			if err := s.userRepo.Update(user); err != nil {
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+// testTelegram записывает отправленные личные сообщения; остальные методы Telegram тестам не нужны
+type testTelegram struct {
+	interfaces.TelegramAPIService
+
+	mu   sync.Mutex
+	sent map[int64][]string
+}
+
+func (t *testTelegram) SendMessageToUser(userID int64, message *telegramapi.SendMessageRequest) (*telegramapi.SendMessageResponse, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sent[userID] = append(t.sent[userID], message.Text)
+	return &telegramapi.SendMessageResponse{}, nil
+}
+
+func (t *testTelegram) AnswerCallback(callbackID string, text string) error {
+	return nil
+}
+
+func (t *testTelegram) messages(userID int64) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string(nil), t.sent[userID]...)
+}
+
+// testEvent — событие, разосланное через SessionNotifier
+type testEvent struct {
+	target string // сессия или пользователь
+	name   string
+	data   interface{}
+}
+
+type testNotifier struct {
+	mu     sync.Mutex
+	events []testEvent
+}
+
+func (n *testNotifier) SendToSession(sessionID string, event string, data interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.events = append(n.events, testEvent{target: sessionID, name: event, data: data})
+}
+
+func (n *testNotifier) SendToUser(userID string, event string, data interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.events = append(n.events, testEvent{target: userID, name: event, data: data})
+}
+
+// count возвращает, сколько раз событие отправлено адресату
+func (n *testNotifier) count(target string, event string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	count := 0
+	for _, e := range n.events {
+		if e.target == target && e.name == event {
+			count++
+		}
+	}
+	return count
+}
+
+// testEnv — SessionService на репозиториях в памяти
+type testEnv struct {
+	service      *SessionService
+	sessions     interfaces.SessionRepository
+	users        *memory.UserRepository
+	tasks        *memory.TaskRepository
+	segments     *memory.PhaseSegmentRepository
+	joinRequests interfaces.SessionJoinRequestRepository
+	achievements interfaces.AchievementRepository
+	contacts     interfaces.ContactRepository
+	telegram     *testTelegram
+}
+
+// newTestEnv создает окружение с пользователями user1..userN; Telegram ID пользователя userK — 100+K
+func newTestEnv(t *testing.T, users int) *testEnv {
+	t.Helper()
+
+	tasks := memory.NewTaskRepository().(*memory.TaskRepository)
+	attendance := memory.NewAttendanceRepository().(*memory.AttendanceRepository)
+	segments := memory.NewPhaseSegmentRepository().(*memory.PhaseSegmentRepository)
+	userRepo := memory.NewUserRepository().(*memory.UserRepository)
+	for i := 1; i <= users; i++ {
+		if err := userRepo.Create(&entity.User{ID: testUserID(i), Name: testUserID(i), TelegramUserID: int64(100 + i)}); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	env := &testEnv{
+		sessions:     memory.NewSessionRepository(tasks, attendance, segments),
+		users:        userRepo,
+		tasks:        tasks,
+		segments:     segments,
+		joinRequests: memory.NewSessionJoinRequestRepository(),
+		achievements: memory.NewAchievementRepository(),
+		contacts:     memory.NewContactRepository(),
+		telegram:     &testTelegram{sent: make(map[int64][]string)},
+	}
+	env.service = NewSessionService(
+		env.sessions,
+		tasks,
+		segments,
+		attendance,
+		memory.NewSessionWaitlistRepository(),
+		env.joinRequests,
+		userRepo,
+		env.contacts,
+		env.achievements,
+		env.telegram,
+		10,
+	).(*SessionService)
+
+	return env
+}
+
+func testUserID(i int) string {
+	return fmt.Sprintf("user%d", i)
+}
+
+// groupSession создает групповую сессию user1 и добавляет в нее остальных участников
+func (e *testEnv) groupSession(t *testing.T, settings entity.SessionSettings, members ...string) *entity.Session {
+	t.Helper()
+
+	settings.Mode = entity.SessionModeGroup
+	if settings.FocusDuration == 0 {
+		settings.FocusDuration = 25
+		settings.BreakDuration = 5
+	}
+	session, err := e.service.CreateSession(testUserID(1), settings)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	for _, userID := range members {
+		if _, err := e.service.JoinSession(session.ID, userID); err != nil {
+			t.Fatalf("JoinSession(%s): %v", userID, err)
+		}
+	}
+	return session
+}
+
+func (e *testEnv) session(t *testing.T, sessionID string) *entity.Session {
+	t.Helper()
+
+	session, err := e.sessions.GetByID(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("GetByID(%s) = %v, %v", sessionID, session, err)
+	}
+	return session
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+const (
+	ScoringTaskWeighted  = "task-weighted"  // 10 очков за задачу и 1 за минуту фокуса
+	ScoringFocusWeighted = "focus-weighted" // 2 очка за задачу и 2 за минуту фокуса
+	ScoringConsistency   = "consistency"    // task-weighted с множителем за серию дней
+)
+
+const (
+	streakBonusPercent    = 10  // прибавка к очкам за каждый день серии
+	maxStreakBonusPercent = 100 // множитель не больше 2
+)
+
+// NewScoringStrategy возвращает стратегию подсчета очков по названию из конфига
+func NewScoringStrategy(name string) (interfaces.ScoringStrategy, error) {
+	switch name {
+	case ScoringTaskWeighted, "":
+		return &weightedScoring{name: ScoringTaskWeighted, perTask: 10, perFocusMinute: 1}, nil
+	case ScoringFocusWeighted:
+		return &weightedScoring{name: ScoringFocusWeighted, perTask: 2, perFocusMinute: 2}, nil
+	case ScoringConsistency:
+		return &consistencyScoring{base: weightedScoring{name: ScoringConsistency, perTask: 10, perFocusMinute: 1}}, nil
+	default:
+		return nil, fmt.Errorf("unknown scoring strategy: %s", name)
+	}
+}
+
+// weightedScoring начисляет фиксированные очки за каждую задачу и минуту фокуса
+type weightedScoring struct {
+	name           string
+	perTask        int
+	perFocusMinute int
+}
+
+func (s *weightedScoring) Name() string {
+	return s.name
+}
+
+func (s *weightedScoring) Score(entry *entity.LeaderboardEntry) entity.ScoreBreakdown {
+	return entity.ScoreBreakdown{
+		Strategy:    s.name,
+		TaskPoints:  entry.TasksCompleted * s.perTask,
+		FocusPoints: entry.FocusTime * s.perFocusMinute,
+		Multiplier:  1,
+	}
+}
+
+// consistencyScoring поощряет регулярность: очки растут на 10% за каждый день серии, но не больше чем вдвое.
+// Серия берется текущая, а не на конец окна: итоги прошлых периодов тоже умножаются на сегодняшнюю серию
+type consistencyScoring struct {
+	base weightedScoring
+}
+
+func (s *consistencyScoring) Name() string {
+	return s.base.name
+}
+
+func (s *consistencyScoring) Score(entry *entity.LeaderboardEntry) entity.ScoreBreakdown {
+	breakdown := s.base.Score(entry)
+
+	bonus := entry.CurrentStreak * streakBonusPercent
+	if bonus > maxStreakBonusPercent {
+		bonus = maxStreakBonusPercent
+	}
+	breakdown.Multiplier = float64(100+bonus) / 100
+
+	return breakdown
+}
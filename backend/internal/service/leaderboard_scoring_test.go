@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func TestNewScoringStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "default", strategy: "", wantName: ScoringTaskWeighted},
+		{name: "task weighted", strategy: ScoringTaskWeighted, wantName: ScoringTaskWeighted},
+		{name: "focus weighted", strategy: ScoringFocusWeighted, wantName: ScoringFocusWeighted},
+		{name: "consistency", strategy: ScoringConsistency, wantName: ScoringConsistency},
+		{name: "unknown", strategy: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewScoringStrategy(tt.strategy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewScoringStrategy(%q) succeeded, want error", tt.strategy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewScoringStrategy(%q) error: %v", tt.strategy, err)
+			}
+			if strategy.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", strategy.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestScoringStrategies(t *testing.T) {
+	tests := []struct {
+		name           string
+		strategy       string
+		entry          entity.LeaderboardEntry
+		wantTask       int
+		wantFocus      int
+		wantMultiplier float64
+		wantTotal      int
+	}{
+		{name: "task weighted", strategy: ScoringTaskWeighted, entry: entity.LeaderboardEntry{TasksCompleted: 3, FocusTime: 50, CurrentStreak: 4}, wantTask: 30, wantFocus: 50, wantMultiplier: 1, wantTotal: 80},
+		{name: "focus weighted", strategy: ScoringFocusWeighted, entry: entity.LeaderboardEntry{TasksCompleted: 3, FocusTime: 50}, wantTask: 6, wantFocus: 100, wantMultiplier: 1, wantTotal: 106},
+		{name: "consistency without streak", strategy: ScoringConsistency, entry: entity.LeaderboardEntry{TasksCompleted: 3, FocusTime: 50}, wantTask: 30, wantFocus: 50, wantMultiplier: 1, wantTotal: 80},
+		{name: "consistency with streak", strategy: ScoringConsistency, entry: entity.LeaderboardEntry{TasksCompleted: 3, FocusTime: 50, CurrentStreak: 5}, wantTask: 30, wantFocus: 50, wantMultiplier: 1.5, wantTotal: 120},
+		{name: "consistency bonus is capped", strategy: ScoringConsistency, entry: entity.LeaderboardEntry{TasksCompleted: 3, FocusTime: 50, CurrentStreak: 30}, wantTask: 30, wantFocus: 50, wantMultiplier: 2, wantTotal: 160},
+		{name: "empty entry", strategy: ScoringConsistency, entry: entity.LeaderboardEntry{CurrentStreak: 3}, wantMultiplier: 1.3, wantTotal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewScoringStrategy(tt.strategy)
+			if err != nil {
+				t.Fatalf("NewScoringStrategy(%q) error: %v", tt.strategy, err)
+			}
+
+			breakdown := strategy.Score(&tt.entry)
+			if breakdown.Strategy != tt.strategy {
+				t.Errorf("Strategy = %q, want %q", breakdown.Strategy, tt.strategy)
+			}
+			if breakdown.TaskPoints != tt.wantTask || breakdown.FocusPoints != tt.wantFocus {
+				t.Errorf("points = %d/%d, want %d/%d", breakdown.TaskPoints, breakdown.FocusPoints, tt.wantTask, tt.wantFocus)
+			}
+			if breakdown.Multiplier != tt.wantMultiplier {
+				t.Errorf("Multiplier = %v, want %v", breakdown.Multiplier, tt.wantMultiplier)
+			}
+			if got := breakdown.Total(); got != tt.wantTotal {
+				t.Errorf("Total() = %d, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
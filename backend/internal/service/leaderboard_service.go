@@ -2,6 +2,8 @@ package service
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
@@ -11,26 +13,35 @@ type LeaderboardService struct {
 	leaderboardRepo interfaces.LeaderboardRepository
 	sessionRepo     interfaces.SessionRepository
 	userRepo        interfaces.UserRepository
+	contactRepo     interfaces.ContactRepository
+	scoring         interfaces.ScoringStrategy
 }
 
 func NewLeaderboardService(
 	leaderboardRepo interfaces.LeaderboardRepository,
 	sessionRepo interfaces.SessionRepository,
 	userRepo interfaces.UserRepository,
+	contactRepo interfaces.ContactRepository,
+	scoring interfaces.ScoringStrategy,
 ) interfaces.LeaderboardService {
 	return &LeaderboardService{
 		leaderboardRepo: leaderboardRepo,
 		sessionRepo:     sessionRepo,
 		userRepo:        userRepo,
+		contactRepo:     contactRepo,
+		scoring:         scoring,
 	}
 }
 
 // GetSessionLeaderboard возвращает лидерборд для сессии
 func (s *LeaderboardService) GetSessionLeaderboard(sessionID string, userID string) ([]*entity.LeaderboardEntry, error) {
 	// Проверяем доступ к сессии
-	_, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.sessionRepo.GetByID(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, entity.ErrSessionNotFound
 	}
 
 	// Получаем записи лидерборда из репозитория
@@ -39,44 +50,81 @@ func (s *LeaderboardService) GetSessionLeaderboard(sessionID string, userID stri
 		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
 	}
 
-	// Заполняем информацию о пользователях
-	result := make([]*entity.LeaderboardEntry, 0, len(entries))
-	for _, entry := range entries {
-		user, err := s.userRepo.GetByID(entry.UserID)
-		if err != nil {
-			// Пропускаем пользователей, которых нет в БД
-			continue
-		}
-
-		entry.UserName = user.Name
-		entry.AvatarURL = user.AvatarURL
-		result = append(result, entry)
-	}
-
-	return result, nil
+	return s.rank(entries, 0), nil
 }
 
 // GetGlobalLeaderboard возвращает глобальный лидерборд
 func (s *LeaderboardService) GetGlobalLeaderboard(userID string, period entity.LeaderboardPeriod, limit int) ([]*entity.LeaderboardEntry, error) {
 	// Получаем записи лидерборда из репозитория
-	entries, err := s.leaderboardRepo.GetGlobalLeaderboard(period, limit)
+	entries, err := s.leaderboardRepo.GetGlobalLeaderboard(s.periodSince(userID, period))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get global leaderboard: %w", err)
 	}
 
-	// Заполняем информацию о пользователях
+	return s.rank(entries, limit), nil
+}
+
+// GetFriendsLeaderboard возвращает лидерборд пользователя среди его контактов. В нем есть все контакты
+// и сам пользователь, даже без завершенных за период сессий
+func (s *LeaderboardService) GetFriendsLeaderboard(userID string, period entity.LeaderboardPeriod) ([]*entity.LeaderboardEntry, error) {
+	contacts, err := s.contactRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(contacts)+1)
+	userIDs = append(userIDs, userID)
+	for _, contact := range contacts {
+		userIDs = append(userIDs, contact.ContactID)
+	}
+
+	entries, err := s.leaderboardRepo.GetUsersLeaderboard(userIDs, s.periodSince(userID, period))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends leaderboard: %w", err)
+	}
+
+	return s.rank(entries, 0), nil
+}
+
+// rank считает очки записей по стратегии из конфига, сортирует их и проставляет места; limit 0 — без ограничения.
+// Имя, аватар и серию пользователя репозиторий отдает вместе с итогами. Очки считает стратегия, поэтому
+// ограничить выборку в запросе нельзя: сортировка и limit — здесь
+func (s *LeaderboardService) rank(entries []*entity.LeaderboardEntry, limit int) []*entity.LeaderboardEntry {
 	result := make([]*entity.LeaderboardEntry, 0, len(entries))
 	for _, entry := range entries {
-		user, err := s.userRepo.GetByID(entry.UserID)
-		if err != nil {
-			// Пропускаем пользователей, которых нет в БД
-			continue
+		entry.Breakdown = s.scoring.Score(entry)
+		entry.Score = entry.Breakdown.Total()
+		result = append(result, entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
 		}
+		if result[i].TasksCompleted != result[j].TasksCompleted {
+			return result[i].TasksCompleted > result[j].TasksCompleted
+		}
+		if result[i].FocusTime != result[j].FocusTime {
+			return result[i].FocusTime > result[j].FocusTime
+		}
+		return result[i].UserID < result[j].UserID
+	})
 
-		entry.UserName = user.Name
-		entry.AvatarURL = user.AvatarURL
-		result = append(result, entry)
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	for i := range result {
+		result[i].Rank = i + 1
 	}
 
-	return result, nil
+	return result
+}
+
+// periodSince возвращает начало окна периода; границы дня — по часовому поясу пользователя
+func (s *LeaderboardService) periodSince(userID string, period entity.LeaderboardPeriod) *time.Time {
+	loc := time.UTC
+	if user, err := s.userRepo.GetByID(userID); err == nil && user != nil {
+		loc = user.Location()
+	}
+	return period.Since(time.Now(), loc)
 }
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+)
+
+// completeWithTasks завершает сессию, в которой каждый из участников выполнил по задаче
+func (e *testEnv) completeWithTasks(t *testing.T, session *entity.Session, userIDs ...string) {
+	t.Helper()
+
+	for _, userID := range userIDs {
+		task, err := e.service.AddTask(session.ID, userID, "task")
+		if err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+		if _, err := e.service.UpdateTask(session.ID, task.ID, userID, true); err != nil {
+			t.Fatalf("UpdateTask: %v", err)
+		}
+	}
+	if _, err := e.service.CompleteSession(session.ID, testUserID(1)); err != nil {
+		t.Fatalf("CompleteSession: %v", err)
+	}
+}
+
+// contactSources возвращает источники контактов пользователя по ID контакта
+func contactSources(t *testing.T, env *testEnv, userID string) map[string]entity.ContactSource {
+	t.Helper()
+
+	contacts, err := env.contacts.GetByUserID(userID)
+	if err != nil {
+		t.Fatalf("GetByUserID(%s): %v", userID, err)
+	}
+	result := make(map[string]entity.ContactSource, len(contacts))
+	for _, contact := range contacts {
+		result[contact.ContactID] = contact.Source
+	}
+	return result
+}
+
+func TestFriendsLeaderboard(t *testing.T) {
+	env := newTestEnv(t, 4)
+	scoring, err := NewScoringStrategy(ScoringTaskWeighted)
+	if err != nil {
+		t.Fatalf("NewScoringStrategy: %v", err)
+	}
+	leaderboard := NewLeaderboardService(memory.NewLeaderboardRepository(env.users), env.sessions, env.users, env.contacts, scoring)
+	users := NewUserService(env.users, env.contacts, env.achievements, env.sessions)
+
+	// user1 и user2 завершают общую сессию, затем user1 — еще одну в одиночку
+	env.completeWithTasks(t, env.startedSession(t), testUserID(1), testUserID(2))
+	solo := env.groupSession(t, entity.SessionSettings{})
+	if _, err := env.service.StartSession(solo.ID, testUserID(1), true); err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	env.completeWithTasks(t, solo, testUserID(1))
+
+	// user3 подписывается на user1 по Telegram ID; подписка односторонняя
+	if _, err := users.FollowUser(testUserID(3), entity.FollowTarget{TelegramUserID: 101}); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+
+	for _, tt := range []struct {
+		userID string
+		want   map[string]entity.ContactSource
+	}{
+		{userID: testUserID(1), want: map[string]entity.ContactSource{testUserID(2): entity.ContactSourceSession}},
+		{userID: testUserID(2), want: map[string]entity.ContactSource{testUserID(1): entity.ContactSourceSession}},
+		{userID: testUserID(3), want: map[string]entity.ContactSource{testUserID(1): entity.ContactSourceFollow}},
+		{userID: testUserID(4), want: map[string]entity.ContactSource{}},
+	} {
+		got := contactSources(t, env, tt.userID)
+		if len(got) != len(tt.want) {
+			t.Errorf("contacts of %s = %v, want %v", tt.userID, got, tt.want)
+			continue
+		}
+		for contactID, source := range tt.want {
+			if got[contactID] != source {
+				t.Errorf("contacts of %s = %v, want %v", tt.userID, got, tt.want)
+				break
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		want   []string // по местам
+	}{
+		{name: "session contacts", userID: testUserID(1), want: []string{testUserID(1), testUserID(2)}},
+		{name: "follower without results", userID: testUserID(3), want: []string{testUserID(1), testUserID(3)}},
+		{name: "no contacts", userID: testUserID(4), want: []string{testUserID(4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := leaderboard.GetFriendsLeaderboard(tt.userID, entity.LeaderboardPeriodAll)
+			if err != nil {
+				t.Fatalf("GetFriendsLeaderboard: %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("leaderboard has %d entries, want %v", len(entries), tt.want)
+			}
+			for i, entry := range entries {
+				if entry.UserID != tt.want[i] || entry.Rank != i+1 {
+					t.Errorf("place %d = %s (rank %d), want %s", i+1, entry.UserID, entry.Rank, tt.want[i])
+				}
+			}
+		})
+	}
+}
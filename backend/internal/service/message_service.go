@@ -39,7 +39,7 @@ func (s *MessageService) GetMessages(sessionID string, userID string, before *ti
 
 	// Проверяем, что чат создан
 	if session.TelegramChatID == nil {
-		return nil, fmt.Errorf("chat not created for this session")
+		return nil, &entity.DomainError{Kind: entity.ErrChatNotFound, Reason: "chat not created for this session"}
 	}
 
 	// Преобразуем before в Unix timestamp в миллисекундах для Telegram API
@@ -92,7 +92,7 @@ func (s *MessageService) SendMessage(sessionID string, userID string, text strin
 
 	// Проверяем, что чат создан
 	if session.TelegramChatID == nil {
-		return nil, fmt.Errorf("chat not created for this session")
+		return nil, &entity.DomainError{Kind: entity.ErrChatNotFound, Reason: "chat not created for this session"}
 	}
 
 	// Получаем информацию о пользователе
@@ -135,7 +135,7 @@ func (s *MessageService) GetChatInfo(sessionID string, userID string) (*entity.T
 
 	// Проверяем, что чат создан
 	if session.TelegramChatID == nil {
-		return nil, fmt.Errorf("chat not created for this session")
+		return nil, &entity.DomainError{Kind: entity.ErrChatNotFound, Reason: "chat not created for this session"}
 	}
 
 	// Получаем информацию о чате из Telegram API
@@ -40,7 +40,7 @@ func (s *SessionCleanupService) Start() {
 	}()
 }
 
-// cleanup removes stale pending sessions
+// cleanup removes stale pending sessions; scheduled sessions are left alone until their time has passed
 func (s *SessionCleanupService) cleanup() {
 	sessions, err := s.sessionRepo.GetSessionsByStatus(entity.SessionStatusPending)
 	if err != nil {
@@ -52,17 +52,32 @@ func (s *SessionCleanupService) cleanup() {
 	cleaned := 0
 
 	for _, session := range sessions {
-		age := now.Sub(session.CreatedAt)
+		// Scheduled sessions wait for their start time; the age counts from it, not from creation
+		since := session.CreatedAt
+		if session.ScheduledAt != nil {
+			if session.ScheduledAt.After(now) {
+				continue
+			}
+			since = *session.ScheduledAt
+		}
+
+		age := now.Sub(since)
 		if age > s.maxAge {
-			// Mark session as completed (or you could delete it)
-			completedAt := now
-			session.Status = entity.SessionStatusCompleted
-			session.CompletedAt = &completedAt
+			// Nobody started the session in time: cancel it rather than pretend it was completed
+			if _, err := checkTransition(session, entity.SessionActionCancel, actorSystem); err != nil {
+				log.Printf("[SessionCleanup] ❌ Cannot cancel session %s: %v\n", session.ID, err)
+				continue
+			}
 
-			if err := s.sessionRepo.Update(session); err != nil {
+			// Compare-and-set: the session may have been started while we were looking at it
+			cancelled, err := s.sessionRepo.UpdateStatus(session.ID, entity.SessionStatusPending, entity.SessionStatusCancelled)
+			if err != nil {
 				log.Printf("[SessionCleanup] ❌ Failed to cleanup session %s: %v\n", session.ID, err)
 				continue
 			}
+			if !cancelled {
+				continue
+			}
 
 			cleaned++
 			log.Printf("[SessionCleanup] 🧹 Cleaned up stale session: %s (age: %v)\n", session.ID, age)
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+// CloneSession создает новую ожидающую сессию с настройками прежней ("повторить").
+// Создателем копии становится вызывающий; переносятся только его задачи, по выбору — все или незавершенные.
+// Возвращает копию и ID участников, которым отправлено приглашение
+func (s *SessionService) CloneSession(sessionID string, userID string, options entity.CloneOptions) (*entity.Session, []string, error) {
+	source, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.CreatorID != userID && findParticipant(source, userID) == nil {
+		return nil, nil, entity.Forbidden("only participants can run a session again")
+	}
+
+	taskMode := options.Tasks
+	if taskMode == "" {
+		taskMode = entity.TaskCopyIncomplete
+	}
+	var titles []string
+	switch taskMode {
+	case entity.TaskCopyNone:
+	case entity.TaskCopyAll, entity.TaskCopyIncomplete:
+		tasks, err := s.taskRepo.GetBySessionIDAndUserID(sessionID, userID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		for _, task := range tasks {
+			if taskMode == entity.TaskCopyIncomplete && task.Completed {
+				continue
+			}
+			titles = append(titles, task.Title)
+		}
+	default:
+		return nil, nil, entity.InvalidArgument("invalid tasks mode: must be 'all', 'incomplete' or 'none'")
+	}
+
+	// Лимит мог уменьшиться с момента создания исходной сессии — тогда действует текущий
+	capacity := source.MaxParticipants
+	if s.maxSessionSize > 0 && capacity > s.maxSessionSize {
+		capacity = 0
+	}
+
+	clone, err := s.CreateSession(userID, entity.SessionSettings{
+		Mode:           source.Mode,
+		Tasks:          titles,
+		FocusDuration:  source.FocusDuration,
+		BreakDuration:  source.BreakDuration,
+		GroupName:      source.GroupName,
+		IsPrivate:      source.IsPrivate,
+		Approval:       source.RequireApproval,
+		StartPolicy:    source.StartPolicy,
+		StartQuorum:    source.StartQuorum,
+		StartCountdown: source.StartCountdown,
+		Capacity:       capacity,
+		Plan: entity.CyclePlan{
+			Cycles:         source.Cycles,
+			LongBreak:      source.LongBreak,
+			LongBreakEvery: source.LongBreakEvery,
+			Phases:         source.PhasePlan,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("[SessionService] 🔁 Session %s cloned from %s by user %s\n", clone.ID, sessionID, userID)
+
+	var invited []string
+	if options.Reinvite {
+		invited = s.reinviteParticipants(source, clone, userID)
+	}
+
+	return clone, invited, nil
+}
+
+// reinviteParticipants отправляет прежним участникам (кроме исключенных) приглашение в повтор сессии.
+// Ошибки доставки только логируются: копия уже создана
+func (s *SessionService) reinviteParticipants(source *entity.Session, clone *entity.Session, inviterID string) []string {
+	inviterName := "Участник"
+	if inviter, err := s.userRepo.GetByID(inviterID); err == nil && inviter != nil {
+		inviterName = inviter.Name
+	}
+	text := fmt.Sprintf("%s зовет вас повторить сессию «%s». Код приглашения: %s", inviterName, sessionTitle(clone), clone.InviteLink)
+
+	var invited []string
+	for _, participant := range source.Participants {
+		if participant.UserID == inviterID || participant.BannedAt != nil {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(participant.UserID)
+		if err != nil || user == nil || user.TelegramUserID == 0 {
+			log.Printf("[SessionService] ⚠️ Cannot re-invite user %s to session %s: %v\n", participant.UserID, clone.ID, err)
+			continue
+		}
+		if _, err := s.telegramAPIService.SendMessageToUser(user.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+			log.Printf("[SessionService] ⚠️ Failed to re-invite user %s to session %s: %v\n", participant.UserID, clone.ID, err)
+			continue
+		}
+		invited = append(invited, participant.UserID)
+	}
+	return invited
+}
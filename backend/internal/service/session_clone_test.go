@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// finishedSession создает и завершает сессию user1 с задачами "a" (выполнена) и "b", участником user2
+// с задачей "c" и исключенным user3
+func (e *testEnv) finishedSession(t *testing.T) *entity.Session {
+	t.Helper()
+
+	session := e.groupSession(t, entity.SessionSettings{
+		Tasks: []string{"a", "b"},
+		Plan:  entity.CyclePlan{Cycles: 3, LongBreak: 20, LongBreakEvery: 3},
+	}, testUserID(2), testUserID(3))
+	if _, err := e.service.RemoveParticipant(session.ID, testUserID(1), testUserID(3), true); err != nil {
+		t.Fatalf("RemoveParticipant: %v", err)
+	}
+	if _, err := e.service.StartSession(session.ID, testUserID(1), true); err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	tasks, err := e.tasks.GetBySessionIDAndUserID(session.ID, testUserID(1))
+	if err != nil {
+		t.Fatalf("GetBySessionIDAndUserID: %v", err)
+	}
+	for _, task := range tasks {
+		if task.Title == "a" {
+			if _, err := e.service.UpdateTask(session.ID, task.ID, testUserID(1), true); err != nil {
+				t.Fatalf("UpdateTask: %v", err)
+			}
+		}
+	}
+	if _, err := e.service.AddTask(session.ID, testUserID(2), "c"); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	if _, err := e.service.CompleteSession(session.ID, testUserID(1)); err != nil {
+		t.Fatalf("CompleteSession: %v", err)
+	}
+	return session
+}
+
+func TestCloneSessionTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		mode      entity.TaskCopyMode
+		wantTasks []string
+		wantErr   error
+	}{
+		{name: "incomplete by default", userID: testUserID(1), wantTasks: []string{"b"}},
+		{name: "all", userID: testUserID(1), mode: entity.TaskCopyAll, wantTasks: []string{"a", "b"}},
+		{name: "incomplete", userID: testUserID(1), mode: entity.TaskCopyIncomplete, wantTasks: []string{"b"}},
+		{name: "none", userID: testUserID(1), mode: entity.TaskCopyNone},
+		{name: "participant copies own tasks", userID: testUserID(2), mode: entity.TaskCopyAll, wantTasks: []string{"c"}},
+		{name: "unknown mode", userID: testUserID(1), mode: "some", wantErr: entity.ErrInvalidArgument},
+		{name: "outsider", userID: testUserID(4), wantErr: entity.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 4)
+			source := env.finishedSession(t)
+
+			clone, _, err := env.service.CloneSession(source.ID, tt.userID, entity.CloneOptions{Tasks: tt.mode})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CloneSession error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CloneSession: %v", err)
+			}
+
+			if clone.ID == source.ID || clone.CreatorID != tt.userID || clone.Status != entity.SessionStatusPending {
+				t.Errorf("clone %s by %s in status %s, want a new pending session of %s", clone.ID, clone.CreatorID, clone.Status, tt.userID)
+			}
+			if clone.Cycles != 3 || clone.LongBreak != 20 || clone.LongBreakEvery != 3 || clone.FocusDuration != source.FocusDuration {
+				t.Errorf("clone plan = %d cycles, long break %d every %d, want the source plan", clone.Cycles, clone.LongBreak, clone.LongBreakEvery)
+			}
+
+			tasks, err := env.tasks.GetBySessionID(clone.ID)
+			if err != nil {
+				t.Fatalf("GetBySessionID: %v", err)
+			}
+			var titles []string
+			for _, task := range tasks {
+				if task.Completed {
+					t.Errorf("cloned task %q is completed", task.Title)
+				}
+				titles = append(titles, task.Title)
+			}
+			sort.Strings(titles)
+			if fmt.Sprint(titles) != fmt.Sprint(tt.wantTasks) {
+				t.Errorf("clone tasks = %v, want %v", titles, tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestCloneSessionReinvite(t *testing.T) {
+	env := newTestEnv(t, 3)
+	source := env.finishedSession(t)
+
+	clone, invited, err := env.service.CloneSession(source.ID, testUserID(1), entity.CloneOptions{Reinvite: true})
+	if err != nil {
+		t.Fatalf("CloneSession: %v", err)
+	}
+
+	// Исключенный участник приглашение не получает
+	if fmt.Sprint(invited) != fmt.Sprint([]string{testUserID(2)}) {
+		t.Errorf("invited = %v, want [%s]", invited, testUserID(2))
+	}
+	if messages := env.telegram.messages(102); len(messages) != 1 {
+		t.Errorf("%s got %d invitations, want 1", testUserID(2), len(messages))
+	}
+	if messages := env.telegram.messages(103); len(messages) != 0 {
+		t.Errorf("banned %s got invitations %v", testUserID(3), messages)
+	}
+	if isPresent(env.session(t, clone.ID), testUserID(2)) {
+		t.Error("invited participant was added to the clone without joining")
+	}
+}
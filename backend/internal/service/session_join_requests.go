@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+// Payload кнопок заявки: "join_request:approve:<requestID>" или "join_request:deny:<requestID>"
+const joinRequestCallbackPrefix = "join_request:"
+
+// requestApproval создает заявку на вход (или возвращает уже поданную) и рассылает ее ведущим сессии
+func (s *SessionService) requestApproval(session *entity.Session, userID string) error {
+	request, err := s.joinRequestRepo.GetPending(session.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get join request: %w", err)
+	}
+
+	if request == nil {
+		request = &entity.SessionJoinRequest{
+			ID:        uuid.New().String(),
+			SessionID: session.ID,
+			UserID:    userID,
+			Status:    entity.JoinRequestStatusPending,
+			CreatedAt: time.Now(),
+		}
+		if err := s.joinRequestRepo.Create(request); err != nil {
+			return fmt.Errorf("failed to create join request: %w", err)
+		}
+		s.notifyHostsAboutJoinRequest(session, request)
+	}
+
+	return &entity.JoinRequestPendingError{SessionID: session.ID, RequestID: request.ID}
+}
+
+// notifyHostsAboutJoinRequest отправляет создателю и со-ведущим сообщение с кнопками "принять"/"отклонить"
+func (s *SessionService) notifyHostsAboutJoinRequest(session *entity.Session, request *entity.SessionJoinRequest) {
+	requesterName := "Пользователь"
+	if requester, err := s.userRepo.GetByID(request.UserID); err == nil && requester != nil {
+		requesterName = requester.Name
+	}
+
+	message := &telegramapi.SendMessageRequest{
+		Text: fmt.Sprintf("%s хочет присоединиться к сессии «%s».", requesterName, sessionTitle(session)),
+		Attachments: []interface{}{
+			telegramapi.InlineKeyboard([]telegramapi.CallbackButton{
+				{Text: "✅ Принять", Payload: joinRequestCallbackPrefix + "approve:" + request.ID},
+				{Text: "❌ Отклонить", Payload: joinRequestCallbackPrefix + "deny:" + request.ID},
+			}),
+		},
+	}
+
+	for _, hostID := range hostIDs(session) {
+		host, err := s.userRepo.GetByID(hostID)
+		if err != nil || host == nil || host.TelegramUserID == 0 {
+			log.Printf("[SessionService] ⚠️ Cannot notify host %s about join request %s: %v\n", hostID, request.ID, err)
+			continue
+		}
+		if _, err := s.telegramAPIService.SendMessageToUser(host.TelegramUserID, message); err != nil {
+			log.Printf("[SessionService] ❌ Failed to notify host %s about join request %s: %v\n", hostID, request.ID, err)
+		}
+	}
+}
+
+// ListJoinRequests возвращает нерассмотренные заявки сессии (только для ведущих)
+func (s *SessionService) ListJoinRequests(sessionID string, userID string) ([]*entity.SessionJoinRequest, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !isHost(session, userID) {
+		return nil, entity.Forbidden("only the creator or co-hosts can view join requests")
+	}
+
+	requests, err := s.joinRequestRepo.GetPendingBySessionID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join requests: %w", err)
+	}
+	return requests, nil
+}
+
+// ReviewJoinRequest одобряет или отклоняет заявку сессии sessionID. Одобренный заявитель сразу входит в сессию
+// (или встает в лист ожидания, если мест нет); о решении он узнает в Telegram
+func (s *SessionService) ReviewJoinRequest(sessionID string, requestID string, reviewerID string, approve bool) (*entity.JoinRequestDecision, error) {
+	request, err := s.joinRequestRepo.GetByID(requestID)
+	if err != nil || request == nil || request.SessionID != sessionID {
+		return nil, entity.ErrJoinRequestNotFound
+	}
+
+	return s.reviewJoinRequest(request, reviewerID, approve)
+}
+
+// reviewJoinRequest рассматривает загруженную заявку: проверяет права, фиксирует решение и применяет его
+func (s *SessionService) reviewJoinRequest(request *entity.SessionJoinRequest, reviewerID string, approve bool) (*entity.JoinRequestDecision, error) {
+	requestID := request.ID
+	session, err := s.loadSession(request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := applyTransition(session, entity.SessionActionReviewJoinRequest, resolveActor(session, reviewerID)); err != nil {
+		return nil, err
+	}
+	if !isHost(session, reviewerID) {
+		return nil, entity.Forbidden("only the creator or co-hosts can review join requests")
+	}
+
+	status := entity.JoinRequestStatusDenied
+	if approve {
+		// Новые участники входят только до старта — такую заявку уже нельзя одобрить
+		if _, err := applyTransition(session, entity.SessionActionJoin, actorOutsider); err != nil {
+			return nil, err
+		}
+		status = entity.JoinRequestStatusApproved
+	}
+
+	now := time.Now()
+	resolved, err := s.joinRequestRepo.Resolve(requestID, status, reviewerID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve join request: %w", err)
+	}
+	if !resolved {
+		return nil, &entity.DomainError{Kind: entity.ErrInvalidTransition, Reason: "join request has already been reviewed"}
+	}
+	request.Status = status
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+
+	decision := &entity.JoinRequestDecision{Request: request}
+	text := fmt.Sprintf("Заявка в сессию «%s» отклонена.", sessionTitle(session))
+
+	if approve {
+		var waitlisted *entity.WaitlistedError
+		err := s.admit(session.ID, request.UserID)
+		switch {
+		case errors.As(err, &waitlisted):
+			decision.Waitlist = waitlisted.Position
+			text = fmt.Sprintf("Заявка в сессию «%s» одобрена, но мест пока нет — вы №%d в листе ожидания.", sessionTitle(session), waitlisted.Position)
+		case err != nil:
+			// Заявитель не вошел — возвращаем заявку в ожидание, чтобы ее можно было рассмотреть снова
+			if _, reopenErr := s.joinRequestRepo.Reopen(requestID, status); reopenErr != nil {
+				log.Printf("[SessionService] ❌ Failed to reopen join request %s: %v\n", requestID, reopenErr)
+			}
+			return nil, err
+		default:
+			if decision.Session, err = s.GetSession(session.ID, request.UserID); err != nil {
+				return nil, err
+			}
+			text = fmt.Sprintf("Заявка одобрена — вы в сессии «%s»!", sessionTitle(session))
+		}
+	}
+
+	if requester, err := s.userRepo.GetByID(request.UserID); err == nil && requester != nil && requester.TelegramUserID != 0 {
+		if _, err := s.telegramAPIService.SendMessageToUser(requester.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+			log.Printf("[SessionService] ⚠️ Failed to notify requester %s: %v\n", request.UserID, err)
+		}
+	}
+
+	return decision, nil
+}
+
+// HandleJoinRequestCallback обрабатывает нажатие ведущим кнопки под заявкой в Telegram.
+// Возвращает nil, nil, если callback не относится к заявкам
+func (s *SessionService) HandleJoinRequestCallback(update interface{}) (*entity.JoinRequestDecision, error) {
+	callbackUpdate, ok := update.(*telegramapi.MessageCallbackUpdate)
+	if !ok {
+		return nil, fmt.Errorf("invalid update type: expected *telegramapi.MessageCallbackUpdate")
+	}
+
+	payload := callbackUpdate.Callback.Payload
+	if !strings.HasPrefix(payload, joinRequestCallbackPrefix) {
+		return nil, nil
+	}
+	action, requestID, found := strings.Cut(strings.TrimPrefix(payload, joinRequestCallbackPrefix), ":")
+	if !found || (action != "approve" && action != "deny") {
+		return nil, entity.InvalidArgument(fmt.Sprintf("invalid join request payload: %s", payload))
+	}
+
+	answer := "Заявка отклонена"
+	decision, err := s.reviewByTelegramUser(callbackUpdate.Callback.User.UserID, requestID, action == "approve")
+	switch {
+	case err != nil:
+		answer = "Не удалось обработать заявку: " + err.Error()
+	case decision.Request.Status == entity.JoinRequestStatusApproved:
+		answer = "Заявка одобрена"
+	}
+
+	if answerErr := s.telegramAPIService.AnswerCallback(callbackUpdate.Callback.CallbackID, answer); answerErr != nil {
+		log.Printf("[SessionService] ⚠️ Failed to answer callback %s: %v\n", callbackUpdate.Callback.CallbackID, answerErr)
+	}
+
+	return decision, err
+}
+
+func (s *SessionService) reviewByTelegramUser(telegramUserID int64, requestID string, approve bool) (*entity.JoinRequestDecision, error) {
+	reviewer, err := s.userRepo.GetByTelegramUserID(telegramUserID)
+	if err != nil || reviewer == nil {
+		return nil, entity.Forbidden("unknown reviewer")
+	}
+
+	request, err := s.joinRequestRepo.GetByID(requestID)
+	if err != nil || request == nil {
+		return nil, entity.ErrJoinRequestNotFound
+	}
+	return s.reviewJoinRequest(request, reviewer.ID, approve)
+}
+
+// SetCoHost назначает участника со-ведущим или снимает с него эту роль (только создатель)
+func (s *SessionService) SetCoHost(sessionID string, userID string, targetUserID string, isCoHost bool) error {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := applyTransition(session, entity.SessionActionManageCoHosts, resolveActor(session, userID)); err != nil {
+		return err
+	}
+	if targetUserID == session.CreatorID {
+		return entity.InvalidArgument("creator is already the host")
+	}
+	if !isPresent(session, targetUserID) {
+		return entity.InvalidArgument("co-host must be a participant of the session")
+	}
+
+	if err := s.sessionRepo.UpdateParticipantCoHost(sessionID, targetUserID, isCoHost); err != nil {
+		return fmt.Errorf("failed to update co-host: %w", err)
+	}
+	return nil
+}
+
+// isHost — создатель или присутствующий со-ведущий
+func isHost(session *entity.Session, userID string) bool {
+	if session.CreatorID == userID {
+		return true
+	}
+	participant := findParticipant(session, userID)
+	return participant != nil && participant.LeftAt == nil && participant.IsCoHost
+}
+
+func hostIDs(session *entity.Session) []string {
+	ids := []string{session.CreatorID}
+	for _, participant := range session.Participants {
+		if participant.UserID != session.CreatorID && participant.LeftAt == nil && participant.IsCoHost {
+			ids = append(ids, participant.UserID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+// requestToJoin подает заявку userID в сессию с одобрением и возвращает ее id
+func (e *testEnv) requestToJoin(t *testing.T, sessionID string, userID string) string {
+	t.Helper()
+
+	_, err := e.service.JoinSession(sessionID, userID)
+	var pending *entity.JoinRequestPendingError
+	if !errors.As(err, &pending) {
+		t.Fatalf("JoinSession(%s) error = %v, want pending join request", userID, err)
+	}
+	return pending.RequestID
+}
+
+func TestJoinRequestIsSentOnce(t *testing.T) {
+	env := newTestEnv(t, 2)
+	session := env.groupSession(t, entity.SessionSettings{IsPrivate: true, Approval: true})
+
+	requestID := env.requestToJoin(t, session.ID, testUserID(2))
+	if again := env.requestToJoin(t, session.ID, testUserID(2)); again != requestID {
+		t.Errorf("second join created request %s, want the pending %s", again, requestID)
+	}
+	if got := len(env.telegram.messages(101)); got != 1 {
+		t.Errorf("host got %d join request messages, want 1", got)
+	}
+	if isPresent(env.session(t, session.ID), testUserID(2)) {
+		t.Error("requester joined before approval")
+	}
+
+	requests, err := env.service.ListJoinRequests(session.ID, testUserID(1))
+	if err != nil {
+		t.Fatalf("ListJoinRequests: %v", err)
+	}
+	if len(requests) != 1 || requests[0].ID != requestID {
+		t.Errorf("ListJoinRequests = %d requests, want the pending %s", len(requests), requestID)
+	}
+	if _, err := env.service.ListJoinRequests(session.ID, testUserID(2)); !errors.Is(err, entity.ErrForbidden) {
+		t.Errorf("ListJoinRequests by requester error = %v, want forbidden", err)
+	}
+}
+
+func TestReviewJoinRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		reviewer    string
+		approve     bool
+		wantErr     error
+		wantStatus  entity.JoinRequestStatus
+		wantPresent bool
+		wantMessage string
+	}{
+		{name: "approve", reviewer: testUserID(1), approve: true, wantStatus: entity.JoinRequestStatusApproved, wantPresent: true, wantMessage: "одобрена"},
+		{name: "deny", reviewer: testUserID(1), wantStatus: entity.JoinRequestStatusDenied, wantMessage: "отклонена"},
+		{name: "co-host approves", reviewer: testUserID(3), approve: true, wantStatus: entity.JoinRequestStatusApproved, wantPresent: true, wantMessage: "одобрена"},
+		{name: "participant is not a host", reviewer: testUserID(4), approve: true, wantErr: entity.ErrForbidden, wantStatus: entity.JoinRequestStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 4)
+			session := env.groupSession(t, entity.SessionSettings{IsPrivate: true}, testUserID(3), testUserID(4))
+			if err := env.service.SetCoHost(session.ID, testUserID(1), testUserID(3), true); err != nil {
+				t.Fatalf("SetCoHost: %v", err)
+			}
+			// Одобрение включено после входа участников, которые рассматривают заявку
+			env.session(t, session.ID).RequireApproval = true
+			requestID := env.requestToJoin(t, session.ID, testUserID(2))
+
+			decision, err := env.service.ReviewJoinRequest(session.ID, requestID, tt.reviewer, tt.approve)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReviewJoinRequest error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ReviewJoinRequest: %v", err)
+			} else if decision.Request.Status != tt.wantStatus {
+				t.Errorf("decision status = %s, want %s", decision.Request.Status, tt.wantStatus)
+			}
+
+			request, err := env.joinRequests.GetByID(requestID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if request.Status != tt.wantStatus {
+				t.Errorf("stored request status = %s, want %s", request.Status, tt.wantStatus)
+			}
+			if present := isPresent(env.session(t, session.ID), testUserID(2)); present != tt.wantPresent {
+				t.Errorf("requester present = %v, want %v", present, tt.wantPresent)
+			}
+
+			messages := env.telegram.messages(102)
+			if tt.wantMessage == "" {
+				if len(messages) != 0 {
+					t.Errorf("requester got %v, want no messages", messages)
+				}
+				return
+			}
+			if len(messages) != 1 || !strings.Contains(messages[0], tt.wantMessage) {
+				t.Errorf("requester got %v, want one message with %q", messages, tt.wantMessage)
+			}
+
+			// Рассмотренную заявку нельзя рассмотреть повторно
+			if _, err := env.service.ReviewJoinRequest(session.ID, requestID, testUserID(1), !tt.approve); !errors.Is(err, entity.ErrInvalidTransition) {
+				t.Errorf("second ReviewJoinRequest error = %v, want invalid transition", err)
+			}
+		})
+	}
+}
+
+// failingJoinRepository не дает занять место в сессии
+type failingJoinRepository struct {
+	interfaces.SessionRepository
+}
+
+func (r failingJoinRepository) JoinWithinCapacity(sessionID string, participant *entity.Participant) (bool, error) {
+	return false, errors.New("connection lost")
+}
+
+func TestApprovedRequestReopensWhenJoinFails(t *testing.T) {
+	env := newTestEnv(t, 2)
+	session := env.groupSession(t, entity.SessionSettings{IsPrivate: true, Approval: true})
+	requestID := env.requestToJoin(t, session.ID, testUserID(2))
+
+	env.service.sessionRepo = failingJoinRepository{env.sessions}
+	if _, err := env.service.ReviewJoinRequest(session.ID, requestID, testUserID(1), true); err == nil {
+		t.Fatal("ReviewJoinRequest succeeded although the requester could not join")
+	}
+
+	request, err := env.joinRequests.GetByID(requestID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if request.Status != entity.JoinRequestStatusPending {
+		t.Fatalf("request status = %s, want pending again", request.Status)
+	}
+
+	// Заявку можно рассмотреть снова, когда вход заработает
+	env.service.sessionRepo = env.sessions
+	if _, err := env.service.ReviewJoinRequest(session.ID, requestID, testUserID(1), true); err != nil {
+		t.Fatalf("ReviewJoinRequest after reopen: %v", err)
+	}
+	if !isPresent(env.session(t, session.ID), testUserID(2)) {
+		t.Error("requester is not in the session after the second approval")
+	}
+}
+
+func TestJoinRequestCallback(t *testing.T) {
+	env := newTestEnv(t, 2)
+	session := env.groupSession(t, entity.SessionSettings{IsPrivate: true, Approval: true})
+	requestID := env.requestToJoin(t, session.ID, testUserID(2))
+
+	update := &telegramapi.MessageCallbackUpdate{}
+	update.Callback.Payload = joinRequestCallbackPrefix + "approve:" + requestID
+	update.Callback.User.UserID = 101
+
+	decision, err := env.service.HandleJoinRequestCallback(update)
+	if err != nil {
+		t.Fatalf("HandleJoinRequestCallback: %v", err)
+	}
+	if decision.Request.Status != entity.JoinRequestStatusApproved || !isPresent(env.session(t, session.ID), testUserID(2)) {
+		t.Errorf("decision status = %s, want the requester approved and in the session", decision.Request.Status)
+	}
+
+	// Чужие callback'и не относятся к заявкам
+	update.Callback.Payload = "create_chat:" + session.ID
+	if decision, err := env.service.HandleJoinRequestCallback(update); decision != nil || err != nil {
+		t.Errorf("HandleJoinRequestCallback(other payload) = %v, %v, want nil, nil", decision, err)
+	}
+}
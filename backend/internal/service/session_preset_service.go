@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+const (
+	maxPresetsPerUser = 20
+	maxPresetTasks    = 50
+)
+
+type SessionPresetService struct {
+	presetRepo interfaces.SessionPresetRepository
+}
+
+func NewSessionPresetService(presetRepo interfaces.SessionPresetRepository) interfaces.SessionPresetService {
+	return &SessionPresetService{
+		presetRepo: presetRepo,
+	}
+}
+
+func (s *SessionPresetService) CreatePreset(userID string, preset *entity.SessionPreset) (*entity.SessionPreset, error) {
+	presets, err := s.presetRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presets: %w", err)
+	}
+	if len(presets) >= maxPresetsPerUser {
+		return nil, entity.InvalidArgument(fmt.Sprintf("at most %d presets are allowed", maxPresetsPerUser))
+	}
+
+	preset.UserID = userID
+	if err := validatePreset(preset, presets); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	preset.ID = uuid.New().String()
+	preset.CreatedAt = now
+	preset.UpdatedAt = now
+
+	if err := s.presetRepo.Create(preset); err != nil {
+		return nil, fmt.Errorf("failed to create preset: %w", err)
+	}
+
+	return preset, nil
+}
+
+func (s *SessionPresetService) GetPresets(userID string) ([]*entity.SessionPreset, error) {
+	presets, err := s.presetRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presets: %w", err)
+	}
+	return presets, nil
+}
+
+func (s *SessionPresetService) GetPreset(presetID string, userID string) (*entity.SessionPreset, error) {
+	preset, err := s.presetRepo.GetByID(presetID)
+	// Чужой пресет для пользователя не существует
+	if err != nil || preset == nil || preset.UserID != userID {
+		return nil, entity.ErrPresetNotFound
+	}
+	return preset, nil
+}
+
+// UpdatePreset заменяет настройки пресета целиком: не переданные поля сбрасываются
+func (s *SessionPresetService) UpdatePreset(presetID string, userID string, preset *entity.SessionPreset) (*entity.SessionPreset, error) {
+	existing, err := s.GetPreset(presetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	presets, err := s.presetRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presets: %w", err)
+	}
+
+	preset.ID = existing.ID
+	preset.UserID = userID
+	preset.CreatedAt = existing.CreatedAt
+	preset.UpdatedAt = time.Now()
+	if err := validatePreset(preset, presets); err != nil {
+		return nil, err
+	}
+
+	if err := s.presetRepo.Update(preset); err != nil {
+		return nil, fmt.Errorf("failed to update preset: %w", err)
+	}
+
+	return preset, nil
+}
+
+func (s *SessionPresetService) DeletePreset(presetID string, userID string) error {
+	if _, err := s.GetPreset(presetID, userID); err != nil {
+		return err
+	}
+
+	if err := s.presetRepo.Delete(presetID); err != nil {
+		return fmt.Errorf("failed to delete preset: %w", err)
+	}
+	return nil
+}
+
+// validatePreset проверяет настройки пресета теми же правилами, что и создание сессии;
+// others — остальные пресеты пользователя для проверки уникальности имени
+func validatePreset(preset *entity.SessionPreset, others []*entity.SessionPreset) error {
+	preset.Name = strings.TrimSpace(preset.Name)
+	if preset.Name == "" || len([]rune(preset.Name)) > 100 {
+		return entity.InvalidArgument("name must be between 1 and 100 characters")
+	}
+	for _, other := range others {
+		if other.ID != preset.ID && other.Name == preset.Name {
+			return entity.InvalidArgument(fmt.Sprintf("preset %q already exists", preset.Name))
+		}
+	}
+
+	if preset.Mode != entity.SessionModeSolo && preset.Mode != entity.SessionModeGroup {
+		return entity.InvalidArgument("invalid mode: must be 'solo' or 'group'")
+	}
+	if preset.FocusDuration <= 0 {
+		return entity.InvalidArgument("focusDuration must be positive")
+	}
+	if preset.BreakDuration < 0 {
+		return entity.InvalidArgument("breakDuration must not be negative")
+	}
+
+	plan, err := normalizeCyclePlan(preset.Plan())
+	if err != nil {
+		return err
+	}
+	preset.Cycles = plan.Cycles
+	preset.LongBreak = plan.LongBreak
+	preset.LongBreakEvery = plan.LongBreakEvery
+	preset.PhasePlan = plan.Phases
+
+	if len(preset.Tasks) > maxPresetTasks {
+		return entity.InvalidArgument(fmt.Sprintf("at most %d tasks are allowed", maxPresetTasks))
+	}
+	for _, title := range preset.Tasks {
+		if strings.TrimSpace(title) == "" {
+			return entity.InvalidArgument("task title must not be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+)
+
+func TestCreatePresetValidation(t *testing.T) {
+	valid := func() *entity.SessionPreset {
+		return &entity.SessionPreset{Name: "Утро", Mode: entity.SessionModeGroup, FocusDuration: 50, BreakDuration: 10}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *entity.SessionPreset)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *entity.SessionPreset) {}},
+		{name: "name is trimmed", modify: func(p *entity.SessionPreset) { p.Name = "  Вечер  " }},
+		{name: "empty name", modify: func(p *entity.SessionPreset) { p.Name = "   " }, wantErr: true},
+		{name: "duplicate name", modify: func(p *entity.SessionPreset) { p.Name = "Занято" }, wantErr: true},
+		{name: "unknown mode", modify: func(p *entity.SessionPreset) { p.Mode = "pair" }, wantErr: true},
+		{name: "no focus", modify: func(p *entity.SessionPreset) { p.FocusDuration = 0 }, wantErr: true},
+		{name: "empty task", modify: func(p *entity.SessionPreset) { p.Tasks = entity.TaskTitles{"write", " "} }, wantErr: true},
+		{name: "long break without period", modify: func(p *entity.SessionPreset) { p.Cycles = 4; p.LongBreak = 30 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presets := NewSessionPresetService(memory.NewSessionPresetRepository())
+			if _, err := presets.CreatePreset(testUserID(1), &entity.SessionPreset{Name: "Занято", Mode: entity.SessionModeSolo, FocusDuration: 25}); err != nil {
+				t.Fatalf("CreatePreset: %v", err)
+			}
+			// Имя занято только у своего пользователя
+			if _, err := presets.CreatePreset(testUserID(2), &entity.SessionPreset{Name: "Утро", Mode: entity.SessionModeSolo, FocusDuration: 25}); err != nil {
+				t.Fatalf("CreatePreset of another user: %v", err)
+			}
+
+			preset := valid()
+			tt.modify(preset)
+			created, err := presets.CreatePreset(testUserID(1), preset)
+			if tt.wantErr {
+				if !errors.Is(err, entity.ErrInvalidArgument) {
+					t.Fatalf("CreatePreset error = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreatePreset: %v", err)
+			}
+			if created.ID == "" || created.UserID != testUserID(1) || created.Name != strings.TrimSpace(created.Name) {
+				t.Errorf("created preset = %+v", created)
+			}
+			if created.LongBreak > 0 && created.LongBreakEvery == 0 {
+				t.Error("long break period was not defaulted")
+			}
+		})
+	}
+}
+
+func TestPresetBelongsToUser(t *testing.T) {
+	presets := NewSessionPresetService(memory.NewSessionPresetRepository())
+	preset, err := presets.CreatePreset(testUserID(1), &entity.SessionPreset{Name: "Утро", Mode: entity.SessionModeSolo, FocusDuration: 25, BreakDuration: 5})
+	if err != nil {
+		t.Fatalf("CreatePreset: %v", err)
+	}
+
+	if _, err := presets.GetPreset(preset.ID, testUserID(2)); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("GetPreset by another user error = %v, want not found", err)
+	}
+	if _, err := presets.UpdatePreset(preset.ID, testUserID(2), &entity.SessionPreset{Name: "Чужой", Mode: entity.SessionModeSolo, FocusDuration: 25}); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("UpdatePreset by another user error = %v, want not found", err)
+	}
+	if err := presets.DeletePreset(preset.ID, testUserID(2)); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("DeletePreset by another user error = %v, want not found", err)
+	}
+
+	// Обновление заменяет настройки целиком
+	updated, err := presets.UpdatePreset(preset.ID, testUserID(1), &entity.SessionPreset{Name: "Утро", Mode: entity.SessionModeGroup, FocusDuration: 50})
+	if err != nil {
+		t.Fatalf("UpdatePreset: %v", err)
+	}
+	if updated.BreakDuration != 0 || updated.Mode != entity.SessionModeGroup || !updated.CreatedAt.Equal(preset.CreatedAt) {
+		t.Errorf("updated preset = %+v, want group preset without break and the original creation time", updated)
+	}
+
+	if err := presets.DeletePreset(preset.ID, testUserID(1)); err != nil {
+		t.Fatalf("DeletePreset: %v", err)
+	}
+	if _, err := presets.GetPreset(preset.ID, testUserID(1)); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("GetPreset after delete error = %v, want not found", err)
+	}
+}
+
+func TestSessionFromPreset(t *testing.T) {
+	env := newTestEnv(t, 1)
+	presets := NewSessionPresetService(memory.NewSessionPresetRepository())
+	groupName := "Чтение"
+	preset, err := presets.CreatePreset(testUserID(1), &entity.SessionPreset{
+		Name:          "Чтение",
+		Mode:          entity.SessionModeGroup,
+		FocusDuration: 45,
+		BreakDuration: 15,
+		Cycles:        3,
+		GroupName:     &groupName,
+		Tasks:         entity.TaskTitles{"глава 1", "глава 2"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePreset: %v", err)
+	}
+
+	settings := preset.Settings()
+	settings.Tasks[0] = "пролог"
+	if preset.Tasks[0] != "глава 1" {
+		t.Fatal("changing the settings changed the preset tasks")
+	}
+
+	session, err := env.service.CreateSession(testUserID(1), settings)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if session.FocusDuration != 45 || session.BreakDuration != 15 || session.Cycles != 3 || session.GroupName == nil || *session.GroupName != groupName {
+		t.Errorf("session = focus %d, break %d, cycles %d, group %v, want the preset settings", session.FocusDuration, session.BreakDuration, session.Cycles, session.GroupName)
+	}
+	tasks, err := env.tasks.GetBySessionID(session.ID)
+	if err != nil {
+		t.Fatalf("GetBySessionID: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("session has %d tasks, want 2", len(tasks))
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"github.com/rnegic/synchronous/pkg/telegramapi"
+)
+
+// SessionSchedulerService starts scheduled sessions at their scheduledAt time
+// and reminds joined participants about them in Telegram
+type SessionSchedulerService struct {
+	sessionRepo        interfaces.SessionRepository
+	userRepo           interfaces.UserRepository
+	sessionService     interfaces.SessionService
+	telegramAPIService interfaces.TelegramAPIService
+	notifier           interfaces.SessionNotifier
+	interval           time.Duration
+	reminderLead       time.Duration // how long before the start the reminder is sent
+}
+
+// NewSessionSchedulerService creates a new scheduler
+func NewSessionSchedulerService(
+	sessionRepo interfaces.SessionRepository,
+	userRepo interfaces.UserRepository,
+	sessionService interfaces.SessionService,
+	telegramAPIService interfaces.TelegramAPIService,
+	notifier interfaces.SessionNotifier,
+	interval time.Duration,
+	reminderLead time.Duration,
+) *SessionSchedulerService {
+	return &SessionSchedulerService{
+		sessionRepo:        sessionRepo,
+		userRepo:           userRepo,
+		sessionService:     sessionService,
+		telegramAPIService: telegramAPIService,
+		notifier:           notifier,
+		interval:           interval,
+		reminderLead:       reminderLead,
+	}
+}
+
+// Start begins the scheduler routine
+func (s *SessionSchedulerService) Start() {
+	log.Printf("[SessionScheduler] 📅 Starting scheduler (interval: %v, reminder: %v before start)\n", s.interval, s.reminderLead)
+
+	ticker := time.NewTicker(s.interval)
+	go func() {
+		for range ticker.C {
+			s.tick()
+		}
+	}()
+}
+
+// tick starts sessions whose time has come and sends reminders for upcoming ones
+func (s *SessionSchedulerService) tick() {
+	now := time.Now()
+
+	sessions, err := s.sessionRepo.GetScheduledSessionsBefore(now.Add(s.reminderLead))
+	if err != nil {
+		log.Printf("[SessionScheduler] ❌ Failed to get scheduled sessions: %v\n", err)
+		return
+	}
+
+	for _, session := range sessions {
+		if !now.Before(*session.ScheduledAt) {
+			s.start(session, now)
+			continue
+		}
+		if session.ReminderSentAt == nil {
+			s.remind(session, now)
+		}
+	}
+}
+
+func (s *SessionSchedulerService) start(session *entity.Session, now time.Time) {
+	started, err := s.sessionService.AutoStartSession(session.ID)
+	if err != nil {
+		// The creator or another instance started the session first
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return
+		}
+		log.Printf("[SessionScheduler] ❌ Failed to start scheduled session %s: %v\n", session.ID, err)
+		return
+	}
+
+	log.Printf("[SessionScheduler] ▶️ Started scheduled session %s\n", started.ID)
+
+	if s.notifier != nil {
+		s.notifier.SendToSession(started.ID, "session_started", map[string]interface{}{
+			"sessionId": started.ID,
+			"phase":     phaseEventData(started, now),
+			"trigger":   "schedule",
+		})
+	}
+
+	s.notifyParticipants(started, fmt.Sprintf("Сессия «%s» началась — пора фокусироваться!", sessionTitle(started)))
+}
+
+func (s *SessionSchedulerService) remind(session *entity.Session, now time.Time) {
+	// Only one instance gets to send the reminder
+	marked, err := s.sessionRepo.MarkReminderSent(session.ID, now)
+	if err != nil {
+		log.Printf("[SessionScheduler] ❌ Failed to mark reminder for session %s: %v\n", session.ID, err)
+		return
+	}
+	if !marked {
+		return
+	}
+
+	minutes := int(math.Ceil(session.ScheduledAt.Sub(now).Minutes()))
+	s.notifyParticipants(session, fmt.Sprintf("Сессия «%s» начнется через %d мин.", sessionTitle(session), minutes))
+}
+
+// notifyParticipants sends a Telegram DM to every participant still in the session
+func (s *SessionSchedulerService) notifyParticipants(session *entity.Session, text string) {
+	for _, participant := range session.Participants {
+		if participant.LeftAt != nil {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(participant.UserID)
+		if err != nil || user == nil || user.TelegramUserID == 0 {
+			log.Printf("[SessionScheduler] ⚠️ Cannot notify user %s about session %s: %v\n", participant.UserID, session.ID, err)
+			continue
+		}
+
+		if _, err := s.telegramAPIService.SendMessageToUser(user.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+			log.Printf("[SessionScheduler] ❌ Failed to notify user %s about session %s: %v\n", participant.UserID, session.ID, err)
+		}
+	}
+}
+
+// sessionTitle возвращает название сессии для уведомлений
+func sessionTitle(session *entity.Session) string {
+	if session.GroupName != nil && *session.GroupName != "" {
+		return *session.GroupName
+	}
+	return "Фокус-сессия"
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// SessionSeriesGeneratorService periodically creates upcoming sessions of recurring series
+type SessionSeriesGeneratorService struct {
+	seriesService interfaces.SessionSeriesService
+	interval      time.Duration
+}
+
+// NewSessionSeriesGeneratorService creates a new series generator
+func NewSessionSeriesGeneratorService(seriesService interfaces.SessionSeriesService, interval time.Duration) *SessionSeriesGeneratorService {
+	return &SessionSeriesGeneratorService{
+		seriesService: seriesService,
+		interval:      interval,
+	}
+}
+
+// Start generates occurrences right away and then on every tick
+func (s *SessionSeriesGeneratorService) Start() {
+	log.Printf("[SessionSeries] 🔁 Starting series generator (interval: %v)\n", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	go func() {
+		s.generate()
+		for range ticker.C {
+			s.generate()
+		}
+	}()
+}
+
+func (s *SessionSeriesGeneratorService) generate() {
+	if err := s.seriesService.GenerateOccurrences(); err != nil {
+		log.Printf("[SessionSeries] ❌ Failed to generate occurrences: %v\n", err)
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+	"github.com/rnegic/synchronous/pkg/recurrence"
+)
+
+type SessionSeriesService struct {
+	seriesRepo     interfaces.SessionSeriesRepository
+	sessionRepo    interfaces.SessionRepository
+	sessionService interfaces.SessionService
+	horizon        time.Duration // на сколько вперед создаются экземпляры серии
+}
+
+func NewSessionSeriesService(
+	seriesRepo interfaces.SessionSeriesRepository,
+	sessionRepo interfaces.SessionRepository,
+	sessionService interfaces.SessionService,
+	horizon time.Duration,
+) interfaces.SessionSeriesService {
+	return &SessionSeriesService{
+		seriesRepo:     seriesRepo,
+		sessionRepo:    sessionRepo,
+		sessionService: sessionService,
+		horizon:        horizon,
+	}
+}
+
+func (s *SessionSeriesService) CreateSeries(userID string, series *entity.SessionSeries) (*entity.SessionSeries, error) {
+	if _, err := time.LoadLocation(series.Timezone); err != nil || series.Timezone == "" {
+		return nil, entity.InvalidArgument(fmt.Sprintf("invalid timezone %q", series.Timezone))
+	}
+	if _, err := recurrence.Parse(series.RRule); err != nil {
+		return nil, entity.InvalidArgument(fmt.Sprintf("invalid rrule: %v", err))
+	}
+	if series.FocusDuration <= 0 {
+		return nil, entity.InvalidArgument("focusDuration must be positive")
+	}
+	if series.BreakDuration < 0 {
+		return nil, entity.InvalidArgument("breakDuration must not be negative")
+	}
+
+	now := time.Now()
+	series.ID = uuid.New().String()
+	series.CreatorID = userID
+	series.InviteLink = uuid.New().String()[:8] // Короткая ссылка, как у сессий
+	series.GeneratedUntil = nil
+	series.CreatedAt = now
+	series.UpdatedAt = now
+
+	if err := s.seriesRepo.Create(series); err != nil {
+		return nil, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	// Первые экземпляры создаем сразу, чтобы по ссылке серии было куда присоединиться
+	if _, err := s.generate(series, now); err != nil {
+		log.Printf("[SessionSeries] ❌ Failed to generate occurrences for series %s: %v\n", series.ID, err)
+	}
+
+	return series, nil
+}
+
+func (s *SessionSeriesService) GetSeries(seriesID string, userID string) (*entity.SessionSeries, []*entity.Session, error) {
+	series, err := s.loadSeries(seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if series.IsPrivate && series.CreatorID != userID {
+		subscribed, err := s.isSubscribed(seriesID, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !subscribed {
+			return nil, nil, entity.Forbidden("access denied")
+		}
+	}
+
+	upcoming, err := s.sessionRepo.GetUpcomingBySeriesID(seriesID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+
+	return series, upcoming, nil
+}
+
+// DeleteSeries останавливает серию и отменяет ее еще не начавшиеся экземпляры
+func (s *SessionSeriesService) DeleteSeries(seriesID string, userID string) ([]string, error) {
+	series, err := s.loadSeries(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.CreatorID != userID {
+		return nil, entity.Forbidden("only creator can delete series")
+	}
+
+	if err := s.seriesRepo.Delete(seriesID); err != nil {
+		return nil, fmt.Errorf("failed to delete series: %w", err)
+	}
+
+	upcoming, err := s.sessionRepo.GetUpcomingBySeriesID(seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+
+	cancelled := make([]string, 0, len(upcoming))
+	for _, session := range upcoming {
+		if err := s.sessionService.CancelSession(session.ID, session.CreatorID); err != nil {
+			log.Printf("[SessionSeries] ❌ Failed to cancel session %s of series %s: %v\n", session.ID, seriesID, err)
+			continue
+		}
+		cancelled = append(cancelled, session.ID)
+	}
+
+	return cancelled, nil
+}
+
+func (s *SessionSeriesService) Subscribe(seriesID string, userID string) error {
+	series, err := s.loadSeries(seriesID)
+	if err != nil {
+		return err
+	}
+	// В приватную серию подписываются только по ссылке-приглашению
+	if series.IsPrivate && series.CreatorID != userID {
+		return entity.Forbidden("private series can only be joined by invite link")
+	}
+
+	return s.subscribe(series, userID)
+}
+
+func (s *SessionSeriesService) Unsubscribe(seriesID string, userID string) error {
+	series, err := s.loadSeries(seriesID)
+	if err != nil {
+		return err
+	}
+	if series.CreatorID == userID {
+		return entity.InvalidArgument("creator cannot unsubscribe from own series")
+	}
+
+	if err := s.seriesRepo.RemoveSubscriber(seriesID, userID); err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+
+	// Из уже созданных, но не начавшихся экземпляров подписчик тоже выходит
+	upcoming, err := s.sessionRepo.GetUpcomingBySeriesID(seriesID)
+	if err != nil {
+		return fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+	for _, session := range upcoming {
+		if !isPresent(session, userID) {
+			continue
+		}
+		if _, _, err := s.sessionService.LeaveSession(session.ID, userID); err != nil {
+			log.Printf("[SessionSeries] ❌ Failed to remove %s from session %s: %v\n", userID, session.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// JoinByInviteLink присоединяет пользователя к ближайшему экземпляру серии; ссылка серии не меняется
+func (s *SessionSeriesService) JoinByInviteLink(inviteLink string, userID string, subscribe bool) (*entity.Session, error) {
+	series, err := s.seriesRepo.GetByInviteLink(inviteLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series by invite link: %w", err)
+	}
+	if series == nil {
+		return nil, fmt.Errorf("%w by invite link", entity.ErrSeriesNotFound)
+	}
+
+	if subscribe {
+		if err := s.subscribe(series, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	upcoming, err := s.sessionRepo.GetUpcomingBySeriesID(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+	if len(upcoming) == 0 {
+		return nil, fmt.Errorf("upcoming %w", entity.ErrSessionNotFound)
+	}
+
+	return s.sessionService.JoinSession(upcoming[0].ID, userID)
+}
+
+// GenerateOccurrences создает экземпляры всех серий на горизонт вперед
+func (s *SessionSeriesService) GenerateOccurrences() error {
+	seriesList, err := s.seriesRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get series: %w", err)
+	}
+
+	now := time.Now()
+	for _, series := range seriesList {
+		created, err := s.generate(series, now)
+		if err != nil {
+			log.Printf("[SessionSeries] ❌ Failed to generate occurrences for series %s: %v\n", series.ID, err)
+			continue
+		}
+		if created > 0 {
+			log.Printf("[SessionSeries] 📅 Series %s: created %d sessions\n", series.ID, created)
+		}
+	}
+
+	return nil
+}
+
+// generate создает экземпляры серии между уже сгенерированным горизонтом и now+horizon.
+// Окно сначала захватывается через compare-and-set, поэтому с несколькими инстансами экземпляры не дублируются
+func (s *SessionSeriesService) generate(series *entity.SessionSeries, now time.Time) (int, error) {
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timezone: %w", err)
+	}
+	rule, err := recurrence.Parse(series.RRule)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rrule: %w", err)
+	}
+
+	after := now
+	if series.GeneratedUntil != nil && series.GeneratedUntil.After(after) {
+		after = *series.GeneratedUntil
+	}
+	until := now.Add(s.horizon)
+	if !until.After(after) {
+		return 0, nil
+	}
+
+	claimed, err := s.seriesRepo.AdvanceGeneratedUntil(series.ID, series.GeneratedUntil, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance series horizon: %w", err)
+	}
+	if !claimed {
+		return 0, nil
+	}
+	series.GeneratedUntil = &until
+
+	subscribers, err := s.seriesRepo.GetSubscribers(series.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+
+	seriesID := series.ID
+	created := 0
+	for _, occurrence := range rule.Between(series.StartsAt.In(loc), after, until) {
+		scheduledAt := occurrence
+		session, err := s.sessionService.CreateSession(series.CreatorID, entity.SessionSettings{
+			Mode:          entity.SessionModeGroup,
+			FocusDuration: series.FocusDuration,
+			BreakDuration: series.BreakDuration,
+			GroupName:     series.GroupName,
+			IsPrivate:     series.IsPrivate,
+			ScheduledAt:   &scheduledAt,
+			SeriesID:      &seriesID,
+		})
+		if err != nil {
+			log.Printf("[SessionSeries] ❌ Failed to create session of series %s at %v: %v\n", series.ID, occurrence, err)
+			continue
+		}
+		created++
+
+		for _, subscriber := range subscribers {
+			if subscriber.UserID == series.CreatorID {
+				continue
+			}
+			if _, err := s.sessionService.JoinSession(session.ID, subscriber.UserID); err != nil {
+				log.Printf("[SessionSeries] ❌ Failed to add subscriber %s to session %s: %v\n", subscriber.UserID, session.ID, err)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// subscribe подписывает пользователя и сразу добавляет его в уже созданные экземпляры
+func (s *SessionSeriesService) subscribe(series *entity.SessionSeries, userID string) error {
+	if series.CreatorID == userID {
+		return nil
+	}
+
+	if err := s.seriesRepo.AddSubscriber(&entity.SessionSeriesSubscriber{
+		SeriesID:     series.ID,
+		UserID:       userID,
+		SubscribedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	upcoming, err := s.sessionRepo.GetUpcomingBySeriesID(series.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+	for _, session := range upcoming {
+		if _, err := s.sessionService.JoinSession(session.ID, userID); err != nil {
+			log.Printf("[SessionSeries] ❌ Failed to add subscriber %s to session %s: %v\n", userID, session.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SessionSeriesService) loadSeries(seriesID string) (*entity.SessionSeries, error) {
+	series, err := s.seriesRepo.GetByID(seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+	if series == nil {
+		return nil, entity.ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+func (s *SessionSeriesService) isSubscribed(seriesID string, userID string) (bool, error) {
+	subscribers, err := s.seriesRepo.GetSubscribers(seriesID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	for _, subscriber := range subscribers {
+		if subscriber.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
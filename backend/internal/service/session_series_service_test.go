@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+)
+
+func newTestSeriesService(env *testEnv, horizon time.Duration) *SessionSeriesService {
+	return NewSessionSeriesService(memory.NewSessionSeriesRepository(), env.sessions, env.service, horizon).(*SessionSeriesService)
+}
+
+func TestSeriesOccurrenceByInviteLink(t *testing.T) {
+	env := newTestEnv(t, 3)
+	series := newTestSeriesService(env, 2*time.Hour)
+
+	created, err := series.CreateSeries(testUserID(1), &entity.SessionSeries{
+		FocusDuration: 25,
+		BreakDuration: 5,
+		RRule:         "FREQ=DAILY",
+		Timezone:      "UTC",
+		StartsAt:      time.Now().Add(time.Hour).Truncate(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("CreateSeries: %v", err)
+	}
+
+	_, upcoming, err := series.GetSeries(created.ID, testUserID(1))
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	if len(upcoming) != 1 {
+		t.Fatalf("upcoming sessions = %d, want 1", len(upcoming))
+	}
+	occurrence := upcoming[0]
+	if occurrence.SeriesID == nil || *occurrence.SeriesID != created.ID {
+		t.Fatalf("occurrence SeriesID = %v, want %s", occurrence.SeriesID, created.ID)
+	}
+
+	joined, err := series.JoinByInviteLink(created.InviteLink, testUserID(2), true)
+	if err != nil {
+		t.Fatalf("JoinByInviteLink: %v", err)
+	}
+	if joined.ID != occurrence.ID || !isPresent(env.session(t, occurrence.ID), testUserID(2)) {
+		t.Errorf("JoinByInviteLink joined session %s, want %s with %s present", joined.ID, occurrence.ID, testUserID(2))
+	}
+
+	// Подписка по публичной серии сразу добавляет в созданные экземпляры
+	if err := series.Subscribe(created.ID, testUserID(3)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if !isPresent(env.session(t, occurrence.ID), testUserID(3)) {
+		t.Errorf("subscriber %s is not in the upcoming occurrence", testUserID(3))
+	}
+
+	if err := series.Unsubscribe(created.ID, testUserID(3)); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if isPresent(env.session(t, occurrence.ID), testUserID(3)) {
+		t.Errorf("unsubscribed %s is still in the upcoming occurrence", testUserID(3))
+	}
+
+	cancelled, err := series.DeleteSeries(created.ID, testUserID(1))
+	if err != nil {
+		t.Fatalf("DeleteSeries: %v", err)
+	}
+	if len(cancelled) != 1 || cancelled[0] != occurrence.ID {
+		t.Errorf("DeleteSeries cancelled %v, want [%s]", cancelled, occurrence.ID)
+	}
+	if status := env.session(t, occurrence.ID).Status; status != entity.SessionStatusCancelled {
+		t.Errorf("occurrence status = %s, want %s", status, entity.SessionStatusCancelled)
+	}
+}
+
+func TestSeriesGenerateOnce(t *testing.T) {
+	env := newTestEnv(t, 1)
+	series := newTestSeriesService(env, 50*time.Hour)
+
+	created, err := series.CreateSeries(testUserID(1), &entity.SessionSeries{
+		FocusDuration: 25,
+		BreakDuration: 5,
+		RRule:         "FREQ=DAILY",
+		Timezone:      "Europe/Moscow",
+		StartsAt:      time.Now().Add(time.Hour).Truncate(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("CreateSeries: %v", err)
+	}
+
+	// Повторная генерация с тем же горизонтом не создает дублей
+	if err := series.GenerateOccurrences(); err != nil {
+		t.Fatalf("GenerateOccurrences: %v", err)
+	}
+
+	upcoming, err := env.sessions.GetUpcomingBySeriesID(created.ID)
+	if err != nil {
+		t.Fatalf("GetUpcomingBySeriesID: %v", err)
+	}
+	if len(upcoming) != 3 {
+		t.Fatalf("upcoming sessions = %d, want 3", len(upcoming))
+	}
+	for i := 1; i < len(upcoming); i++ {
+		if got := upcoming[i].ScheduledAt.Sub(*upcoming[i-1].ScheduledAt); got != 24*time.Hour {
+			t.Errorf("occurrences %d and %d are %v apart, want 24h", i-1, i, got)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -15,32 +17,74 @@ import (
 type SessionService struct {
 	sessionRepo        interfaces.SessionRepository
 	taskRepo           interfaces.TaskRepository
+	phaseSegmentRepo   interfaces.PhaseSegmentRepository
+	attendanceRepo     interfaces.AttendanceRepository
+	waitlistRepo       interfaces.SessionWaitlistRepository
+	joinRequestRepo    interfaces.SessionJoinRequestRepository
 	userRepo           interfaces.UserRepository
+	contactRepo        interfaces.ContactRepository
+	achievementRepo    interfaces.AchievementRepository
 	telegramAPIService interfaces.TelegramAPIService
+	maxSessionSize     int // лимит участников по умолчанию и верхняя граница для настройки сессии
 }
 
 func NewSessionService(
 	sessionRepo interfaces.SessionRepository,
 	taskRepo interfaces.TaskRepository,
+	phaseSegmentRepo interfaces.PhaseSegmentRepository,
+	attendanceRepo interfaces.AttendanceRepository,
+	waitlistRepo interfaces.SessionWaitlistRepository,
+	joinRequestRepo interfaces.SessionJoinRequestRepository,
 	userRepo interfaces.UserRepository,
+	contactRepo interfaces.ContactRepository,
+	achievementRepo interfaces.AchievementRepository,
 	telegramAPIService interfaces.TelegramAPIService,
+	maxSessionSize int,
 ) interfaces.SessionService {
 	return &SessionService{
 		sessionRepo:        sessionRepo,
 		taskRepo:           taskRepo,
+		phaseSegmentRepo:   phaseSegmentRepo,
+		attendanceRepo:     attendanceRepo,
+		waitlistRepo:       waitlistRepo,
+		joinRequestRepo:    joinRequestRepo,
 		userRepo:           userRepo,
+		contactRepo:        contactRepo,
+		achievementRepo:    achievementRepo,
 		telegramAPIService: telegramAPIService,
+		maxSessionSize:     maxSessionSize,
 	}
 }
 
-func (s *SessionService) CreateSession(
-	userID string,
-	mode entity.SessionMode,
-	tasks []string,
-	focusDuration, breakDuration int,
-	groupName *string,
-	isPrivate bool,
-) (*entity.Session, error) {
+func (s *SessionService) CreateSession(userID string, settings entity.SessionSettings) (*entity.Session, error) {
+	// Запланированная сессия стартует автоматически (SessionSchedulerService), время должно быть в будущем
+	if settings.ScheduledAt != nil && !settings.ScheduledAt.After(time.Now()) {
+		return nil, entity.InvalidArgument("scheduledAt must be in the future")
+	}
+	startPolicy, err := normalizeStartPolicy(settings.Mode, settings.StartPolicy, settings.StartQuorum, settings.StartCountdown)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := normalizeCyclePlan(settings.Plan)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Approval && (settings.Mode != entity.SessionModeGroup || !settings.IsPrivate) {
+		return nil, entity.InvalidArgument("requireApproval is only available for private group sessions")
+	}
+
+	capacity := settings.Capacity
+	if capacity == 0 {
+		capacity = s.maxSessionSize
+	} else if capacity < 2 || (s.maxSessionSize > 0 && capacity > s.maxSessionSize) {
+		if s.maxSessionSize <= 0 {
+			return nil, entity.InvalidArgument("maxParticipants must be at least 2")
+		}
+		return nil, entity.InvalidArgument(fmt.Sprintf("maxParticipants must be between 2 and %d", s.maxSessionSize))
+	}
+
 	sessionID := uuid.New().String()
 	inviteLink := uuid.New().String()[:8] // Короткая ссылка
 	// Получаем реальные данные пользователя для корректного отображения имени и аватара
@@ -61,28 +105,43 @@ func (s *SessionService) CreateSession(
 
 	// Сначала создаем сессию, чтобы она существовала в БД для внешних ключей
 	session := &entity.Session{
-		ID:            sessionID,
-		Mode:          mode,
-		Status:        entity.SessionStatusPending,
-		FocusDuration: focusDuration,
-		BreakDuration: breakDuration,
-		GroupName:     groupName,
-		IsPrivate:     isPrivate,
-		CreatorID:     userID,
-		Participants:  participants,
-		InviteLink:    inviteLink,
-		CreatedAt:     time.Now(),
-		CurrentCycle:  0,
+		ID:              sessionID,
+		Mode:            settings.Mode,
+		Status:          entity.SessionStatusPending,
+		FocusDuration:   settings.FocusDuration,
+		BreakDuration:   settings.BreakDuration,
+		Cycles:          plan.Cycles,
+		LongBreak:       plan.LongBreak,
+		LongBreakEvery:  plan.LongBreakEvery,
+		PhasePlan:       plan.Phases,
+		GroupName:       settings.GroupName,
+		IsPrivate:       settings.IsPrivate,
+		RequireApproval: settings.Approval,
+		MaxParticipants: capacity,
+		ScheduledAt:     settings.ScheduledAt,
+		SeriesID:        settings.SeriesID,
+		StartPolicy:     startPolicy,
+		StartQuorum:     settings.StartQuorum,
+		StartCountdown:  settings.StartCountdown,
+		CreatorID:       userID,
+		Participants:    participants,
+		InviteLink:      inviteLink,
+		CreatedAt:       time.Now(),
+		CurrentCycle:    0,
 	}
 
 	if err := s.sessionRepo.Create(session); err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if err := s.attendanceRepo.Create(newAttendance(sessionID, userID, session.CreatedAt)); err != nil {
+		return nil, fmt.Errorf("failed to record attendance: %w", err)
+	}
+
 	// Теперь создаем задачи после создания сессии
 	// Привязываем задачи к пользователю (creator) для индивидуального отслеживания
-	tasksList := make([]entity.Task, 0, len(tasks))
-	for _, title := range tasks {
+	tasksList := make([]entity.Task, 0, len(settings.Tasks))
+	for _, title := range settings.Tasks {
 		task := entity.Task{
 			ID:        uuid.New().String(),
 			Title:     title,
@@ -106,9 +165,9 @@ func (s *SessionService) CreateSession(
 }
 
 func (s *SessionService) GetSession(sessionID string, userID string) (*entity.Session, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
 	}
 
 	// Проверяем доступ
@@ -129,7 +188,7 @@ func (s *SessionService) GetSession(sessionID string, userID string) (*entity.Se
 	}
 
 	if !hasAccess {
-		return nil, fmt.Errorf("access denied")
+		return nil, entity.Forbidden("access denied")
 	}
 
 	// Загружаем только задачи текущего пользователя (индивидуальные задачи)
@@ -153,7 +212,7 @@ func (s *SessionService) GetActiveSession(userID string) (*entity.Session, error
 		return nil, fmt.Errorf("failed to get active session: %w", err)
 	}
 	if session == nil {
-		return nil, fmt.Errorf("active session not found")
+		return nil, fmt.Errorf("active %w", entity.ErrSessionNotFound)
 	}
 
 	// Load only tasks that belong to the current user to avoid leaking private notes
@@ -208,34 +267,491 @@ func (s *SessionService) GetPublicSessions(page, limit int) ([]*entity.Session,
 }
 
 func (s *SessionService) JoinSession(sessionID string, userID string) (*entity.Session, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
+	}
+
+	if participant := findParticipant(session, userID); participant != nil {
+		if participant.BannedAt != nil {
+			return nil, entity.Forbidden("user is banned from this session")
+		}
+		if participant.LeftAt == nil {
+			// Уже участник, просто возвращаем сессию
+			return s.GetSession(sessionID, userID)
+		}
+		return s.rejoinSession(session, userID)
 	}
 
-	if session.Status != entity.SessionStatusPending {
-		return nil, fmt.Errorf("session already started")
+	if _, err := applyTransition(session, entity.SessionActionJoin, actorOutsider); err != nil {
+		return nil, err
 	}
 
-	// Подтягиваем реальные имя и аватар участника
-	user, uerr := s.userRepo.GetByID(userID)
-	if uerr != nil || user == nil {
-		return nil, fmt.Errorf("failed to load user: %w", uerr)
+	// В сессию с одобрением новые участники попадают только через заявку; вернуться можно без нее
+	if session.RequireApproval {
+		return nil, s.requestApproval(session, userID)
+	}
+
+	if err := s.admit(sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.GetSession(sessionID, userID)
+}
+
+// admit добавляет нового участника с его реальными именем и аватаром
+func (s *SessionService) admit(sessionID string, userID string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user == nil {
+		return fmt.Errorf("failed to load user: %w", err)
 	}
 
-	participant := &entity.Participant{
+	return s.takeSeat(sessionID, &entity.Participant{
 		UserID:    userID,
 		UserName:  user.Name,
 		AvatarURL: user.AvatarURL,
 		IsReady:   false,
 		JoinedAt:  time.Now(),
+	})
+}
+
+// rejoinSession возвращает вышедшего участника в сессию, пока она не завершена.
+// Новые участники могут присоединиться только до старта, а вернуться можно и во время сессии
+func (s *SessionService) rejoinSession(session *entity.Session, userID string) (*entity.Session, error) {
+	if _, err := applyTransition(session, entity.SessionActionRejoin, actorOutsider); err != nil {
+		return nil, err
 	}
 
-	if err := s.sessionRepo.AddParticipant(sessionID, participant); err != nil {
-		return nil, fmt.Errorf("failed to add participant: %w", err)
+	if err := s.takeSeat(session.ID, &entity.Participant{UserID: userID, JoinedAt: time.Now()}); err != nil {
+		return nil, err
 	}
 
-	return s.GetSession(sessionID, userID)
+	return s.GetSession(session.ID, userID)
+}
+
+// takeSeat занимает место в сессии (новым или вернувшимся участником).
+// Если мест нет, пользователь встает в лист ожидания и возвращается *entity.WaitlistedError
+func (s *SessionService) takeSeat(sessionID string, participant *entity.Participant) error {
+	joined, err := s.sessionRepo.JoinWithinCapacity(sessionID, participant)
+	if err != nil {
+		return fmt.Errorf("failed to join session: %w", err)
+	}
+	if !joined {
+		return s.enqueue(sessionID, participant.UserID)
+	}
+
+	if err := s.waitlistRepo.Remove(sessionID, participant.UserID); err != nil {
+		return fmt.Errorf("failed to update waitlist: %w", err)
+	}
+
+	// Для вернувшегося участника интервал присутствия начинается сейчас, а не в момент первого входа
+	if err := s.attendanceRepo.Create(newAttendance(sessionID, participant.UserID, time.Now())); err != nil {
+		return fmt.Errorf("failed to record attendance: %w", err)
+	}
+
+	return nil
+}
+
+// enqueue ставит пользователя в лист ожидания; повторная запись сохраняет место в очереди
+func (s *SessionService) enqueue(sessionID string, userID string) error {
+	if err := s.waitlistRepo.Add(&entity.SessionWaitlistEntry{
+		SessionID: sessionID,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to add to waitlist: %w", err)
+	}
+
+	entries, err := s.waitlistRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get waitlist: %w", err)
+	}
+
+	position := len(entries)
+	for i, entry := range entries {
+		if entry.UserID == userID {
+			position = i + 1
+			break
+		}
+	}
+
+	return &entity.WaitlistedError{SessionID: sessionID, Position: position}
+}
+
+// LeaveWaitlist убирает пользователя из листа ожидания сессии
+func (s *SessionService) LeaveWaitlist(sessionID string, userID string) error {
+	if _, err := s.loadSession(sessionID); err != nil {
+		return err
+	}
+
+	if err := s.waitlistRepo.Remove(sessionID, userID); err != nil {
+		return fmt.Errorf("failed to leave waitlist: %w", err)
+	}
+	return nil
+}
+
+// promoteFromWaitlist отдает освободившиеся места ожидающим по очереди записи и сообщает им об этом в Telegram.
+// Ошибки только логируются: место освободил уже состоявшийся выход участника
+func (s *SessionService) promoteFromWaitlist(sessionID string) []string {
+	entries, err := s.waitlistRepo.GetBySessionID(sessionID)
+	if err != nil {
+		log.Printf("[SessionService] ❌ Failed to get waitlist of session %s: %v\n", sessionID, err)
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		log.Printf("[SessionService] ❌ Failed to load session %s for waitlist: %v\n", sessionID, err)
+		return nil
+	}
+
+	var promoted []string
+	for _, entry := range entries {
+		// Новые участники входят только до старта, а бывшие могут вернуться и позже
+		action := entity.SessionActionJoin
+		if participant := findParticipant(session, entry.UserID); participant != nil {
+			if participant.BannedAt != nil || participant.LeftAt == nil {
+				s.dropFromWaitlist(sessionID, entry.UserID)
+				continue
+			}
+			action = entity.SessionActionRejoin
+		}
+		if _, err := applyTransition(session, action, actorOutsider); err != nil {
+			s.dropFromWaitlist(sessionID, entry.UserID)
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(entry.UserID)
+		if err != nil || user == nil {
+			log.Printf("[SessionService] ⚠️ Cannot promote user %s in session %s: %v\n", entry.UserID, sessionID, err)
+			s.dropFromWaitlist(sessionID, entry.UserID)
+			continue
+		}
+
+		err = s.takeSeat(sessionID, &entity.Participant{
+			UserID:    user.ID,
+			UserName:  user.Name,
+			AvatarURL: user.AvatarURL,
+			JoinedAt:  time.Now(),
+		})
+		if err != nil {
+			// Мест больше нет (или ошибка БД) — остальные ждут следующего выхода
+			if !errors.Is(err, entity.ErrSessionFull) {
+				log.Printf("[SessionService] ❌ Failed to promote user %s in session %s: %v\n", entry.UserID, sessionID, err)
+			}
+			break
+		}
+
+		promoted = append(promoted, user.ID)
+		log.Printf("[SessionService] 🎟️ User %s promoted from waitlist of session %s\n", user.ID, sessionID)
+
+		if user.TelegramUserID != 0 {
+			text := fmt.Sprintf("Освободилось место — вы в сессии «%s»!", sessionTitle(session))
+			if _, err := s.telegramAPIService.SendMessageToUser(user.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+				log.Printf("[SessionService] ⚠️ Failed to notify promoted user %s: %v\n", user.ID, err)
+			}
+		}
+	}
+
+	return promoted
+}
+
+func (s *SessionService) dropFromWaitlist(sessionID string, userID string) {
+	if err := s.waitlistRepo.Remove(sessionID, userID); err != nil {
+		log.Printf("[SessionService] ❌ Failed to remove user %s from waitlist of session %s: %v\n", userID, sessionID, err)
+	}
+}
+
+// LeaveSession — мягкий выход: участник остаётся в истории и отчёте сессии и может вернуться.
+// Если выходит создатель, права переходят к участнику, который провёл в сессии больше всего времени;
+// newCreatorID пуст, если права не передавались. Освободившееся место получает первый из листа ожидания
+func (s *SessionService) LeaveSession(sessionID string, userID string) (string, []string, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if session.Mode == entity.SessionModeSolo {
+		return "", nil, entity.InvalidArgument("cannot leave solo session")
+	}
+	if _, err := applyTransition(session, entity.SessionActionLeave, resolveActor(session, userID)); err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	if err := s.sessionRepo.UpdateParticipantLeftAt(sessionID, userID, &now); err != nil {
+		return "", nil, fmt.Errorf("failed to leave session: %w", err)
+	}
+
+	if err := s.attendanceRepo.Close(sessionID, userID, now); err != nil {
+		return "", nil, fmt.Errorf("failed to record attendance: %w", err)
+	}
+
+	newCreatorID := ""
+	if session.CreatorID == userID {
+		newCreatorID, err = s.longestPresentParticipant(session, userID, now)
+		if err != nil {
+			return "", nil, err
+		}
+		// Пустой newCreatorID — в сессии никого не осталось, права остаются у создателя
+		if newCreatorID != "" {
+			if err := s.sessionRepo.UpdateCreator(sessionID, newCreatorID); err != nil {
+				return "", nil, fmt.Errorf("failed to transfer ownership: %w", err)
+			}
+		}
+	}
+
+	return newCreatorID, s.promoteFromWaitlist(sessionID), nil
+}
+
+// RemoveParticipant исключает участника из групповой сессии; с ban он не сможет вернуться.
+// Возвращает пользователей, получивших освободившееся место из листа ожидания
+func (s *SessionService) RemoveParticipant(sessionID string, userID string, targetUserID string, ban bool) ([]string, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Mode == entity.SessionModeSolo {
+		return nil, entity.InvalidArgument("cannot remove participants from solo session")
+	}
+	if _, err := applyTransition(session, entity.SessionActionRemoveParticipant, resolveActor(session, userID)); err != nil {
+		return nil, err
+	}
+	if targetUserID == userID {
+		return nil, entity.InvalidArgument("cannot remove yourself, leave the session instead")
+	}
+
+	participant := findParticipant(session, targetUserID)
+	if participant == nil {
+		return nil, entity.ErrParticipantNotFound
+	}
+
+	now := time.Now()
+	if ban && participant.BannedAt == nil {
+		if err := s.sessionRepo.UpdateParticipantBannedAt(sessionID, targetUserID, &now); err != nil {
+			return nil, fmt.Errorf("failed to ban participant: %w", err)
+		}
+	}
+
+	if participant.LeftAt != nil {
+		return nil, nil
+	}
+
+	if err := s.sessionRepo.UpdateParticipantLeftAt(sessionID, targetUserID, &now); err != nil {
+		return nil, fmt.Errorf("failed to remove participant: %w", err)
+	}
+	if err := s.attendanceRepo.Close(sessionID, targetUserID, now); err != nil {
+		return nil, fmt.Errorf("failed to record attendance: %w", err)
+	}
+
+	return s.promoteFromWaitlist(sessionID), nil
+}
+
+// TransferOwnership передаёт права создателя другому участнику сессии
+func (s *SessionService) TransferOwnership(sessionID string, userID string, newCreatorID string) error {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := applyTransition(session, entity.SessionActionTransferOwnership, resolveActor(session, userID)); err != nil {
+		return err
+	}
+	if newCreatorID == userID {
+		return entity.InvalidArgument("user is already the creator")
+	}
+	if !isPresent(session, newCreatorID) {
+		return entity.InvalidArgument("new creator must be a participant of the session")
+	}
+
+	if err := s.sessionRepo.UpdateCreator(sessionID, newCreatorID); err != nil {
+		return fmt.Errorf("failed to transfer ownership: %w", err)
+	}
+
+	return nil
+}
+
+// longestPresentParticipant выбирает среди оставшихся участников того, кто суммарно провёл в сессии
+// больше всего времени (при равенстве — кто присоединился раньше)
+func (s *SessionService) longestPresentParticipant(session *entity.Session, excludeUserID string, now time.Time) (string, error) {
+	attendance, err := s.attendanceRepo.GetBySessionID(session.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	presence := make(map[string]time.Duration)
+	for _, interval := range attendance {
+		leftAt := now
+		if interval.LeftAt != nil {
+			leftAt = *interval.LeftAt
+		}
+		presence[interval.UserID] += leftAt.Sub(interval.JoinedAt)
+	}
+
+	var best *entity.Participant
+	for i := range session.Participants {
+		p := &session.Participants[i]
+		if p.UserID == excludeUserID || p.LeftAt != nil {
+			continue
+		}
+		if best == nil ||
+			presence[p.UserID] > presence[best.UserID] ||
+			(presence[p.UserID] == presence[best.UserID] && p.JoinedAt.Before(best.JoinedAt)) {
+			best = p
+		}
+	}
+
+	if best == nil {
+		return "", nil
+	}
+	return best.UserID, nil
+}
+
+// loadSession загружает сессию вместе с участниками; если ее нет — entity.ErrSessionNotFound
+func (s *SessionService) loadSession(sessionID string) (*entity.Session, error) {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, entity.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// findParticipant возвращает участника сессии (в том числе вышедшего) или nil
+func findParticipant(session *entity.Session, userID string) *entity.Participant {
+	for i := range session.Participants {
+		if session.Participants[i].UserID == userID {
+			return &session.Participants[i]
+		}
+	}
+	return nil
+}
+
+// isPresent проверяет, что пользователь сейчас участвует в сессии (не вышел из неё)
+func isPresent(session *entity.Session, userID string) bool {
+	participant := findParticipant(session, userID)
+	return participant != nil && participant.LeftAt == nil
+}
+
+// maxPlannedPhases ограничивает длину плана циклов (фазы фокуса и перерывов вместе)
+const maxPlannedPhases = 48
+
+// normalizeCyclePlan проверяет план циклов; длинный перерыв без периода идет после каждого DefaultLongBreakEvery-го цикла
+func normalizeCyclePlan(plan entity.CyclePlan) (entity.CyclePlan, error) {
+	if len(plan.Phases) > 0 {
+		if plan.Cycles != 0 || plan.LongBreak != 0 || plan.LongBreakEvery != 0 {
+			return plan, entity.InvalidArgument("phases cannot be combined with cycles or long break settings")
+		}
+		if len(plan.Phases) > maxPlannedPhases {
+			return plan, entity.InvalidArgument(fmt.Sprintf("phases must contain at most %d entries", maxPlannedPhases))
+		}
+		if plan.Phases[0].Phase != entity.SessionPhaseFocus {
+			return plan, entity.InvalidArgument("phases must start with focus")
+		}
+		for _, phase := range plan.Phases {
+			switch phase.Phase {
+			case entity.SessionPhaseFocus, entity.SessionPhaseBreak, entity.SessionPhaseLongBreak:
+			default:
+				return plan, entity.InvalidArgument("invalid phase: must be 'focus', 'break' or 'long_break'")
+			}
+			if phase.Duration <= 0 || phase.Duration > 240 {
+				return plan, entity.InvalidArgument("phase duration must be between 1 and 240 minutes")
+			}
+		}
+		return plan, nil
+	}
+
+	if plan.Cycles < 0 || plan.Cycles > maxPlannedPhases/2 {
+		return plan, entity.InvalidArgument(fmt.Sprintf("cycles must be between 0 and %d", maxPlannedPhases/2))
+	}
+	if plan.LongBreak < 0 || plan.LongBreak > 240 {
+		return plan, entity.InvalidArgument("longBreak must be between 0 and 240 minutes")
+	}
+	if plan.LongBreakEvery < 0 {
+		return plan, entity.InvalidArgument("longBreakEvery must not be negative")
+	}
+	if plan.LongBreak == 0 {
+		plan.LongBreakEvery = 0
+	} else if plan.LongBreakEvery == 0 {
+		plan.LongBreakEvery = entity.DefaultLongBreakEvery
+	}
+	return plan, nil
+}
+
+// normalizeStartPolicy проверяет настройки автостарта; пустая политика — ручной старт
+func normalizeStartPolicy(mode entity.SessionMode, policy entity.StartPolicy, quorum int, countdown *int) (entity.StartPolicy, error) {
+	switch policy {
+	case "", entity.StartPolicyManual:
+		return entity.StartPolicyManual, nil
+	case entity.StartPolicyAllReady, entity.StartPolicyQuorum:
+	default:
+		return "", entity.InvalidArgument("invalid start policy: must be 'manual', 'all_ready' or 'quorum'")
+	}
+
+	if mode != entity.SessionModeGroup {
+		return "", entity.InvalidArgument("start policy is only available for group sessions")
+	}
+	if policy == entity.StartPolicyQuorum && quorum < 2 {
+		return "", entity.InvalidArgument("startQuorum must be at least 2")
+	}
+	if countdown != nil && (*countdown < 0 || *countdown > 300) {
+		return "", entity.InvalidArgument("startCountdown must be between 0 and 300 seconds")
+	}
+	return policy, nil
+}
+
+// readiness возвращает число присутствующих участников и сколько из них готовы
+func readiness(session *entity.Session) (present int, ready int) {
+	for _, participant := range session.Participants {
+		if participant.LeftAt != nil {
+			continue
+		}
+		present++
+		if participant.IsReady {
+			ready++
+		}
+	}
+	return present, ready
+}
+
+// startPolicyMet — выполнено ли условие автостарта сессии
+func startPolicyMet(session *entity.Session) bool {
+	present, ready := readiness(session)
+
+	switch session.StartPolicy {
+	case entity.StartPolicyAllReady:
+		return present >= 2 && ready == present
+	case entity.StartPolicyQuorum:
+		return ready >= session.StartQuorum
+	default:
+		return false
+	}
+}
+
+// notReadyParticipants — присутствующие участники, не подтвердившие готовность (кроме exceptUserID)
+func notReadyParticipants(session *entity.Session, exceptUserID string) []string {
+	var userIDs []string
+	for _, participant := range session.Participants {
+		if participant.LeftAt == nil && !participant.IsReady && participant.UserID != exceptUserID {
+			userIDs = append(userIDs, participant.UserID)
+		}
+	}
+	return userIDs
+}
+
+func newAttendance(sessionID string, userID string, joinedAt time.Time) *entity.SessionAttendance {
+	return &entity.SessionAttendance{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		UserID:    userID,
+		JoinedAt:  joinedAt,
+	}
 }
 
 func (s *SessionService) JoinByInviteLink(inviteLink string, userID string) (*entity.Session, error) {
@@ -246,137 +762,346 @@ func (s *SessionService) JoinByInviteLink(inviteLink string, userID string) (*en
 
 	session, err := s.sessionRepo.GetByInviteLink(cleanInviteLink)
 	if err != nil {
-		return nil, fmt.Errorf("session not found by invite link: %w", err)
+		return nil, fmt.Errorf("failed to get session by invite link: %w", err)
 	}
 
 	if session == nil {
-		return nil, fmt.Errorf("session not found by invite link")
-	}
-
-	// Проверяем, не присоединен ли уже пользователь
-	for _, p := range session.Participants {
-		if p.UserID == userID {
-			// Уже участник, просто возвращаем сессию
-			return s.GetSession(session.ID, userID)
-		}
+		return nil, fmt.Errorf("%w by invite link", entity.ErrSessionNotFound)
 	}
 
-	// Присоединяем пользователя
+	// Присоединяем пользователя (уже присоединенный получит сессию, вышедший — вернется в нее)
 	return s.JoinSession(session.ID, userID)
 }
 
+// SetReady отмечает готовность участника. Проверка общая для REST и WebSocket: отмечаться могут только участники сессии
 func (s *SessionService) SetReady(sessionID string, userID string, isReady bool) error {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if resolveActor(session, userID) == actorOutsider {
+		return entity.Forbidden("only participants can set readiness")
+	}
+
 	return s.sessionRepo.UpdateParticipantReady(sessionID, userID, isReady)
 }
 
-func (s *SessionService) StartSession(sessionID string, userID string) error {
-	session, err := s.sessionRepo.GetByID(sessionID)
+// StartSession запускает сессию вручную. Если у сессии есть политика автостарта, а условие еще не выполнено,
+// нужен force ("начать без остальных"). Возвращает участников, которые не успели подтвердить готовность
+func (s *SessionService) StartSession(sessionID string, userID string, force bool) ([]string, error) {
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return nil, err
 	}
 
-	if session.CreatorID != userID {
-		return fmt.Errorf("only creator can start session")
+	if session.StartPolicy != "" && session.StartPolicy != entity.StartPolicyManual && !force && !startPolicyMet(session) {
+		return nil, &entity.DomainError{
+			Kind:   entity.ErrInvalidTransition,
+			Reason: "not enough participants are ready, use force to start anyway",
+		}
 	}
 
-	if session.Status != entity.SessionStatusPending {
-		return fmt.Errorf("session already started")
+	late := notReadyParticipants(session, userID)
+	if err := s.startSession(session, resolveActor(session, userID)); err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
-	session.Status = entity.SessionStatusActive
-	session.StartedAt = &now
+	return late, nil
+}
+
+// UpdateStartPolicy меняет политику автостарта ожидающей сессии; идущий обратный отсчет сбрасывается
+func (s *SessionService) UpdateStartPolicy(sessionID string, userID string, policy entity.StartPolicy, quorum int, countdown *int) (*entity.Session, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := applyTransition(session, entity.SessionActionConfigureStart, resolveActor(session, userID)); err != nil {
+		return nil, err
+	}
+
+	normalized, err := normalizeStartPolicy(session.Mode, policy, quorum, countdown)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.sessionRepo.Update(session)
+	session.StartPolicy = normalized
+	session.StartQuorum = quorum
+	session.StartCountdown = countdown
+	session.AutoStartAt = nil
+
+	if err := s.sessionRepo.Update(session); err != nil {
+		return nil, fmt.Errorf("failed to update start policy: %w", err)
+	}
+
+	return session, nil
 }
 
-func (s *SessionService) PauseSession(sessionID string, userID string) error {
-	session, err := s.sessionRepo.GetByID(sessionID)
+// CancelAutoStart останавливает обратный отсчет. Готовность участников сбрасывается,
+// иначе условие автостарта сразу выполнится снова
+func (s *SessionService) CancelAutoStart(sessionID string, userID string) error {
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return err
 	}
 
-	// Verify user is participant or creator
-	if session.CreatorID != userID {
-		// For group sessions, check if user is participant
-		if session.Mode == entity.SessionModeGroup {
-			isParticipant := false
-			for _, p := range session.Participants {
-				if p.UserID == userID {
-					isParticipant = true
-					break
-				}
-			}
-			if !isParticipant {
-				return fmt.Errorf("user not authorized to pause session")
-			}
-		} else {
-			return fmt.Errorf("only creator can pause solo session")
+	if _, err := applyTransition(session, entity.SessionActionConfigureStart, resolveActor(session, userID)); err != nil {
+		return err
+	}
+	if session.AutoStartAt == nil {
+		return &entity.DomainError{Kind: entity.ErrInvalidTransition, Reason: "countdown is not running"}
+	}
+
+	cancelled, err := s.sessionRepo.CancelAutoStart(sessionID, *session.AutoStartAt)
+	if err != nil {
+		return fmt.Errorf("failed to cancel countdown: %w", err)
+	}
+	if !cancelled {
+		return &entity.DomainError{Kind: entity.ErrInvalidTransition, Reason: "countdown already finished"}
+	}
+
+	return nil
+}
+
+// AutoStartSession запускает сессию от имени системы: по расписанию или по окончании обратного отсчета
+func (s *SessionService) AutoStartSession(sessionID string) (*entity.Session, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.startSession(session, actorSystem); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// AutoCompleteSession завершает сессию, план циклов которой закончился в at (SessionTimerService).
+// elapsed — фазы, прошедшие после текущей, пока таймер догонял план; они сохраняются, только если сессию завершил этот вызов
+func (s *SessionService) AutoCompleteSession(sessionID string, at time.Time, elapsed []*entity.SessionPhaseSegment) (*entity.SessionReport, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Пауза или переключение фазы после чтения таймером сдвигают конец плана
+	if session.Status != entity.SessionStatusActive || session.PhaseEndsAt == nil || session.PhaseEndsAt.After(at) {
+		return nil, fmt.Errorf("cycle plan is not over: %w", entity.ErrInvalidTransition)
+	}
+
+	return s.completeSession(session, actorSystem, at, elapsed)
+}
+
+// startSession переводит сессию в active и открывает первую фазу.
+// Статус меняется через compare-and-set, чтобы ручной старт и планировщик не запустили сессию дважды
+func (s *SessionService) startSession(session *entity.Session, actor sessionActor) error {
+	if _, err := checkTransition(session, entity.SessionActionStart, actor); err != nil {
+		return err
+	}
+
+	// Новое состояние собираем в копии и переносим в сессию только после compare-and-set:
+	// репозиторий может отдавать общий с хранилищем объект
+	now := time.Now()
+	started := *session
+	started.Status = entity.SessionStatusActive
+	started.StartedAt = &now
+	started.AutoStartAt = nil
+
+	// Первый цикл начинается с первой фазы плана (всегда фокус), дальше фазы переключает SessionTimerService
+	started.CurrentCycle = 1
+	startPhase(&started, 0, now)
+
+	ok, err := s.sessionRepo.Start(&started)
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("session already started: %w", entity.ErrInvalidTransition)
+	}
+	session.Status = started.Status
+	session.StartedAt = started.StartedAt
+	session.AutoStartAt = nil
+	session.CurrentCycle = started.CurrentCycle
+	session.PhaseIndex = started.PhaseIndex
+	session.CurrentPhase = started.CurrentPhase
+	session.PhaseStartedAt = started.PhaseStartedAt
+	session.PhaseEndsAt = started.PhaseEndsAt
+
+	if session.CurrentPhase != "" {
+		if err := s.phaseSegmentRepo.Create(newPhaseSegment(session, now, nil)); err != nil {
+			return fmt.Errorf("failed to open phase segment: %w", err)
 		}
 	}
 
-	if session.Status != entity.SessionStatusActive {
-		// Idempotent: if already paused, do nothing
-		if session.Status == entity.SessionStatusPaused {
-			return nil
+	s.closeWaitlist(session)
+	return nil
+}
+
+// closeWaitlist убирает из листа ожидания начавшейся сессии тех, кто в ней не был: новые участники входят только до старта.
+// Бывшие участники остаются в очереди и могут вернуться на освободившееся место. Ошибки только логируются
+func (s *SessionService) closeWaitlist(session *entity.Session) {
+	entries, err := s.waitlistRepo.GetBySessionID(session.ID)
+	if err != nil {
+		log.Printf("[SessionService] ❌ Failed to get waitlist of session %s: %v\n", session.ID, err)
+		return
+	}
+
+	for _, entry := range entries {
+		if participant := findParticipant(session, entry.UserID); participant != nil && participant.BannedAt == nil {
+			continue
+		}
+		s.dropFromWaitlist(session.ID, entry.UserID)
+
+		user, err := s.userRepo.GetByID(entry.UserID)
+		if err != nil || user == nil || user.TelegramUserID == 0 {
+			continue
+		}
+		text := fmt.Sprintf("Сессия «%s» началась, а место так и не освободилось — вы убраны из листа ожидания", sessionTitle(session))
+		if _, err := s.telegramAPIService.SendMessageToUser(user.TelegramUserID, &telegramapi.SendMessageRequest{Text: text}); err != nil {
+			log.Printf("[SessionService] ⚠️ Failed to notify user %s about closed waitlist: %v\n", user.ID, err)
 		}
-		return fmt.Errorf("session is not active")
+	}
+}
+
+func (s *SessionService) PauseSession(sessionID string, userID string) error {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Idempotent: if already paused, do nothing
+	changed, err := checkTransition(session, entity.SessionActionPause, resolveActor(session, userID))
+	if err != nil || !changed {
+		return err
+	}
+
+	// Только статус и начало паузы: фазу мог параллельно переключить таймер
+	now := time.Now()
+	paused, err := s.sessionRepo.Pause(session.ID, now)
+	if err != nil {
+		return err
+	}
+	if !paused {
+		return fmt.Errorf("session is no longer active: %w", entity.ErrInvalidTransition)
+	}
+
+	// Время паузы не входит ни в фокус, ни в перерыв
+	if err := s.phaseSegmentRepo.CloseOpen(session.ID, now); err != nil {
+		return fmt.Errorf("failed to close phase segment: %w", err)
 	}
 
-	session.Status = entity.SessionStatusPaused
-	return s.sessionRepo.Update(session)
+	return nil
 }
 
 func (s *SessionService) ResumeSession(sessionID string, userID string) error {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return err
 	}
 
-	// Verify user is participant or creator
-	if session.CreatorID != userID {
-		// For group sessions, check if user is participant
-		if session.Mode == entity.SessionModeGroup {
-			isParticipant := false
-			for _, p := range session.Participants {
-				if p.UserID == userID {
-					isParticipant = true
-					break
-				}
-			}
-			if !isParticipant {
-				return fmt.Errorf("user not authorized to resume session")
-			}
-		} else {
-			return fmt.Errorf("only creator can resume solo session")
+	// Idempotent: if already active, do nothing
+	changed, err := checkTransition(session, entity.SessionActionResume, resolveActor(session, userID))
+	if err != nil || !changed {
+		return err
+	}
+	if session.PausedAt == nil {
+		return fmt.Errorf("paused session has no pause start: %w", entity.ErrInvalidTransition)
+	}
+
+	// Новое состояние собираем в копии: загруженная сессия нужна для compare-and-set по началу паузы
+	now := time.Now()
+	pausedAt := *session.PausedAt
+	resumed := *session
+	resumed.Status = entity.SessionStatusActive
+	pauseDuration := endPause(&resumed, now)
+
+	// Фаза продолжается с того же места: дедлайн сдвигается на длительность паузы
+	if resumed.PhaseStartedAt != nil && resumed.PhaseEndsAt != nil {
+		phaseStartedAt := resumed.PhaseStartedAt.Add(pauseDuration)
+		phaseEndsAt := resumed.PhaseEndsAt.Add(pauseDuration)
+		resumed.PhaseStartedAt = &phaseStartedAt
+		resumed.PhaseEndsAt = &phaseEndsAt
+	}
+
+	ok, err := s.sessionRepo.Resume(&resumed, pausedAt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("session is no longer paused: %w", entity.ErrInvalidTransition)
+	}
+
+	if resumed.CurrentPhase != "" {
+		if err := s.phaseSegmentRepo.Create(newPhaseSegment(&resumed, now, nil)); err != nil {
+			return fmt.Errorf("failed to open phase segment: %w", err)
 		}
 	}
 
-	// Idempotent: if already active, do nothing
-	if session.Status == entity.SessionStatusActive {
-		return nil
+	return nil
+}
+
+// endPause снимает сессию с паузы: добавляет длительность паузы к TotalPauseTime и сбрасывает PausedAt
+func endPause(session *entity.Session, now time.Time) time.Duration {
+	if session.PausedAt == nil {
+		return 0
 	}
 
-	if session.Status != entity.SessionStatusPaused {
-		return fmt.Errorf("session is not paused")
+	pauseDuration := now.Sub(*session.PausedAt)
+	if pauseDuration < 0 {
+		pauseDuration = 0
 	}
 
-	session.Status = entity.SessionStatusActive
-	return s.sessionRepo.Update(session)
+	session.TotalPauseTime += pauseDuration.Milliseconds()
+	session.PausedAt = nil
+
+	return pauseDuration
 }
 
 func (s *SessionService) CompleteSession(sessionID string, userID string) (*entity.SessionReport, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
 	}
 
-	now := time.Now()
-	session.Status = entity.SessionStatusCompleted
-	session.CompletedAt = &now
+	report, err := s.completeSession(session, resolveActor(session, userID), time.Now(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.sessionRepo.Update(session); err != nil {
-		return nil, fmt.Errorf("failed to update session: %w", err)
+	// Дата отчета — по часовому поясу завершившего
+	report.CompletedAt = report.CompletedAt.In(s.userLocation(userID))
+	return report, nil
+}
+
+// completeSession завершает сессию в момент now, формирует отчет и предлагает создать чат для обсуждения.
+// elapsed — прошедшие фазы, которые таймер еще не сохранил
+func (s *SessionService) completeSession(session *entity.Session, actor sessionActor, now time.Time, elapsed []*entity.SessionPhaseSegment) (*entity.SessionReport, error) {
+	sessionID := session.ID
+
+	// Завершение, отмена и таймер гонятся за одну сессию: отчет, статистику и чат получает только тот, чей compare-and-set прошел
+	session, err := s.finishSession(session, entity.SessionActionComplete, actor, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Текущий отрезок заканчивается там, где начинается первая прошедшая фаза
+	closeAt := now
+	if len(elapsed) > 0 {
+		closeAt = elapsed[0].StartedAt
+	}
+	if err := s.phaseSegmentRepo.CloseOpen(session.ID, closeAt); err != nil {
+		return nil, fmt.Errorf("failed to close phase segment: %w", err)
+	}
+	for _, segment := range elapsed {
+		if err := s.phaseSegmentRepo.Create(segment); err != nil {
+			return nil, fmt.Errorf("failed to save phase segment: %w", err)
+		}
+	}
+
+	if err := s.attendanceRepo.CloseAll(session.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to close attendance: %w", err)
 	}
 
 	tasks, err := s.taskRepo.GetBySessionID(sessionID)
@@ -384,7 +1109,28 @@ func (s *SessionService) CompleteSession(sessionID string, userID string) (*enti
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	report := s.buildSessionReport(session, tasks, now)
+	segments, err := s.phaseSegmentRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phase segments: %w", err)
+	}
+
+	attendance, err := s.attendanceRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	report := s.buildSessionReport(session, tasks, segments, attendance, now)
+
+	// Ошибка статистики не отменяет завершение: ее можно пересчитать по истории
+	unlocked, err := s.recordSessionStats(session, report)
+	if err != nil {
+		log.Printf("[SessionService] ❌ Failed to record stats of session %s: %v\n", sessionID, err)
+	}
+	report.Achievements = unlocked
+	s.announceAchievements(unlocked)
+	if err := s.recordSessionContacts(session, now); err != nil {
+		log.Printf("[SessionService] ❌ Failed to record contacts of session %s: %v\n", sessionID, err)
+	}
 
 	// Создаем чат для обсуждения после завершения сессии
 	// Отправляем сообщение создателю с кнопкой для создания чата
@@ -397,17 +1143,65 @@ func (s *SessionService) CompleteSession(sessionID string, userID string) (*enti
 	return report, nil
 }
 
-func (s *SessionService) GetSessionReport(sessionID string, userID string) (*entity.SessionReport, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+// CancelSession отменяет сессию, которая еще не завершена. В отличие от завершения отчет и статистика не формируются
+func (s *SessionService) CancelSession(sessionID string, userID string) error {
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return err
 	}
-	if session == nil {
-		return nil, fmt.Errorf("session not found")
+
+	now := time.Now()
+	if _, err := s.finishSession(session, entity.SessionActionCancel, resolveActor(session, userID), now); err != nil {
+		return err
+	}
+
+	if err := s.phaseSegmentRepo.CloseOpen(session.ID, now); err != nil {
+		return fmt.Errorf("failed to close phase segment: %w", err)
+	}
+
+	if err := s.attendanceRepo.CloseAll(session.ID, now); err != nil {
+		return fmt.Errorf("failed to close attendance: %w", err)
+	}
+
+	return nil
+}
+
+// finishSession переводит сессию в completed или cancelled через compare-and-set по загруженному статусу
+// и возвращает сохраненное состояние. Загруженную сессию не меняет: репозиторий может отдавать общий с хранилищем объект
+func (s *SessionService) finishSession(session *entity.Session, action entity.SessionAction, actor sessionActor, now time.Time) (*entity.Session, error) {
+	if _, err := checkTransition(session, action, actor); err != nil {
+		return nil, err
+	}
+
+	finished := *session
+	finished.Status = sessionTransitions[action].to
+	if action == entity.SessionActionComplete {
+		finished.CompletedAt = &now
+	}
+	endPause(&finished, now)
+	finished.CurrentPhase = ""
+	finished.PhaseStartedAt = nil
+	finished.PhaseEndsAt = nil
+
+	ok, err := s.sessionRepo.Finish(&finished, session.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("session is no longer %s: %w", session.Status, entity.ErrInvalidTransition)
+	}
+
+	return &finished, nil
+}
+
+func (s *SessionService) GetSessionReport(sessionID string, userID string) (*entity.SessionReport, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
 	}
 
 	if !s.hasAccessToSession(session, userID) {
-		return nil, fmt.Errorf("access denied")
+		return nil, entity.Forbidden("access denied")
 	}
 
 	tasks, err := s.taskRepo.GetBySessionID(sessionID)
@@ -415,6 +1209,16 @@ func (s *SessionService) GetSessionReport(sessionID string, userID string) (*ent
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
+	segments, err := s.phaseSegmentRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phase segments: %w", err)
+	}
+
+	attendance, err := s.attendanceRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
 	completedAt := time.Now()
 	if session.CompletedAt != nil {
 		completedAt = *session.CompletedAt
@@ -422,33 +1226,66 @@ func (s *SessionService) GetSessionReport(sessionID string, userID string) (*ent
 		completedAt = *session.StartedAt
 	}
 
-	return s.buildSessionReport(session, tasks, completedAt), nil
+	// Дата отчета — по часовому поясу запросившего
+	return s.buildSessionReport(session, tasks, segments, attendance, completedAt.In(s.userLocation(userID))), nil
 }
 
-func (s *SessionService) buildSessionReport(session *entity.Session, tasks []*entity.Task, completedAt time.Time) *entity.SessionReport {
+func (s *SessionService) buildSessionReport(
+	session *entity.Session,
+	tasks []*entity.Task,
+	segments []*entity.SessionPhaseSegment,
+	attendance []*entity.SessionAttendance,
+	completedAt time.Time,
+) *entity.SessionReport {
 	cycles := session.CurrentCycle
 	if cycles <= 0 {
 		cycles = 1
 	}
 
-	focusMinutes := session.FocusDuration * cycles
-	if focusMinutes < 0 {
-		focusMinutes = 0
+	// Текущий отрезок незавершённой сессии считаем до текущего момента
+	until := time.Now()
+	if session.CompletedAt != nil {
+		until = *session.CompletedAt
 	}
 
-	breakMinutes := session.BreakDuration * cycles
-	if breakMinutes < 0 {
-		breakMinutes = 0
+	var focusMinutes, breakMinutes int
+	if len(segments) > 0 {
+		// Фактическое время по отрезкам фаз
+		focus, breaks := phaseDurations(segments, until)
+		focusMinutes = durationToMinutes(focus)
+		breakMinutes = durationToMinutes(breaks)
+	} else {
+		// Сессии без отрезков фаз (начаты до их появления) — оценка по плановым длительностям
+		focusMinutes = session.FocusDuration * cycles
+		if focusMinutes < 0 {
+			focusMinutes = 0
+		}
+
+		breakMinutes = session.BreakDuration * cycles
+		if breakMinutes < 0 {
+			breakMinutes = 0
+		}
+	}
+
+	intervalsByUser := make(map[string][]*entity.SessionAttendance)
+	for _, interval := range attendance {
+		intervalsByUser[interval.UserID] = append(intervalsByUser[interval.UserID], interval)
 	}
 
 	statsByUser := make(map[string]*entity.ParticipantReport, len(session.Participants))
 	for _, participant := range session.Participants {
+		// Личное время фокуса — только то, когда участник был в сессии
+		participantFocus := focusMinutes
+		if intervals, ok := intervalsByUser[participant.UserID]; ok && len(segments) > 0 {
+			participantFocus = durationToMinutes(attendedFocus(segments, intervals, until))
+		}
+
 		statsByUser[participant.UserID] = &entity.ParticipantReport{
 			UserID:         participant.UserID,
 			UserName:       participant.UserName,
 			AvatarURL:      participant.AvatarURL,
 			TasksCompleted: 0,
-			FocusTime:      focusMinutes,
+			FocusTime:      participantFocus,
 		}
 	}
 
@@ -473,6 +1310,16 @@ func (s *SessionService) buildSessionReport(session *entity.Session, tasks []*en
 		}
 	}
 
+	// Все задачи участника, включая невыполненные
+	for _, task := range tasks {
+		if task.UserID == nil {
+			continue
+		}
+		if stats := statsByUser[*task.UserID]; stats != nil {
+			stats.TasksTotal++
+		}
+	}
+
 	participants := make([]entity.ParticipantReport, 0, len(statsByUser))
 	for _, stats := range statsByUser {
 		participants = append(participants, *stats)
@@ -492,11 +1339,60 @@ func (s *SessionService) buildSessionReport(session *entity.Session, tasks []*en
 		FocusTime:       focusMinutes,
 		BreakTime:       breakMinutes,
 		CyclesCompleted: cycles,
+		CyclesPlanned:   session.PlannedCycles(),
+		PlanCompleted:   planCompleted(session),
 		Participants:    participants,
 		CompletedAt:     completedAt,
 	}
 }
 
+// planCompleted проверяет, что сессия с конечным планом прошла его до конца:
+// движок фаз очищает текущую фазу, только когда план исчерпан
+func planCompleted(session *entity.Session) bool {
+	return session.PlannedCycles() > 0 && session.StartedAt != nil && session.CurrentPhase == ""
+}
+
+// attendedFocus считает время фокуса, в которое участник был в сессии:
+// пересечение его интервалов присутствия с отрезками фокуса
+func attendedFocus(segments []*entity.SessionPhaseSegment, intervals []*entity.SessionAttendance, until time.Time) time.Duration {
+	var total time.Duration
+	for _, segment := range segments {
+		if segment.Phase != entity.SessionPhaseFocus {
+			continue
+		}
+
+		segmentEnd := until
+		if segment.EndedAt != nil {
+			segmentEnd = *segment.EndedAt
+		}
+
+		for _, interval := range intervals {
+			intervalEnd := until
+			if interval.LeftAt != nil {
+				intervalEnd = *interval.LeftAt
+			}
+
+			start := segment.StartedAt
+			if interval.JoinedAt.After(start) {
+				start = interval.JoinedAt
+			}
+			end := segmentEnd
+			if intervalEnd.Before(end) {
+				end = intervalEnd
+			}
+
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+func durationToMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
 func (s *SessionService) hasAccessToSession(session *entity.Session, userID string) bool {
 	if session.CreatorID == userID {
 		return true
@@ -578,9 +1474,9 @@ func (s *SessionService) HandleChatCreated(update interface{}) error {
 	}
 
 	// Находим сессию
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return err
 	}
 
 	// Сохраняем информацию о чате в сессии
@@ -652,19 +1548,19 @@ func (s *SessionService) addParticipantsToChat(session *entity.Session, chatID i
 
 // DeleteChatAfterDiscussion удаляет чат для обсуждения после окончания обсуждения
 func (s *SessionService) DeleteChatAfterDiscussion(sessionID string, userID string) error {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return err
 	}
 
 	// Проверяем права доступа - только создатель может удалить чат
 	if session.CreatorID != userID {
-		return fmt.Errorf("only creator can delete chat")
+		return entity.Forbidden("only creator can delete chat")
 	}
 
 	// Проверяем, что сессия завершена
 	if session.Status != entity.SessionStatusCompleted {
-		return fmt.Errorf("can only delete chat after session completion")
+		return fmt.Errorf("%w: can only delete chat after session completion", entity.ErrInvalidTransition)
 	}
 
 	// Удаляем чат в Telegram API, если он существует
@@ -681,7 +1577,7 @@ func (s *SessionService) DeleteChatAfterDiscussion(sessionID string, userID stri
 			return fmt.Errorf("failed to update session: %w", err)
 		}
 	} else {
-		return fmt.Errorf("chat does not exist for this session")
+		return fmt.Errorf("chat %w for this session", entity.ErrNotFound)
 	}
 
 	return nil
@@ -689,46 +1585,44 @@ func (s *SessionService) DeleteChatAfterDiscussion(sessionID string, userID stri
 
 // DeleteSession удаляет сессию и связанный чат Telegram (если есть)
 func (s *SessionService) DeleteSession(sessionID string, userID string) error {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return fmt.Errorf("session not found: %w", err)
+		return err
 	}
 
 	// Проверяем права доступа - только создатель может удалить сессию
 	if session.CreatorID != userID {
-		return fmt.Errorf("only creator can delete session")
+		return entity.Forbidden("only creator can delete session")
 	}
 
 	// Удаляем чат в Telegram API, если он существует
 	if session.TelegramChatID != nil {
-		err := s.telegramAPIService.DeleteChat(*session.TelegramChatID)
-		if err != nil {
-			// Логируем ошибку, но не прерываем удаление сессии
-			// Чат может быть уже удален пользователем вручную или иметь другие проблемы
-			// В реальности здесь должно быть логирование
-			_ = err // Игнорируем ошибку удаления чата
+		if err := s.telegramAPIService.DeleteChat(*session.TelegramChatID); err != nil {
+			// Не прерываем удаление сессии: чат мог быть уже удален пользователем вручную
+			log.Printf("[SessionService] ⚠️ Failed to delete Telegram chat %d for session %s: %v\n", *session.TelegramChatID, sessionID, err)
 		}
 	}
 
-	// Удаляем сессию из репозитория
-	// Примечание: в реальности нужно проверить, есть ли метод Delete в репозитории
-	// Если нет, можно обновить статус на "cancelled" или "deleted"
-	return fmt.Errorf("delete method not implemented in repository")
+	if err := s.sessionRepo.Delete(sessionID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
 }
 
 func (s *SessionService) UpdateTask(sessionID string, taskID string, userID string, completed bool) (*entity.Task, error) {
 	task, err := s.taskRepo.GetByID(taskID)
 	if err != nil {
-		return nil, fmt.Errorf("task not found: %w", err)
+		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	if task.SessionID != sessionID {
-		return nil, fmt.Errorf("task does not belong to session")
+	if task == nil || task.SessionID != sessionID {
+		return nil, entity.ErrTaskNotFound
 	}
 
 	// Проверяем что задача принадлежит текущему пользователю
 	if task.UserID == nil || *task.UserID != userID {
-		return nil, fmt.Errorf("task does not belong to user")
+		return nil, entity.Forbidden("task does not belong to user")
 	}
 
 	task.Completed = completed
@@ -746,7 +1640,17 @@ func (s *SessionService) UpdateTask(sessionID string, taskID string, userID stri
 	return task, nil
 }
 
+// AddTask добавляет задачу пользователю. Проверка общая для REST и WebSocket: задачи добавляют только участники сессии
 func (s *SessionService) AddTask(sessionID string, userID string, title string) (*entity.Task, error) {
+	session, err := s.loadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolveActor(session, userID) == actorOutsider {
+		return nil, entity.Forbidden("only participants can add tasks")
+	}
+
 	task := &entity.Task{
 		ID:        uuid.New().String(),
 		Title:     title,
@@ -766,42 +1670,38 @@ func (s *SessionService) AddTask(sessionID string, userID string, title string)
 func (s *SessionService) DeleteTask(sessionID string, taskID string, userID string) error {
 	task, err := s.taskRepo.GetByID(taskID)
 	if err != nil {
-		return fmt.Errorf("task not found: %w", err)
+		return fmt.Errorf("failed to get task: %w", err)
 	}
 
-	if task.SessionID != sessionID {
-		return fmt.Errorf("task does not belong to session")
+	if task == nil || task.SessionID != sessionID {
+		return entity.ErrTaskNotFound
 	}
 
 	// Проверяем что задача принадлежит текущему пользователю
 	if task.UserID == nil || *task.UserID != userID {
-		return fmt.Errorf("task does not belong to user")
+		return entity.Forbidden("task does not belong to user")
 	}
 
 	return s.taskRepo.Delete(taskID)
 }
 
 func (s *SessionService) GetParticipantsProgress(sessionID string, userID string) ([]entity.ParticipantProgress, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
 	}
 
 	// Verify user is participant
-	isParticipant := false
-	for _, p := range session.Participants {
-		if p.UserID == userID {
-			isParticipant = true
-			break
-		}
-	}
-	if !isParticipant {
-		return nil, fmt.Errorf("user is not a participant")
+	if findParticipant(session, userID) == nil {
+		return nil, entity.Forbidden("user is not a participant")
 	}
 
-	// Get progress for all participants
+	// Get progress for all participants who are still in the session
 	progressList := make([]entity.ParticipantProgress, 0, len(session.Participants))
 	for _, p := range session.Participants {
+		if p.LeftAt != nil {
+			continue
+		}
 		total, completed, err := s.taskRepo.CountBySessionIDAndUserID(sessionID, p.UserID)
 		if err != nil {
 			// Log error but continue with other participants
@@ -827,13 +1727,13 @@ func (s *SessionService) GetParticipantsProgress(sessionID string, userID string
 }
 
 func (s *SessionService) InviteUsers(sessionID string, userID string, userIDs []string) (int, string, error) {
-	session, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.loadSession(sessionID)
 	if err != nil {
-		return 0, "", fmt.Errorf("session not found: %w", err)
+		return 0, "", err
 	}
 
 	if session.CreatorID != userID {
-		return 0, "", fmt.Errorf("only creator can invite users")
+		return 0, "", entity.Forbidden("only creator can invite users")
 	}
 
 	// В реальности отправляем приглашения через Telegram API
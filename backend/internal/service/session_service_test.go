@@ -0,0 +1,279 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// startedSession создает групповую сессию user1 с участником user2 и запускает ее
+func (e *testEnv) startedSession(t *testing.T) *entity.Session {
+	t.Helper()
+
+	session := e.groupSession(t, entity.SessionSettings{}, testUserID(2))
+	if _, err := e.service.StartSession(session.ID, testUserID(1), true); err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	return e.session(t, session.ID)
+}
+
+func TestCompleteSessionOnce(t *testing.T) {
+	env := newTestEnv(t, 2)
+	session := env.startedSession(t)
+
+	if _, err := env.service.CompleteSession(session.ID, testUserID(1)); err != nil {
+		t.Fatalf("CompleteSession: %v", err)
+	}
+	chatOffers := len(env.telegram.messages(101))
+	if chatOffers != 1 {
+		t.Fatalf("creator got %d discussion chat offers, want 1", chatOffers)
+	}
+
+	// Повторное завершение и отмена проигрывают compare-and-set и не повторяют побочные эффекты
+	if _, err := env.service.CompleteSession(session.ID, testUserID(1)); !errors.Is(err, entity.ErrInvalidTransition) {
+		t.Errorf("second CompleteSession error = %v, want invalid transition", err)
+	}
+	if err := env.service.CancelSession(session.ID, testUserID(1)); !errors.Is(err, entity.ErrInvalidTransition) {
+		t.Errorf("CancelSession after completion error = %v, want invalid transition", err)
+	}
+	if got := len(env.telegram.messages(101)); got != chatOffers {
+		t.Errorf("creator got %d discussion chat offers after retries, want %d", got, chatOffers)
+	}
+
+	stored := env.session(t, session.ID)
+	if stored.Status != entity.SessionStatusCompleted || stored.CompletedAt == nil {
+		t.Errorf("session status = %s, completedAt = %v, want completed with time", stored.Status, stored.CompletedAt)
+	}
+}
+
+func TestFinishSession(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(t *testing.T, env *testEnv) *entity.Session
+		cancel     bool
+		wantStatus entity.SessionStatus
+	}{
+		{
+			name:       "complete active",
+			prepare:    func(t *testing.T, env *testEnv) *entity.Session { return env.startedSession(t) },
+			wantStatus: entity.SessionStatusCompleted,
+		},
+		{
+			name: "complete paused",
+			prepare: func(t *testing.T, env *testEnv) *entity.Session {
+				session := env.startedSession(t)
+				if err := env.service.PauseSession(session.ID, testUserID(2)); err != nil {
+					t.Fatalf("PauseSession: %v", err)
+				}
+				return session
+			},
+			wantStatus: entity.SessionStatusCompleted,
+		},
+		{
+			name: "cancel pending",
+			prepare: func(t *testing.T, env *testEnv) *entity.Session {
+				return env.groupSession(t, entity.SessionSettings{}, testUserID(2))
+			},
+			cancel:     true,
+			wantStatus: entity.SessionStatusCancelled,
+		},
+		{
+			name: "cancel paused",
+			prepare: func(t *testing.T, env *testEnv) *entity.Session {
+				session := env.startedSession(t)
+				if err := env.service.PauseSession(session.ID, testUserID(1)); err != nil {
+					t.Fatalf("PauseSession: %v", err)
+				}
+				return session
+			},
+			cancel:     true,
+			wantStatus: entity.SessionStatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 2)
+			session := tt.prepare(t, env)
+
+			var err error
+			if tt.cancel {
+				err = env.service.CancelSession(session.ID, testUserID(1))
+			} else {
+				_, err = env.service.CompleteSession(session.ID, testUserID(1))
+			}
+			if err != nil {
+				t.Fatalf("finish session: %v", err)
+			}
+
+			stored := env.session(t, session.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if stored.PausedAt != nil || stored.CurrentPhase != "" || stored.PhaseEndsAt != nil {
+				t.Errorf("pausedAt = %v, phase = %q, phaseEndsAt = %v, want pause and phase cleared", stored.PausedAt, stored.CurrentPhase, stored.PhaseEndsAt)
+			}
+			if (stored.CompletedAt != nil) != (tt.wantStatus == entity.SessionStatusCompleted) {
+				t.Errorf("completedAt = %v for status %s", stored.CompletedAt, stored.Status)
+			}
+		})
+	}
+}
+
+// racingSessionRepository отдает очистке копии сессий и сразу запускает их, как параллельный старт
+type racingSessionRepository struct {
+	interfaces.SessionRepository
+}
+
+func (r racingSessionRepository) GetSessionsByStatus(status entity.SessionStatus) ([]*entity.Session, error) {
+	sessions, err := r.SessionRepository.GetSessionsByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make([]*entity.Session, 0, len(sessions))
+	for _, session := range sessions {
+		loaded := *session
+		copies = append(copies, &loaded)
+		if _, err := r.SessionRepository.UpdateStatus(session.ID, entity.SessionStatusPending, entity.SessionStatusActive); err != nil {
+			return nil, err
+		}
+	}
+	return copies, nil
+}
+
+func TestCleanupCancelsStaleSessions(t *testing.T) {
+	tests := []struct {
+		name       string
+		racing     bool
+		wantStatus entity.SessionStatus
+	}{
+		{name: "stale session is cancelled", wantStatus: entity.SessionStatusCancelled},
+		{name: "session started meanwhile is skipped", racing: true, wantStatus: entity.SessionStatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 1)
+			session := env.groupSession(t, entity.SessionSettings{})
+			env.session(t, session.ID).CreatedAt = time.Now().Add(-2 * time.Hour)
+
+			var repo interfaces.SessionRepository = env.sessions
+			if tt.racing {
+				repo = racingSessionRepository{env.sessions}
+			}
+			NewSessionCleanupService(repo, time.Minute, time.Hour).cleanup()
+
+			if status := env.session(t, session.ID).Status; status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// copyingSessionRepository, как и gorm, отдает копии сессий; beforeStart срабатывает между чтением и записью старта
+type copyingSessionRepository struct {
+	interfaces.SessionRepository
+	beforeStart func()
+}
+
+func (r copyingSessionRepository) GetByID(id string) (*entity.Session, error) {
+	session, err := r.SessionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := *session
+	loaded.Participants = append([]entity.Participant(nil), session.Participants...)
+	return &loaded, nil
+}
+
+func (r copyingSessionRepository) Start(session *entity.Session) (bool, error) {
+	if r.beforeStart != nil {
+		r.beforeStart()
+	}
+	return r.SessionRepository.Start(session)
+}
+
+func TestStartSessionKeepsConcurrentChanges(t *testing.T) {
+	env := newTestEnv(t, 3)
+	session := env.groupSession(t, entity.SessionSettings{}, testUserID(2))
+
+	// Пока сессия запускается, user3 входит в нее, а user2 отмечается готовым
+	env.service.sessionRepo = copyingSessionRepository{
+		SessionRepository: env.sessions,
+		beforeStart: func() {
+			if err := env.sessions.AddParticipant(session.ID, &entity.Participant{SessionID: session.ID, UserID: testUserID(3), JoinedAt: time.Now()}); err != nil {
+				t.Fatalf("AddParticipant: %v", err)
+			}
+			if err := env.sessions.UpdateParticipantReady(session.ID, testUserID(2), true); err != nil {
+				t.Fatalf("UpdateParticipantReady: %v", err)
+			}
+		},
+	}
+
+	if _, err := env.service.StartSession(session.ID, testUserID(1), true); err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	stored := env.session(t, session.ID)
+	if stored.Status != entity.SessionStatusActive || stored.StartedAt == nil {
+		t.Fatalf("status = %s, startedAt = %v, want active with start time", stored.Status, stored.StartedAt)
+	}
+	if stored.CurrentPhase != entity.SessionPhaseFocus || stored.CurrentCycle != 1 || stored.PhaseEndsAt == nil {
+		t.Errorf("phase = %q, cycle = %d, phaseEndsAt = %v, want first focus phase", stored.CurrentPhase, stored.CurrentCycle, stored.PhaseEndsAt)
+	}
+	if !isPresent(stored, testUserID(3)) {
+		t.Errorf("%s joined during the start and was lost", testUserID(3))
+	}
+	if participant := findParticipant(stored, testUserID(2)); participant == nil || !participant.IsReady {
+		t.Errorf("readiness of %s set during the start was lost", testUserID(2))
+	}
+
+	if _, err := env.service.StartSession(session.ID, testUserID(1), true); !errors.Is(err, entity.ErrInvalidTransition) {
+		t.Errorf("second StartSession error = %v, want invalid transition", err)
+	}
+}
+
+func TestAddTaskRequiresParticipation(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		leave   bool
+		wantErr error
+	}{
+		{name: "creator", userID: testUserID(1)},
+		{name: "participant", userID: testUserID(2)},
+		{name: "outsider", userID: testUserID(3), wantErr: entity.ErrForbidden},
+		{name: "participant who left", userID: testUserID(2), leave: true, wantErr: entity.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 3)
+			session := env.groupSession(t, entity.SessionSettings{}, testUserID(2))
+			if tt.leave {
+				if _, _, err := env.service.LeaveSession(session.ID, tt.userID); err != nil {
+					t.Fatalf("LeaveSession: %v", err)
+				}
+			}
+
+			task, err := env.service.AddTask(session.ID, tt.userID, "task")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("AddTask error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddTask: %v", err)
+			}
+			if task.UserID == nil || *task.UserID != tt.userID || task.SessionID != session.ID {
+				t.Errorf("task = %+v, want a task of %s in session %s", task, tt.userID, session.ID)
+			}
+		})
+	}
+}
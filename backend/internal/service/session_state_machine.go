@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// sessionActor — кто выполняет действие над сессией
+type sessionActor string
+
+const (
+	actorCreator     sessionActor = "creator"
+	actorParticipant sessionActor = "participant" // участник групповой сессии (не создатель)
+	actorOutsider    sessionActor = "outsider"    // не участник (в том числе вышедший)
+	actorSystem      sessionActor = "system"      // фоновые сервисы: очистка, планировщик
+)
+
+// sessionTransition описывает, из каких статусов и кем может быть выполнено действие.
+// Пустой to — действие не меняет статус сессии
+type sessionTransition struct {
+	from       []entity.SessionStatus
+	to         entity.SessionStatus
+	actors     []sessionActor
+	idempotent bool // повтор действия, когда сессия уже в статусе to, — не ошибка
+}
+
+var (
+	unfinishedStatuses = []entity.SessionStatus{
+		entity.SessionStatusPending,
+		entity.SessionStatusActive,
+		entity.SessionStatusPaused,
+	}
+	runningStatuses = []entity.SessionStatus{
+		entity.SessionStatusActive,
+		entity.SessionStatusPaused,
+	}
+)
+
+// sessionTransitions — все правила жизненного цикла сессии в одном месте
+var sessionTransitions = map[entity.SessionAction]sessionTransition{
+	entity.SessionActionStart: {
+		from:   []entity.SessionStatus{entity.SessionStatusPending},
+		to:     entity.SessionStatusActive,
+		actors: []sessionActor{actorCreator, actorSystem},
+	},
+	entity.SessionActionPause: {
+		from:       []entity.SessionStatus{entity.SessionStatusActive},
+		to:         entity.SessionStatusPaused,
+		actors:     []sessionActor{actorCreator, actorParticipant},
+		idempotent: true,
+	},
+	entity.SessionActionResume: {
+		from:       []entity.SessionStatus{entity.SessionStatusPaused},
+		to:         entity.SessionStatusActive,
+		actors:     []sessionActor{actorCreator, actorParticipant},
+		idempotent: true,
+	},
+	entity.SessionActionComplete: {
+		from:   runningStatuses,
+		to:     entity.SessionStatusCompleted,
+		actors: []sessionActor{actorCreator, actorSystem},
+	},
+	entity.SessionActionCancel: {
+		from:   unfinishedStatuses,
+		to:     entity.SessionStatusCancelled,
+		actors: []sessionActor{actorCreator, actorSystem},
+	},
+	entity.SessionActionJoin: {
+		from:   []entity.SessionStatus{entity.SessionStatusPending},
+		actors: []sessionActor{actorOutsider},
+	},
+	entity.SessionActionRejoin: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorOutsider},
+	},
+	entity.SessionActionLeave: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorCreator, actorParticipant},
+	},
+	entity.SessionActionRemoveParticipant: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorCreator},
+	},
+	entity.SessionActionTransferOwnership: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorCreator},
+	},
+	entity.SessionActionConfigureStart: {
+		from:   []entity.SessionStatus{entity.SessionStatusPending},
+		actors: []sessionActor{actorCreator},
+	},
+	entity.SessionActionManageCoHosts: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorCreator},
+	},
+	// Со-ведущий — это участник; право рассматривать заявки дополнительно проверяет isHost
+	entity.SessionActionReviewJoinRequest: {
+		from:   unfinishedStatuses,
+		actors: []sessionActor{actorCreator, actorParticipant},
+	},
+}
+
+// resolveActor определяет роль пользователя в сессии
+func resolveActor(session *entity.Session, userID string) sessionActor {
+	if session.CreatorID == userID {
+		return actorCreator
+	}
+	if session.Mode == entity.SessionModeGroup && isPresent(session, userID) {
+		return actorParticipant
+	}
+	return actorOutsider
+}
+
+// applyTransition проверяет, что actor может выполнить action в текущем статусе сессии,
+// и переводит сессию в целевой статус. changed == false, если сессия уже в целевом статусе
+// и действие идемпотентно — тогда сохранять нечего
+func applyTransition(session *entity.Session, action entity.SessionAction, actor sessionActor) (changed bool, err error) {
+	changed, err = checkTransition(session, action, actor)
+	if err != nil || !changed {
+		return changed, err
+	}
+
+	if to := sessionTransitions[action].to; to != "" {
+		session.Status = to
+	}
+	return true, nil
+}
+
+// checkTransition выполняет проверки applyTransition, не меняя сессию. Нужна, когда статус
+// сохраняется compare-and-set и сессию можно менять только после успешной записи
+func checkTransition(session *entity.Session, action entity.SessionAction, actor sessionActor) (changed bool, err error) {
+	transition, ok := sessionTransitions[action]
+	if !ok {
+		return false, fmt.Errorf("unknown session action: %s", action)
+	}
+
+	if !transition.allows(actor) {
+		return false, entity.Forbidden(transition.forbiddenReason(action))
+	}
+
+	if transition.idempotent && session.Status == transition.to {
+		return false, nil
+	}
+
+	if !transition.allowedFrom(session.Status) {
+		return false, &entity.TransitionError{Action: action, From: session.Status}
+	}
+
+	return true, nil
+}
+
+func (t sessionTransition) allows(actor sessionActor) bool {
+	for _, allowed := range t.actors {
+		if allowed == actor {
+			return true
+		}
+	}
+	return false
+}
+
+func (t sessionTransition) allowedFrom(status entity.SessionStatus) bool {
+	for _, from := range t.from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (t sessionTransition) forbiddenReason(action entity.SessionAction) string {
+	for _, actor := range t.actors {
+		if actor == actorParticipant || actor == actorOutsider {
+			return fmt.Sprintf("user not authorized to %s", action.Description())
+		}
+	}
+	return fmt.Sprintf("only creator can %s", action.Description())
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func TestApplyTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      entity.SessionStatus
+		action      entity.SessionAction
+		actor       sessionActor
+		wantStatus  entity.SessionStatus
+		wantChanged bool
+		wantErr     error
+	}{
+		{name: "creator starts pending", status: entity.SessionStatusPending, action: entity.SessionActionStart, actor: actorCreator, wantStatus: entity.SessionStatusActive, wantChanged: true},
+		{name: "system starts pending", status: entity.SessionStatusPending, action: entity.SessionActionStart, actor: actorSystem, wantStatus: entity.SessionStatusActive, wantChanged: true},
+		{name: "participant cannot start", status: entity.SessionStatusPending, action: entity.SessionActionStart, actor: actorParticipant, wantStatus: entity.SessionStatusPending, wantErr: entity.ErrForbidden},
+		{name: "start twice", status: entity.SessionStatusActive, action: entity.SessionActionStart, actor: actorCreator, wantStatus: entity.SessionStatusActive, wantErr: entity.ErrInvalidTransition},
+		{name: "participant pauses", status: entity.SessionStatusActive, action: entity.SessionActionPause, actor: actorParticipant, wantStatus: entity.SessionStatusPaused, wantChanged: true},
+		{name: "pause is idempotent", status: entity.SessionStatusPaused, action: entity.SessionActionPause, actor: actorCreator, wantStatus: entity.SessionStatusPaused},
+		{name: "pause pending", status: entity.SessionStatusPending, action: entity.SessionActionPause, actor: actorCreator, wantStatus: entity.SessionStatusPending, wantErr: entity.ErrInvalidTransition},
+		{name: "resume paused", status: entity.SessionStatusPaused, action: entity.SessionActionResume, actor: actorCreator, wantStatus: entity.SessionStatusActive, wantChanged: true},
+		{name: "resume is idempotent", status: entity.SessionStatusActive, action: entity.SessionActionResume, actor: actorParticipant, wantStatus: entity.SessionStatusActive},
+		{name: "outsider cannot pause", status: entity.SessionStatusActive, action: entity.SessionActionPause, actor: actorOutsider, wantStatus: entity.SessionStatusActive, wantErr: entity.ErrForbidden},
+		{name: "complete paused", status: entity.SessionStatusPaused, action: entity.SessionActionComplete, actor: actorSystem, wantStatus: entity.SessionStatusCompleted, wantChanged: true},
+		{name: "complete pending", status: entity.SessionStatusPending, action: entity.SessionActionComplete, actor: actorCreator, wantStatus: entity.SessionStatusPending, wantErr: entity.ErrInvalidTransition},
+		{name: "cancel pending", status: entity.SessionStatusPending, action: entity.SessionActionCancel, actor: actorCreator, wantStatus: entity.SessionStatusCancelled, wantChanged: true},
+		{name: "cancel completed", status: entity.SessionStatusCompleted, action: entity.SessionActionCancel, actor: actorSystem, wantStatus: entity.SessionStatusCompleted, wantErr: entity.ErrInvalidTransition},
+		{name: "join keeps status", status: entity.SessionStatusPending, action: entity.SessionActionJoin, actor: actorOutsider, wantStatus: entity.SessionStatusPending, wantChanged: true},
+		{name: "join after start", status: entity.SessionStatusActive, action: entity.SessionActionJoin, actor: actorOutsider, wantStatus: entity.SessionStatusActive, wantErr: entity.ErrInvalidTransition},
+		{name: "rejoin after start", status: entity.SessionStatusActive, action: entity.SessionActionRejoin, actor: actorOutsider, wantStatus: entity.SessionStatusActive, wantChanged: true},
+		{name: "configure start after start", status: entity.SessionStatusActive, action: entity.SessionActionConfigureStart, actor: actorCreator, wantStatus: entity.SessionStatusActive, wantErr: entity.ErrInvalidTransition},
+		{name: "participant cannot remove", status: entity.SessionStatusActive, action: entity.SessionActionRemoveParticipant, actor: actorParticipant, wantStatus: entity.SessionStatusActive, wantErr: entity.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &entity.Session{Status: tt.status}
+
+			changed, err := applyTransition(session, tt.action, tt.actor)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applyTransition() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("applyTransition() error: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("applyTransition() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if session.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", session.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCheckTransitionKeepsStatus(t *testing.T) {
+	session := &entity.Session{Status: entity.SessionStatusPending}
+
+	changed, err := checkTransition(session, entity.SessionActionStart, actorCreator)
+	if err != nil || !changed {
+		t.Fatalf("checkTransition() = %v, %v; want true, nil", changed, err)
+	}
+	if session.Status != entity.SessionStatusPending {
+		t.Errorf("status = %s, want %s", session.Status, entity.SessionStatusPending)
+	}
+}
+
+func TestUnknownAction(t *testing.T) {
+	session := &entity.Session{Status: entity.SessionStatusActive}
+
+	if _, err := applyTransition(session, entity.SessionAction("dance"), actorCreator); err == nil {
+		t.Fatal("applyTransition() with unknown action succeeded")
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// sessionResults возвращает итоги сессии по участникам. Участник без фокуса и выполненных задач в статистику не попадает
+func sessionResults(report *entity.SessionReport) []*entity.SessionResult {
+	results := make([]*entity.SessionResult, 0, len(report.Participants))
+	for _, participant := range report.Participants {
+		if participant.FocusTime <= 0 && participant.TasksCompleted <= 0 {
+			continue
+		}
+		results = append(results, &entity.SessionResult{
+			SessionID:      report.SessionID,
+			UserID:         participant.UserID,
+			TasksCompleted: participant.TasksCompleted,
+			TasksTotal:     participant.TasksTotal,
+			FocusTime:      participant.FocusTime,
+			CompletedAt:    report.CompletedAt,
+		})
+	}
+	return results
+}
+
+// recordSessionStats учитывает итоги завершенной сессии в статистике участников и выдает заработанные значки.
+// Итог по паре сессия/участник записывается один раз, поэтому повторное завершение ничего не меняет.
+// Возвращает значки, полученные за эту сессию
+func (s *SessionService) recordSessionStats(session *entity.Session, report *entity.SessionReport) ([]*entity.UserAchievement, error) {
+	var unlocked []*entity.UserAchievement
+	recorded := 0
+	for _, result := range sessionResults(report) {
+		ok, err := s.userRepo.RecordSessionResult(result, s.userLocation(result.UserID))
+		if err != nil {
+			return unlocked, fmt.Errorf("failed to record stats of user %s: %w", result.UserID, err)
+		}
+		if !ok {
+			continue
+		}
+		recorded++
+
+		stats, err := s.userRepo.GetStats(result.UserID)
+		if err != nil {
+			return unlocked, fmt.Errorf("failed to get stats of user %s: %w", result.UserID, err)
+		}
+		if stats == nil {
+			continue
+		}
+
+		achievements, err := s.unlockAchievements(session, result, stats)
+		if err != nil {
+			return unlocked, fmt.Errorf("failed to unlock achievements of user %s: %w", result.UserID, err)
+		}
+		unlocked = append(unlocked, achievements...)
+	}
+
+	if recorded > 0 {
+		log.Printf("[SessionService] 📈 Stats of session %s recorded for %d participant(s)\n", report.SessionID, recorded)
+	}
+	return unlocked, nil
+}
+
+// recordSessionContacts делает участников завершенной сессии контактами друг друга. Исключенные участники не учитываются
+func (s *SessionService) recordSessionContacts(session *entity.Session, at time.Time) error {
+	userIDs := make([]string, 0, len(session.Participants))
+	for _, participant := range session.Participants {
+		if participant.BannedAt != nil {
+			continue
+		}
+		userIDs = append(userIDs, participant.UserID)
+	}
+	if len(userIDs) < 2 {
+		return nil
+	}
+
+	return s.contactRepo.AddSessionContacts(userIDs, at)
+}
+
+// RecomputeStats пересчитывает статистику всех пользователей по истории завершенных сессий и восстанавливает контакты из них.
+// Итоги сессий дописываются в журнал и пересчитываются в одной транзакции. Журнал не ссылается на sessions внешним ключом,
+// поэтому итоги удаленных сессий остаются в нем и тоже учитываются.
+// Недостающие значки выдаются, уже полученные сохраняются. Возвращает число учтенных сессий
+func (s *SessionService) RecomputeStats() (int, error) {
+	sessions, err := s.sessionRepo.GetSessionsByStatus(entity.SessionStatusCompleted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get completed sessions: %w", err)
+	}
+
+	// Серии дней зависят от порядка, поэтому сессии учитываются в хронологическом порядке
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessionCompletedAt(sessions[i]).Before(sessionCompletedAt(sessions[j]))
+	})
+
+	var results []*entity.SessionResult
+	for _, session := range sessions {
+		tasks, err := s.taskRepo.GetBySessionID(session.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get tasks of session %s: %w", session.ID, err)
+		}
+
+		segments, err := s.phaseSegmentRepo.GetBySessionID(session.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get phase segments of session %s: %w", session.ID, err)
+		}
+
+		attendance, err := s.attendanceRepo.GetBySessionID(session.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get attendance of session %s: %w", session.ID, err)
+		}
+
+		report := s.buildSessionReport(session, tasks, segments, attendance, sessionCompletedAt(session))
+		results = append(results, sessionResults(report)...)
+	}
+
+	if err := s.userRepo.RebuildStats(results); err != nil {
+		return 0, fmt.Errorf("failed to rebuild stats: %w", err)
+	}
+
+	// Значки за прошлые сессии выдаются без уведомлений: статистика каждого участника
+	// восстанавливается сессия за сессией, как если бы они завершались сейчас
+	sessionsByID := make(map[string]*entity.Session, len(sessions))
+	for _, session := range sessions {
+		sessionsByID[session.ID] = session
+	}
+	stats := make(map[string]*entity.UserStats)
+	locations := make(map[string]*time.Location)
+	for _, result := range results {
+		userStats, exists := stats[result.UserID]
+		if !exists {
+			userStats = &entity.UserStats{UserID: result.UserID}
+			stats[result.UserID] = userStats
+			locations[result.UserID] = s.userLocation(result.UserID)
+		}
+		userStats.AddSession(result, locations[result.UserID])
+
+		if _, err := s.unlockAchievements(sessionsByID[result.SessionID], result, userStats); err != nil {
+			return 0, fmt.Errorf("failed to unlock achievements of user %s: %w", result.UserID, err)
+		}
+	}
+
+	// Контакты из сессий, завершенных до их появления
+	for _, session := range sessions {
+		if err := s.recordSessionContacts(session, sessionCompletedAt(session)); err != nil {
+			return 0, fmt.Errorf("failed to record contacts of session %s: %w", session.ID, err)
+		}
+	}
+
+	return len(sessions), nil
+}
+
+// sessionCompletedAt возвращает время завершения сессии; для старых сессий без него — время последнего изменения
+func sessionCompletedAt(session *entity.Session) time.Time {
+	if session.CompletedAt != nil {
+		return *session.CompletedAt
+	}
+	return session.UpdatedAt
+}
+
+// userLocation возвращает часовой пояс пользователя; для неизвестного пользователя — UTC
+func (s *SessionService) userLocation(userID string) *time.Location {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user == nil {
+		return time.UTC
+	}
+	return user.Location()
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestRecomputeStatsKeepsDeletedSessions(t *testing.T) {
+	env := newTestEnv(t, 2)
+
+	var sessionIDs []string
+	for i := 0; i < 2; i++ {
+		session := env.startedSession(t)
+		// Итог без фокуса и выполненных задач в журнал не попадает
+		for _, userID := range []string{testUserID(1), testUserID(2)} {
+			task, err := env.service.AddTask(session.ID, userID, "task")
+			if err != nil {
+				t.Fatalf("AddTask: %v", err)
+			}
+			if _, err := env.service.UpdateTask(session.ID, task.ID, userID, true); err != nil {
+				t.Fatalf("UpdateTask: %v", err)
+			}
+		}
+		if _, err := env.service.CompleteSession(session.ID, testUserID(1)); err != nil {
+			t.Fatalf("CompleteSession: %v", err)
+		}
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	// Удаленная сессия пропадает из истории, но ее итоги остаются в журнале
+	if err := env.service.DeleteSession(sessionIDs[0], testUserID(1)); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+
+	counted, err := env.service.RecomputeStats()
+	if err != nil {
+		t.Fatalf("RecomputeStats: %v", err)
+	}
+	if counted != 1 {
+		t.Errorf("RecomputeStats counted %d sessions, want 1", counted)
+	}
+
+	for _, userID := range []string{testUserID(1), testUserID(2)} {
+		stats, err := env.users.GetStats(userID)
+		if err != nil {
+			t.Fatalf("GetStats(%s): %v", userID, err)
+		}
+		if stats.TotalSessions != 2 {
+			t.Errorf("%s has %d sessions after recompute, want 2", userID, stats.TotalSessions)
+		}
+	}
+}
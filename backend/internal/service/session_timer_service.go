@@ -0,0 +1,351 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// SessionTimerService is the server-side phase engine: it walks every active
+// session through its cycle plan, completes sessions whose plan is over and
+// notifies clients about both. It also runs the readiness countdown of pending
+// sessions with an auto-start policy, so that the countdown ends on time
+type SessionTimerService struct {
+	sessionRepo      interfaces.SessionRepository
+	phaseSegmentRepo interfaces.PhaseSegmentRepository
+	sessionService   interfaces.SessionService
+	notifier         interfaces.SessionNotifier
+	interval         time.Duration
+}
+
+// NewSessionTimerService creates a new phase engine
+func NewSessionTimerService(
+	sessionRepo interfaces.SessionRepository,
+	phaseSegmentRepo interfaces.PhaseSegmentRepository,
+	sessionService interfaces.SessionService,
+	notifier interfaces.SessionNotifier,
+	interval time.Duration,
+) *SessionTimerService {
+	return &SessionTimerService{
+		sessionRepo:      sessionRepo,
+		phaseSegmentRepo: phaseSegmentRepo,
+		sessionService:   sessionService,
+		notifier:         notifier,
+		interval:         interval,
+	}
+}
+
+// Start begins the phase engine routine
+func (s *SessionTimerService) Start() {
+	log.Printf("[SessionTimer] ⏱️ Starting phase engine (interval: %v)\n", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	go func() {
+		for range ticker.C {
+			s.tick()
+		}
+	}()
+}
+
+// tick advances every active session whose phase deadline has passed
+// and every auto-start countdown
+func (s *SessionTimerService) tick() {
+	now := time.Now()
+
+	sessions, err := s.sessionRepo.GetSessionsWithPhaseEndingBefore(now)
+	if err != nil {
+		log.Printf("[SessionTimer] ❌ Failed to get sessions with expired phase: %v\n", err)
+	} else {
+		for _, session := range sessions {
+			s.advance(session, now)
+		}
+	}
+
+	pending, err := s.sessionRepo.GetPendingWithStartPolicy()
+	if err != nil {
+		log.Printf("[SessionTimer] ❌ Failed to get sessions with start policy: %v\n", err)
+		return
+	}
+
+	for _, session := range pending {
+		s.evaluateStartPolicy(session, now)
+	}
+}
+
+// evaluateStartPolicy starts the countdown once the policy is met, cancels it when
+// someone drops their readiness and starts the session when the countdown runs out.
+// The countdown lives only in AutoStartAt, so every instance sees the same deadline
+func (s *SessionTimerService) evaluateStartPolicy(session *entity.Session, now time.Time) {
+	met := startPolicyMet(session)
+
+	switch {
+	case met && session.AutoStartAt == nil:
+		startsAt := now.Add(session.CountdownDuration())
+		claimed, err := s.sessionRepo.UpdateAutoStartAt(session.ID, nil, &startsAt)
+		if err != nil {
+			log.Printf("[SessionTimer] ❌ Failed to start countdown for session %s: %v\n", session.ID, err)
+			return
+		}
+		if !claimed || s.notifier == nil {
+			return
+		}
+
+		present, ready := readiness(session)
+		s.notifier.SendToSession(session.ID, "start_countdown", map[string]interface{}{
+			"sessionId":  session.ID,
+			"startsAt":   startsAt.Format(time.RFC3339),
+			"countdown":  countdownSeconds(startsAt, now),
+			"readyCount": ready,
+			"present":    present,
+		})
+
+	case !met && session.AutoStartAt != nil:
+		cancelled, err := s.sessionRepo.UpdateAutoStartAt(session.ID, session.AutoStartAt, nil)
+		if err != nil {
+			log.Printf("[SessionTimer] ❌ Failed to cancel countdown for session %s: %v\n", session.ID, err)
+			return
+		}
+		if cancelled && s.notifier != nil {
+			s.notifier.SendToSession(session.ID, "start_countdown_cancelled", map[string]interface{}{
+				"sessionId": session.ID,
+				"reason":    "not_ready",
+			})
+		}
+
+	case met && !now.Before(*session.AutoStartAt):
+		s.finishCountdown(session, now)
+	}
+}
+
+// finishCountdown starts the session whose countdown ran out and tells
+// participants who were not ready that the session started without them
+func (s *SessionTimerService) finishCountdown(session *entity.Session, now time.Time) {
+	late := notReadyParticipants(session, "")
+
+	started, err := s.sessionService.AutoStartSession(session.ID)
+	if err != nil {
+		// The creator or another instance started the session first
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return
+		}
+		log.Printf("[SessionTimer] ❌ Failed to auto-start session %s: %v\n", session.ID, err)
+		return
+	}
+
+	log.Printf("[SessionTimer] ▶️ Started session %s after countdown\n", started.ID)
+
+	if s.notifier == nil {
+		return
+	}
+
+	s.notifier.SendToSession(started.ID, "session_started", map[string]interface{}{
+		"sessionId": started.ID,
+		"phase":     phaseEventData(started, now),
+		"trigger":   "countdown",
+	})
+	for _, userID := range late {
+		s.notifier.SendToUser(userID, "missed_start", map[string]interface{}{
+			"sessionId": started.ID,
+			"startedAt": started.StartedAt.Format(time.RFC3339),
+		})
+	}
+}
+
+// countdownSeconds returns the whole seconds left until the auto-start deadline
+func countdownSeconds(startsAt time.Time, now time.Time) int {
+	left := int(math.Ceil(startsAt.Sub(now).Seconds()))
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+func (s *SessionTimerService) advance(session *entity.Session, now time.Time) {
+	previousPhaseEndsAt := *session.PhaseEndsAt
+
+	// Work on a copy so the repository can compare against the stored deadline
+	next := *session
+	// Catch up on every phase missed while the server was down; phases are
+	// chained from the previous deadline, not from now, so clients don't drift
+	var skipped []*entity.SessionPhaseSegment
+	var planEndedAt *time.Time
+	for next.PhaseEndsAt != nil && !now.Before(*next.PhaseEndsAt) {
+		endedAt := *next.PhaseEndsAt
+		if next.PhaseStartedAt != nil && !endedAt.Equal(previousPhaseEndsAt) {
+			skipped = append(skipped, newPhaseSegment(&next, *next.PhaseStartedAt, &endedAt))
+		}
+		if !advancePhase(&next) {
+			planEndedAt = &endedAt
+			break
+		}
+	}
+
+	if planEndedAt != nil {
+		s.finish(&next, *planEndedAt, skipped)
+		return
+	}
+
+	updated, err := s.sessionRepo.UpdatePhase(&next, previousPhaseEndsAt)
+	if err != nil {
+		log.Printf("[SessionTimer] ❌ Failed to advance phase for session %s: %v\n", session.ID, err)
+		return
+	}
+	if !updated {
+		// Session was paused, completed or advanced elsewhere in the meantime
+		return
+	}
+
+	log.Printf("[SessionTimer] 🔁 Session %s: phase=%s cycle=%d\n", next.ID, next.CurrentPhase, next.CurrentCycle)
+
+	s.recordSegments(&next, previousPhaseEndsAt, skipped)
+
+	if s.notifier != nil {
+		s.notifier.SendToSession(next.ID, "phase_changed", phaseEventData(&next, now))
+	}
+}
+
+// finish completes a session whose cycle plan is over. The phase is cleared by
+// the same status compare-and-set that completes the session, so only one
+// instance completes it, and a failed completion leaves the expired phase in
+// place for the next tick to retry
+func (s *SessionTimerService) finish(session *entity.Session, endedAt time.Time, skipped []*entity.SessionPhaseSegment) {
+	report, err := s.sessionService.AutoCompleteSession(session.ID, endedAt, skipped)
+	if err != nil {
+		// Paused, completed or cancelled elsewhere in the meantime
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return
+		}
+		log.Printf("[SessionTimer] ❌ Failed to complete session %s after its plan: %v\n", session.ID, err)
+		return
+	}
+
+	log.Printf("[SessionTimer] 🏁 Session %s completed its plan: %d cycles\n", session.ID, session.CurrentCycle)
+
+	if s.notifier != nil {
+		s.notifier.SendToSession(session.ID, "session_completed", map[string]interface{}{
+			"sessionId": session.ID,
+			"status":    entity.SessionStatusCompleted,
+			"trigger":   "plan_finished",
+			"report":    report,
+		})
+
+		for _, achievement := range report.Achievements {
+			s.notifier.SendToUser(achievement.UserID, "achievement_unlocked", map[string]interface{}{
+				"code":        achievement.Code,
+				"title":       achievement.Code.Title(),
+				"description": achievement.Code.Description(),
+				"sessionId":   achievement.SessionID,
+				"unlockedAt":  achievement.UnlockedAt.Format(time.RFC3339),
+			})
+		}
+	}
+}
+
+// recordSegments closes the finished phase, stores phases that elapsed during
+// catch-up and opens a segment for the new current phase
+func (s *SessionTimerService) recordSegments(session *entity.Session, previousPhaseEndsAt time.Time, skipped []*entity.SessionPhaseSegment) {
+	if err := s.phaseSegmentRepo.CloseOpen(session.ID, previousPhaseEndsAt); err != nil {
+		log.Printf("[SessionTimer] ❌ Failed to close phase segment for session %s: %v\n", session.ID, err)
+	}
+
+	segments := append(skipped, newPhaseSegment(session, *session.PhaseStartedAt, nil))
+	for _, segment := range segments {
+		if err := s.phaseSegmentRepo.Create(segment); err != nil {
+			log.Printf("[SessionTimer] ❌ Failed to save phase segment for session %s: %v\n", session.ID, err)
+		}
+	}
+}
+
+// newPhaseSegment создаёт отрезок текущей фазы сессии; endedAt == nil — отрезок ещё идёт
+func newPhaseSegment(session *entity.Session, startedAt time.Time, endedAt *time.Time) *entity.SessionPhaseSegment {
+	return &entity.SessionPhaseSegment{
+		ID:        uuid.New().String(),
+		SessionID: session.ID,
+		Phase:     session.CurrentPhase,
+		Cycle:     session.CurrentCycle,
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+	}
+}
+
+// phaseDurations суммирует фактическое время фокуса и перерывов по отрезкам фаз (паузы в отрезки не входят).
+// Незакрытый отрезок считается до until
+func phaseDurations(segments []*entity.SessionPhaseSegment, until time.Time) (focus time.Duration, breaks time.Duration) {
+	for _, segment := range segments {
+		endedAt := until
+		if segment.EndedAt != nil {
+			endedAt = *segment.EndedAt
+		}
+
+		duration := endedAt.Sub(segment.StartedAt)
+		if duration <= 0 {
+			continue
+		}
+
+		if segment.Phase == entity.SessionPhaseFocus {
+			focus += duration
+		} else {
+			breaks += duration
+		}
+	}
+	return focus, breaks
+}
+
+// startPhase переводит сессию на фазу плана с номером index, отсчитывая её длительность от at.
+// false — план исчерпан, сессия остается в прежней фазе
+func startPhase(session *entity.Session, index int, at time.Time) bool {
+	planned, ok := session.PlannedPhaseAt(index)
+	if !ok {
+		return false
+	}
+
+	startedAt := at
+	endsAt := at.Add(time.Duration(planned.Duration) * time.Minute)
+
+	session.PhaseIndex = index
+	session.CurrentPhase = planned.Phase
+	session.PhaseStartedAt = &startedAt
+	session.PhaseEndsAt = &endsAt
+	return true
+}
+
+// advancePhase переключает сессию на следующую фазу плана; каждый фокус после первого начинает новый цикл.
+// false — план исчерпан
+func advancePhase(session *entity.Session) bool {
+	if !startPhase(session, session.PhaseIndex+1, *session.PhaseEndsAt) {
+		return false
+	}
+	if session.CurrentPhase == entity.SessionPhaseFocus {
+		session.CurrentCycle++
+	}
+	return true
+}
+
+// phaseEventData формирует payload события phase_changed
+func phaseEventData(session *entity.Session, now time.Time) map[string]interface{} {
+	data := map[string]interface{}{
+		"sessionId":    session.ID,
+		"phase":        session.CurrentPhase,
+		"currentCycle": session.CurrentCycle,
+		"phaseIndex":   session.PhaseIndex,
+		"serverTime":   now.Format(time.RFC3339),
+	}
+	if cycles := session.PlannedCycles(); cycles > 0 {
+		data["totalCycles"] = cycles
+	}
+	if next, ok := session.PlannedPhaseAt(session.PhaseIndex + 1); ok {
+		data["nextPhase"] = next
+	}
+	if session.PhaseStartedAt != nil {
+		data["phaseStartedAt"] = session.PhaseStartedAt.Format(time.RFC3339)
+	}
+	if session.PhaseEndsAt != nil {
+		data["phaseEndsAt"] = session.PhaseEndsAt.Format(time.RFC3339)
+	}
+	return data
+}
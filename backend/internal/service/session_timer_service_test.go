@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// failingCompletion fails the first AutoCompleteSession calls, as a lost database connection would
+type failingCompletion struct {
+	interfaces.SessionService
+	failures int
+}
+
+func (s *failingCompletion) AutoCompleteSession(sessionID string, at time.Time, elapsed []*entity.SessionPhaseSegment) (*entity.SessionReport, error) {
+	if s.failures > 0 {
+		s.failures--
+		return nil, errors.New("connection lost")
+	}
+	return s.SessionService.AutoCompleteSession(sessionID, at, elapsed)
+}
+
+func TestTimerCompletesFinishedPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		ticks    int
+	}{
+		{name: "completes on the first tick", ticks: 1},
+		{name: "retries a failed completion", failures: 1, ticks: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, 2)
+			session := env.groupSession(t, entity.SessionSettings{Plan: entity.CyclePlan{Cycles: 1}}, testUserID(2))
+			if _, err := env.service.StartSession(session.ID, testUserID(1), true); err != nil {
+				t.Fatalf("StartSession: %v", err)
+			}
+
+			// The server missed the end of focus and the whole break of the only cycle
+			focusEndedAt := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+			stored := env.session(t, session.ID)
+			phaseStartedAt := focusEndedAt.Add(-25 * time.Minute)
+			stored.StartedAt = &phaseStartedAt
+			stored.PhaseStartedAt = &phaseStartedAt
+			stored.PhaseEndsAt = &focusEndedAt
+			planEndedAt := focusEndedAt.Add(5 * time.Minute)
+			open, err := env.segments.GetBySessionID(session.ID)
+			if err != nil || len(open) != 1 {
+				t.Fatalf("GetBySessionID = %d segments, %v, want the open focus segment", len(open), err)
+			}
+			open[0].StartedAt = phaseStartedAt
+
+			notifier := &testNotifier{}
+			timer := NewSessionTimerService(env.sessions, env.segments, &failingCompletion{SessionService: env.service, failures: tt.failures}, notifier, time.Second)
+
+			for i := 1; i <= tt.ticks; i++ {
+				timer.tick()
+
+				stored = env.session(t, session.ID)
+				if i < tt.ticks && (stored.Status != entity.SessionStatusActive || stored.PhaseEndsAt == nil) {
+					t.Fatalf("after failed tick %d: status = %s, phaseEndsAt = %v, want active with the expired phase", i, stored.Status, stored.PhaseEndsAt)
+				}
+			}
+
+			if stored.Status != entity.SessionStatusCompleted || stored.CurrentPhase != "" || stored.PhaseEndsAt != nil {
+				t.Fatalf("status = %s, phase = %q, phaseEndsAt = %v, want completed without phase", stored.Status, stored.CurrentPhase, stored.PhaseEndsAt)
+			}
+			if stored.CompletedAt == nil || !stored.CompletedAt.Equal(planEndedAt) {
+				t.Errorf("completedAt = %v, want the end of the plan %v", stored.CompletedAt, planEndedAt)
+			}
+			if count := notifier.count(session.ID, "session_completed"); count != 1 {
+				t.Errorf("session_completed sent %d times, want 1", count)
+			}
+
+			segments, err := env.segments.GetBySessionID(session.ID)
+			if err != nil {
+				t.Fatalf("GetBySessionID: %v", err)
+			}
+			if len(segments) != 2 {
+				t.Fatalf("got %d phase segments, want focus and break", len(segments))
+			}
+			focus, rest := segments[0], segments[1]
+			if focus.Phase != entity.SessionPhaseFocus || focus.EndedAt == nil || !focus.EndedAt.Equal(focusEndedAt) {
+				t.Errorf("focus segment ends at %v, want %v", focus.EndedAt, focusEndedAt)
+			}
+			if rest.Phase != entity.SessionPhaseBreak || !rest.StartedAt.Equal(focusEndedAt) || rest.EndedAt == nil || !rest.EndedAt.Equal(planEndedAt) {
+				t.Errorf("break segment = %v..%v, want %v..%v", rest.StartedAt, rest.EndedAt, focusEndedAt, planEndedAt)
+			}
+
+			// The next tick no longer sees the session
+			timer.tick()
+			if count := notifier.count(session.ID, "session_completed"); count != 1 {
+				t.Errorf("session_completed sent %d times after another tick, want 1", count)
+			}
+		})
+	}
+}
+
+func TestReadinessCountdown(t *testing.T) {
+	env := newTestEnv(t, 3)
+	countdown := 30
+	session := env.groupSession(t, entity.SessionSettings{StartPolicy: entity.StartPolicyAllReady, StartCountdown: &countdown}, testUserID(2))
+
+	notifier := &testNotifier{}
+	timer := NewSessionTimerService(env.sessions, env.segments, env.service, notifier, time.Second)
+
+	setReady := func(userID string, isReady bool) {
+		t.Helper()
+		if err := env.service.SetReady(session.ID, userID, isReady); err != nil {
+			t.Fatalf("SetReady(%s, %v): %v", userID, isReady, err)
+		}
+	}
+
+	// Outsiders can't mark readiness
+	if err := env.service.SetReady(session.ID, testUserID(3), true); !errors.Is(err, entity.ErrForbidden) {
+		t.Fatalf("SetReady by outsider error = %v, want forbidden", err)
+	}
+
+	setReady(testUserID(1), true)
+	timer.tick()
+	if env.session(t, session.ID).AutoStartAt != nil {
+		t.Fatal("countdown started before everyone was ready")
+	}
+
+	setReady(testUserID(2), true)
+	timer.tick()
+	timer.tick()
+	startsAt := env.session(t, session.ID).AutoStartAt
+	if startsAt == nil {
+		t.Fatal("countdown did not start when everyone was ready")
+	}
+	if count := notifier.count(session.ID, "start_countdown"); count != 1 {
+		t.Errorf("start_countdown sent %d times, want 1", count)
+	}
+
+	// Readiness withdrawn: the countdown is cancelled
+	setReady(testUserID(2), false)
+	timer.tick()
+	if env.session(t, session.ID).AutoStartAt != nil {
+		t.Fatal("countdown was not cancelled when a participant was no longer ready")
+	}
+	if count := notifier.count(session.ID, "start_countdown_cancelled"); count != 1 {
+		t.Errorf("start_countdown_cancelled sent %d times, want 1", count)
+	}
+
+	// Ready again and the countdown runs out
+	setReady(testUserID(2), true)
+	timer.tick()
+	past := time.Now().Add(-time.Second)
+	env.session(t, session.ID).AutoStartAt = &past
+	timer.tick()
+
+	stored := env.session(t, session.ID)
+	if stored.Status != entity.SessionStatusActive || stored.AutoStartAt != nil {
+		t.Fatalf("status = %s, autoStartAt = %v, want active without countdown", stored.Status, stored.AutoStartAt)
+	}
+	if count := notifier.count(session.ID, "session_started"); count != 1 {
+		t.Errorf("session_started sent %d times, want 1", count)
+	}
+}
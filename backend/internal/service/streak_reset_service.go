@@ -0,0 +1,64 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
+)
+
+// StreakResetService resets day streaks that lapsed at the user's local midnight
+type StreakResetService struct {
+	userRepo interfaces.UserRepository
+	interval time.Duration
+}
+
+// NewStreakResetService creates a new streak reset service
+func NewStreakResetService(userRepo interfaces.UserRepository, interval time.Duration) *StreakResetService {
+	return &StreakResetService{
+		userRepo: userRepo,
+		interval: interval,
+	}
+}
+
+// Start begins the reset routine. Midnight comes at different moments in different timezones,
+// so the check runs on a short interval rather than once a day
+func (s *StreakResetService) Start() {
+	log.Printf("[StreakReset] 🔥 Starting streak reset service (interval: %v)\n", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	go func() {
+		s.reset()
+		for range ticker.C {
+			s.reset()
+		}
+	}()
+}
+
+// reset zeroes streaks of users whose last session was before yesterday in their own timezone
+func (s *StreakResetService) reset() {
+	timezones, err := s.userRepo.GetStreakTimezones()
+	if err != nil {
+		log.Printf("[StreakReset] ❌ Failed to get streak timezones: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, timezone := range timezones {
+		loc, err := entity.LoadTimezone(timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		yesterday := entity.LocalDay(now, loc).AddDate(0, 0, -1)
+		reset, err := s.userRepo.ResetLapsedStreaks(timezone, yesterday)
+		if err != nil {
+			log.Printf("[StreakReset] ❌ Failed to reset streaks in timezone %q: %v\n", timezone, err)
+			continue
+		}
+		if reset > 0 {
+			log.Printf("[StreakReset] 🧊 Reset %d lapsed streak(s) in timezone %q\n", reset, timezone)
+		}
+	}
+}
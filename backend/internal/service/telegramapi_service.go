@@ -45,6 +45,10 @@ func (s *TelegramAPIService) SendMessageToUser(userID int64, message *telegramap
 	return s.client.SendMessageToUser(userID, message)
 }
 
+func (s *TelegramAPIService) AnswerCallback(callbackID string, text string) error {
+	return s.client.AnswerCallback(callbackID, text)
+}
+
 func (s *TelegramAPIService) GetChat(chatID int64) (*telegramapi.Chat, error) {
 	return s.client.GetChat(chatID)
 }
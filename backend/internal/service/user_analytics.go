@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+const (
+	maxAnalyticsDays      = 366
+	defaultAnalyticsDays  = 7 // по дням — последние семь дней
+	defaultAnalyticsWeeks = 4 // по неделям — текущая и три предыдущие недели
+)
+
+// GetAnalytics возвращает личную аналитику фокуса по итогам сессий за период и сравнение с предыдущим периодом той же длины.
+// Дни и часы считаются в часовом поясе пользователя
+func (s *UserService) GetAnalytics(userID string, query entity.AnalyticsQuery) (*entity.FocusAnalytics, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+	loc := user.Location()
+
+	from, to, err := analyticsPeriod(query, entity.LocalDay(time.Now(), loc))
+	if err != nil {
+		return nil, err
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+
+	// Границы периода — полночь первого и следующего за последним дня по поясу пользователя
+	fromAt := localMidnight(from, loc)
+	toAt := localMidnight(to.AddDate(0, 0, 1), loc)
+	previousAt := localMidnight(from.AddDate(0, 0, -days), loc)
+
+	results, err := s.userRepo.GetSessionResults(userID, previousAt, toAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session results: %w", err)
+	}
+
+	sessionIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		sessionIDs = append(sessionIDs, result.SessionID)
+	}
+	sessions, err := s.sessionRepo.GetByIDs(sessionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	sessionsByID := make(map[string]*entity.Session, len(sessions))
+	for _, session := range sessions {
+		sessionsByID[session.ID] = session
+	}
+
+	analytics := &entity.FocusAnalytics{
+		From:        from,
+		To:          to,
+		Granularity: query.Granularity,
+		Buckets:     analyticsBuckets(from, to, query.Granularity),
+	}
+
+	for _, result := range results {
+		// Сессия могла быть удалена после завершения: ее итог учитывается без режима и циклов
+		session := sessionsByID[result.SessionID]
+		cycles := 0
+		if session != nil {
+			cycles = session.CurrentCycle
+			if cycles <= 0 {
+				cycles = 1
+			}
+		}
+
+		if result.CompletedAt.Before(fromAt) {
+			analytics.Previous.Add(result, cycles)
+			continue
+		}
+
+		analytics.Summary.Add(result, cycles)
+		if bucket := findBucket(analytics.Buckets, entity.LocalDay(result.CompletedAt, loc)); bucket != nil {
+			bucket.Add(result, cycles)
+		}
+
+		// Без времени старта сессия считается начатой за время фокуса до завершения
+		startedAt := result.CompletedAt.Add(-time.Duration(result.FocusTime) * time.Minute)
+		if session != nil && session.StartedAt != nil {
+			startedAt = *session.StartedAt
+		}
+		analytics.Hours[startedAt.In(loc).Hour()] += result.FocusTime
+
+		if session == nil {
+			continue
+		}
+		switch session.Mode {
+		case entity.SessionModeSolo:
+			analytics.Solo.Add(result, cycles)
+		case entity.SessionModeGroup:
+			analytics.Group.Add(result, cycles)
+		}
+	}
+
+	return analytics, nil
+}
+
+// analyticsPeriod проверяет период запроса и подставляет период по умолчанию, заканчивающийся сегодня
+func analyticsPeriod(query entity.AnalyticsQuery, today time.Time) (time.Time, time.Time, error) {
+	switch query.Granularity {
+	case entity.AnalyticsGranularityDay, entity.AnalyticsGranularityWeek:
+	default:
+		return time.Time{}, time.Time{}, entity.InvalidArgument("granularity must be day or week")
+	}
+
+	to := query.To
+	if to.IsZero() {
+		to = today
+	}
+	from := query.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-defaultAnalyticsDays)
+		if query.Granularity == entity.AnalyticsGranularityWeek {
+			from = weekStart(to).AddDate(0, 0, -7*(defaultAnalyticsWeeks-1))
+		}
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, entity.InvalidArgument("from must not be after to")
+	}
+	if to.Sub(from).Hours()/24 >= maxAnalyticsDays {
+		return time.Time{}, time.Time{}, entity.InvalidArgument(fmt.Sprintf("period must not exceed %d days", maxAnalyticsDays))
+	}
+
+	return from, to, nil
+}
+
+// analyticsBuckets создает пустые отрезки периода: по дню или по неделе с понедельника.
+// Первая неделя может начинаться раньше from
+func analyticsBuckets(from, to time.Time, granularity entity.AnalyticsGranularity) []*entity.FocusBucket {
+	start, step := from, 1
+	if granularity == entity.AnalyticsGranularityWeek {
+		start, step = weekStart(from), 7
+	}
+
+	var buckets []*entity.FocusBucket
+	for day := start; !day.After(to); day = day.AddDate(0, 0, step) {
+		buckets = append(buckets, &entity.FocusBucket{Start: day})
+	}
+	return buckets
+}
+
+// findBucket возвращает отрезок, в который попадает день; отрезки идут по возрастанию
+func findBucket(buckets []*entity.FocusBucket, day time.Time) *entity.FocusBucket {
+	var found *entity.FocusBucket
+	for _, bucket := range buckets {
+		if bucket.Start.After(day) {
+			break
+		}
+		found = bucket
+	}
+	return found
+}
+
+// weekStart возвращает понедельник недели дня
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// localMidnight возвращает начало дня day (дата в UTC, как у entity.LocalDay) в часовом поясе loc
+func localMidnight(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
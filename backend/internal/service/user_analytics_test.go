@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAnalyticsPeriod(t *testing.T) {
+	// Среда
+	today := date(2026, 3, 18)
+
+	tests := []struct {
+		name     string
+		query    entity.AnalyticsQuery
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "default days", query: entity.AnalyticsQuery{Granularity: entity.AnalyticsGranularityDay}, wantFrom: date(2026, 3, 12), wantTo: today},
+		{name: "default weeks start on monday", query: entity.AnalyticsQuery{Granularity: entity.AnalyticsGranularityWeek}, wantFrom: date(2026, 2, 23), wantTo: today},
+		{name: "explicit period", query: entity.AnalyticsQuery{From: date(2026, 1, 1), To: date(2026, 1, 31), Granularity: entity.AnalyticsGranularityDay}, wantFrom: date(2026, 1, 1), wantTo: date(2026, 1, 31)},
+		{name: "default from before explicit to", query: entity.AnalyticsQuery{To: date(2026, 1, 31), Granularity: entity.AnalyticsGranularityDay}, wantFrom: date(2026, 1, 25), wantTo: date(2026, 1, 31)},
+		{name: "single day", query: entity.AnalyticsQuery{From: today, To: today, Granularity: entity.AnalyticsGranularityDay}, wantFrom: today, wantTo: today},
+		{name: "longest period", query: entity.AnalyticsQuery{From: date(2025, 3, 18), To: date(2026, 3, 18), Granularity: entity.AnalyticsGranularityWeek}, wantFrom: date(2025, 3, 18), wantTo: date(2026, 3, 18)},
+		{name: "too long", query: entity.AnalyticsQuery{From: date(2025, 3, 17), To: date(2026, 3, 18), Granularity: entity.AnalyticsGranularityDay}, wantErr: true},
+		{name: "from after to", query: entity.AnalyticsQuery{From: date(2026, 3, 19), To: today, Granularity: entity.AnalyticsGranularityDay}, wantErr: true},
+		{name: "unknown granularity", query: entity.AnalyticsQuery{Granularity: "month"}, wantErr: true},
+		{name: "missing granularity", query: entity.AnalyticsQuery{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := analyticsPeriod(tt.query, today)
+			if tt.wantErr {
+				if !errors.Is(err, entity.ErrInvalidArgument) {
+					t.Fatalf("analyticsPeriod() error = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyticsPeriod() error: %v", err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("analyticsPeriod() = %v..%v, want %v..%v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestAnalyticsBuckets(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        time.Time
+		to          time.Time
+		granularity entity.AnalyticsGranularity
+		wantStarts  []time.Time
+	}{
+		{name: "days", from: date(2026, 3, 16), to: date(2026, 3, 18), granularity: entity.AnalyticsGranularityDay, wantStarts: []time.Time{date(2026, 3, 16), date(2026, 3, 17), date(2026, 3, 18)}},
+		{name: "single day", from: date(2026, 3, 18), to: date(2026, 3, 18), granularity: entity.AnalyticsGranularityDay, wantStarts: []time.Time{date(2026, 3, 18)}},
+		{name: "first week starts before from", from: date(2026, 3, 4), to: date(2026, 3, 18), granularity: entity.AnalyticsGranularityWeek, wantStarts: []time.Time{date(2026, 3, 2), date(2026, 3, 9), date(2026, 3, 16)}},
+		{name: "weeks across months", from: date(2026, 3, 30), to: date(2026, 4, 6), granularity: entity.AnalyticsGranularityWeek, wantStarts: []time.Time{date(2026, 3, 30), date(2026, 4, 6)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := analyticsBuckets(tt.from, tt.to, tt.granularity)
+			if len(buckets) != len(tt.wantStarts) {
+				t.Fatalf("analyticsBuckets() returned %d buckets, want %d", len(buckets), len(tt.wantStarts))
+			}
+			for i, bucket := range buckets {
+				if !bucket.Start.Equal(tt.wantStarts[i]) {
+					t.Errorf("bucket %d starts %v, want %v", i, bucket.Start, tt.wantStarts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindBucket(t *testing.T) {
+	buckets := analyticsBuckets(date(2026, 3, 4), date(2026, 3, 18), entity.AnalyticsGranularityWeek)
+
+	tests := []struct {
+		name      string
+		day       time.Time
+		wantStart *time.Time
+	}{
+		{name: "before first bucket", day: date(2026, 3, 1)},
+		{name: "first day of bucket", day: date(2026, 3, 9), wantStart: timeOf(date(2026, 3, 9))},
+		{name: "inside bucket", day: date(2026, 3, 12), wantStart: timeOf(date(2026, 3, 9))},
+		{name: "last bucket", day: date(2026, 3, 18), wantStart: timeOf(date(2026, 3, 16))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := findBucket(buckets, tt.day)
+			switch {
+			case tt.wantStart == nil && bucket != nil:
+				t.Errorf("findBucket(%v) = %v, want nil", tt.day, bucket.Start)
+			case tt.wantStart != nil && (bucket == nil || !bucket.Start.Equal(*tt.wantStart)):
+				t.Errorf("findBucket(%v) = %v, want %v", tt.day, bucket, *tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want time.Time
+	}{
+		{day: date(2026, 3, 16), want: date(2026, 3, 16)}, // понедельник
+		{day: date(2026, 3, 18), want: date(2026, 3, 16)},
+		{day: date(2026, 3, 22), want: date(2026, 3, 16)}, // воскресенье
+		{day: date(2026, 3, 1), want: date(2026, 2, 23)},
+	}
+
+	for _, tt := range tests {
+		if got := weekStart(tt.day); !got.Equal(tt.want) {
+			t.Errorf("weekStart(%v) = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+}
+
+func timeOf(t time.Time) *time.Time {
+	return &t
+}
@@ -2,18 +2,32 @@ package service
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
 )
 
 type UserService struct {
-	userRepo interfaces.UserRepository
+	userRepo        interfaces.UserRepository
+	contactRepo     interfaces.ContactRepository
+	achievementRepo interfaces.AchievementRepository
+	sessionRepo     interfaces.SessionRepository
 }
 
-func NewUserService(userRepo interfaces.UserRepository) interfaces.UserService {
+func NewUserService(
+	userRepo interfaces.UserRepository,
+	contactRepo interfaces.ContactRepository,
+	achievementRepo interfaces.AchievementRepository,
+	sessionRepo interfaces.SessionRepository,
+) interfaces.UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		contactRepo:     contactRepo,
+		achievementRepo: achievementRepo,
+		sessionRepo:     sessionRepo,
 	}
 }
 
@@ -31,8 +45,154 @@ func (s *UserService) GetProfile(userID string) (*entity.User, *entity.UserStats
 	return user, stats, nil
 }
 
-func (s *UserService) GetContacts(userID string) ([]*entity.User, error) {
-	// В реальности нужно получать контакты из Max API
-	// Пока возвращаем пустой список
-	return []*entity.User{}, nil
+// GetAchievements возвращает полученные пользователем значки в порядке получения
+func (s *UserService) GetAchievements(userID string) ([]*entity.UserAchievement, error) {
+	achievements, err := s.achievementRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	return achievements, nil
+}
+
+// GetContacts возвращает контакты пользователя: участников общих сессий и тех, на кого он подписан
+func (s *UserService) GetContacts(userID string) ([]*entity.UserContact, error) {
+	contacts, err := s.contactRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	contactIDs := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		contactIDs = append(contactIDs, contact.ContactID)
+	}
+	users, err := s.userRepo.GetByIDs(contactIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact users: %w", err)
+	}
+	usersByID := make(map[string]*entity.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	result := make([]*entity.UserContact, 0, len(contacts))
+	for _, contact := range contacts {
+		user, exists := usersByID[contact.ContactID]
+		if !exists {
+			// Пропускаем пользователей, которых нет в БД
+			continue
+		}
+		contact.Contact = user
+		result = append(result, contact)
+	}
+
+	return result, nil
+}
+
+// GetInviteCode возвращает код приглашения в контакты, создавая его при первом запросе
+func (s *UserService) GetInviteCode(userID string) (string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %w", err)
+	}
+	if user == nil {
+		return "", entity.ErrUserNotFound
+	}
+
+	if user.InviteCode != nil {
+		return *user.InviteCode, nil
+	}
+
+	inviteCode := uuid.New().String()[:8] // Короткий код, как у приглашений в сессию
+	created, err := s.userRepo.SetInviteCode(userID, inviteCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to set invite code: %w", err)
+	}
+	if created {
+		return inviteCode, nil
+	}
+
+	// Параллельный запрос успел создать код раньше — отдаем его
+	user, err = s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get invite code: %w", err)
+	}
+	if user == nil || user.InviteCode == nil {
+		return "", entity.ErrUserNotFound
+	}
+	return *user.InviteCode, nil
+}
+
+// FollowUser добавляет пользователя в контакты по Telegram ID или коду приглашения
+func (s *UserService) FollowUser(userID string, target entity.FollowTarget) (*entity.UserContact, error) {
+	var (
+		user *entity.User
+		err  error
+	)
+	switch {
+	case target.TelegramUserID != 0:
+		user, err = s.userRepo.GetByTelegramUserID(target.TelegramUserID)
+	case target.InviteCode != "":
+		user, err = s.userRepo.GetByInviteCode(strings.TrimPrefix(target.InviteCode, entity.ContactInvitePrefix))
+	default:
+		return nil, entity.InvalidArgument("telegramUserId or inviteCode is required")
+	}
+	// Репозиторий в памяти сообщает об отсутствии пользователя ошибкой
+	if err != nil || user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	if user.ID == userID {
+		return nil, entity.InvalidArgument("cannot add yourself to contacts")
+	}
+
+	contact := &entity.UserContact{
+		UserID:    userID,
+		ContactID: user.ID,
+		Source:    entity.ContactSourceFollow,
+		CreatedAt: time.Now(),
+	}
+	if err := s.contactRepo.Follow(contact); err != nil {
+		return nil, fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	contact.Contact = user
+	return contact, nil
+}
+
+// RemoveContact удаляет контакт пользователя; после новой общей сессии он появится снова
+func (s *UserService) RemoveContact(userID string, contactID string) error {
+	removed, err := s.contactRepo.Delete(userID, contactID)
+	if err != nil {
+		return fmt.Errorf("failed to remove contact: %w", err)
+	}
+	if !removed {
+		return entity.ErrContactNotFound
+	}
+	return nil
+}
+
+// UpdateSettings меняет настройки пользователя. Часовой пояс определяет границы его дней: серии, дневной лидерборд, даты отчетов
+func (s *UserService) UpdateSettings(userID string, settings entity.UserSettings) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	if settings.Timezone != nil {
+		timezone := strings.TrimSpace(*settings.Timezone)
+		if _, err := entity.LoadTimezone(timezone); err != nil {
+			return nil, err
+		}
+		user.Timezone = timezone
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
 }
@@ -45,7 +45,7 @@ func (h *AuthHandler) login(c *gin.Context) {
 	fmt.Printf("[Auth Handler]   User-Agent: %s\n", c.Request.Header.Get("User-Agent"))
 	fmt.Printf("[Auth Handler]   X-Forwarded-Proto: %s\n", c.Request.Header.Get("X-Forwarded-Proto"))
 
-	tokens, user, err := h.authService.Login(req.InitData, req.DeviceID)
+	tokens, user, err := h.authService.Login(req.InitData, req.DeviceID, req.Timezone)
 	if err != nil {
 		fmt.Printf("[Auth Handler] ❌ Login failed: %v\n", err)
 		h.ErrorResponse(c, http.StatusUnauthorized, err.Error())
@@ -71,6 +71,7 @@ func (h *AuthHandler) login(c *gin.Context) {
 			"id":        user.ID,
 			"name":      user.Name,
 			"avatarUrl": user.AvatarURL,
+			"timezone":  user.Timezone,
 			"createdAt": user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		},
 	})
@@ -1,10 +1,12 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rnegic/synchronous/internal/entity"
 )
 
 type BaseHandler struct {
@@ -30,6 +32,27 @@ func (h *BaseHandler) ErrorResponse(c *gin.Context, statusCode int, message stri
 	})
 }
 
+// DomainErrorResponse отвечает статусом, соответствующим доменной ошибке сервиса;
+// ошибки, не относящиеся к доменным, отдаются с fallbackStatus
+func (h *BaseHandler) DomainErrorResponse(c *gin.Context, err error, fallbackStatus int) {
+	h.ErrorResponse(c, domainErrorStatus(err, fallbackStatus), err.Error())
+}
+
+func domainErrorStatus(err error, fallbackStatus int) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidTransition), errors.Is(err, entity.ErrSessionFull), errors.Is(err, entity.ErrApprovalRequired):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidArgument):
+		return http.StatusBadRequest
+	default:
+		return fallbackStatus
+	}
+}
+
 func (h *BaseHandler) SuccessResponse(c *gin.Context, statusCode int, data interface{}) {
 	c.JSON(statusCode, data)
 }
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// getJoinRequests возвращает нерассмотренные заявки на вход (для создателя и со-ведущих)
+func (h *SessionHandler) getJoinRequests(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	requests, err := h.sessionService.ListJoinRequests(c.Param("sessionId"), userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	requestsList := make([]gin.H, 0, len(requests))
+	for _, request := range requests {
+		requestsList = append(requestsList, joinRequestToMap(request))
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"requests": requestsList,
+	})
+}
+
+func (h *SessionHandler) approveJoinRequest(c *gin.Context) {
+	h.reviewJoinRequest(c, true)
+}
+
+func (h *SessionHandler) denyJoinRequest(c *gin.Context) {
+	h.reviewJoinRequest(c, false)
+}
+
+func (h *SessionHandler) reviewJoinRequest(c *gin.Context, approve bool) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	decision, err := h.sessionService.ReviewJoinRequest(c.Param("sessionId"), c.Param("requestId"), userID, approve)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	announceJoinDecision(h.wsHandler, decision)
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"request": joinRequestToMap(decision.Request),
+	})
+}
+
+// addCoHost назначает участника со-ведущим
+func (h *SessionHandler) addCoHost(c *gin.Context) {
+	h.setCoHost(c, true)
+}
+
+func (h *SessionHandler) removeCoHost(c *gin.Context) {
+	h.setCoHost(c, false)
+}
+
+func (h *SessionHandler) setCoHost(c *gin.Context, isCoHost bool) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	targetUserID := c.Param("userId")
+
+	if err := h.sessionService.SetCoHost(sessionID, userID, targetUserID, isCoHost); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "co_host_updated", gin.H{
+			"sessionId": sessionID,
+			"userId":    targetUserID,
+			"isCoHost":  isCoHost,
+		})
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// announceJoinDecision сообщает заявителю о решении по заявке, а участникам — о новом участнике.
+// Используется и REST-обработчиком, и webhook'ом (кнопки в Telegram)
+func announceJoinDecision(wsHandler *WebSocketHandler, decision *entity.JoinRequestDecision) {
+	if wsHandler == nil || decision == nil {
+		return
+	}
+
+	request := decision.Request
+	event := gin.H{
+		"sessionId": request.SessionID,
+		"requestId": request.ID,
+		"status":    request.Status,
+	}
+	if decision.Waitlist > 0 {
+		event["waitlisted"] = true
+		event["position"] = decision.Waitlist
+	}
+	wsHandler.SendToUser(request.UserID, "join_request_resolved", event)
+	wsHandler.SendToSession(request.SessionID, "join_request_resolved", event)
+
+	if decision.Session != nil {
+		broadcastParticipantJoined(wsHandler, decision.Session, request.UserID)
+	}
+}
+
+func joinRequestToMap(request *entity.SessionJoinRequest) gin.H {
+	requestMap := gin.H{
+		"id":        request.ID,
+		"sessionId": request.SessionID,
+		"userId":    request.UserID,
+		"status":    request.Status,
+		"createdAt": request.CreatedAt.Format(time.RFC3339),
+	}
+	if request.ReviewedBy != nil {
+		requestMap["reviewedBy"] = *request.ReviewedBy
+	}
+	if request.ReviewedAt != nil {
+		requestMap["reviewedAt"] = request.ReviewedAt.Format(time.RFC3339)
+	}
+	return requestMap
+}
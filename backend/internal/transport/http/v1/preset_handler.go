@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// presetRequest — тело создания и замены пресета
+type presetRequest struct {
+	Name          string           `json:"name" binding:"required"`
+	Mode          string           `json:"mode" binding:"required"`
+	FocusDuration int              `json:"focusDuration" binding:"required"`
+	BreakDuration int              `json:"breakDuration" binding:"required"`
+	Cycles        int              `json:"cycles"`
+	LongBreak     int              `json:"longBreak"`
+	BreakEvery    int              `json:"longBreakEvery"`
+	Phases        entity.PhasePlan `json:"phases"`
+	IsPrivate     bool             `json:"isPrivate"`
+	GroupName     *string          `json:"groupName"`
+	Tasks         []string         `json:"tasks"`
+}
+
+func (r *presetRequest) toPreset() *entity.SessionPreset {
+	return &entity.SessionPreset{
+		Name:           r.Name,
+		Mode:           entity.SessionMode(r.Mode),
+		FocusDuration:  r.FocusDuration,
+		BreakDuration:  r.BreakDuration,
+		Cycles:         r.Cycles,
+		LongBreak:      r.LongBreak,
+		LongBreakEvery: r.BreakEvery,
+		PhasePlan:      r.Phases,
+		IsPrivate:      r.IsPrivate,
+		GroupName:      r.GroupName,
+		Tasks:          r.Tasks,
+	}
+}
+
+// getPresets возвращает пресеты текущего пользователя
+func (h *UserHandler) getPresets(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	presets, err := h.presetService.GetPresets(userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	presetsList := make([]gin.H, 0, len(presets))
+	for _, preset := range presets {
+		presetsList = append(presetsList, presetToMap(preset))
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"presets": presetsList,
+	})
+}
+
+// getPreset возвращает один пресет текущего пользователя
+func (h *UserHandler) getPreset(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	preset, err := h.presetService.GetPreset(c.Param("presetId"), userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"preset": presetToMap(preset),
+	})
+}
+
+// createPreset сохраняет новый пресет
+func (h *UserHandler) createPreset(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req presetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	preset, err := h.presetService.CreatePreset(userID, req.toPreset())
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"preset": presetToMap(preset),
+	})
+}
+
+// updatePreset заменяет настройки пресета
+func (h *UserHandler) updatePreset(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req presetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	preset, err := h.presetService.UpdatePreset(c.Param("presetId"), userID, req.toPreset())
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"preset": presetToMap(preset),
+	})
+}
+
+// deletePreset удаляет пресет; сессии, созданные из него, не меняются
+func (h *UserHandler) deletePreset(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.presetService.DeletePreset(c.Param("presetId"), userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func presetToMap(preset *entity.SessionPreset) gin.H {
+	tasks := []string(preset.Tasks)
+	if tasks == nil {
+		tasks = []string{}
+	}
+
+	presetMap := gin.H{
+		"id":            preset.ID,
+		"name":          preset.Name,
+		"mode":          preset.Mode,
+		"focusDuration": preset.FocusDuration,
+		"breakDuration": preset.BreakDuration,
+		"cycles":        preset.Cycles,
+		"isPrivate":     preset.IsPrivate,
+		"tasks":         tasks,
+		"createdAt":     preset.CreatedAt.Format(time.RFC3339),
+		"updatedAt":     preset.UpdatedAt.Format(time.RFC3339),
+	}
+	if preset.LongBreak > 0 {
+		presetMap["longBreak"] = preset.LongBreak
+		presetMap["longBreakEvery"] = preset.LongBreakEvery
+	}
+	if len(preset.PhasePlan) > 0 {
+		presetMap["phases"] = preset.PhasePlan
+	}
+	if preset.GroupName != nil {
+		presetMap["groupName"] = *preset.GroupName
+	}
+	return presetMap
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+// createSeries создает повторяющуюся групповую сессию
+func (h *SessionHandler) createSeries(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		GroupName     *string   `json:"groupName"`
+		IsPrivate     bool      `json:"isPrivate"`
+		FocusDuration int       `json:"focusDuration" binding:"required"`
+		BreakDuration int       `json:"breakDuration" binding:"required"`
+		RRule         string    `json:"rrule" binding:"required"`    // например FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR
+		Timezone      string    `json:"timezone" binding:"required"` // IANA, например Europe/Moscow
+		StartsAt      time.Time `json:"startsAt" binding:"required"` // первое повторение (RFC3339)
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(userID, &entity.SessionSeries{
+		GroupName:     req.GroupName,
+		IsPrivate:     req.IsPrivate,
+		FocusDuration: req.FocusDuration,
+		BreakDuration: req.BreakDuration,
+		RRule:         req.RRule,
+		Timezone:      req.Timezone,
+		StartsAt:      req.StartsAt,
+	})
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"series": seriesToMap(series),
+	})
+}
+
+// getSeries возвращает серию и ее ближайшие экземпляры
+func (h *SessionHandler) getSeries(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	series, upcoming, err := h.seriesService.GetSeries(c.Param("seriesId"), userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	upcomingList := make([]gin.H, 0, len(upcoming))
+	for _, session := range upcoming {
+		upcomingList = append(upcomingList, h.sessionToMap(session))
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"series":   seriesToMap(series),
+		"upcoming": upcomingList,
+	})
+}
+
+// deleteSeries останавливает серию и отменяет ее еще не начавшиеся сессии
+func (h *SessionHandler) deleteSeries(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cancelled, err := h.seriesService.DeleteSeries(c.Param("seriesId"), userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		for _, sessionID := range cancelled {
+			h.wsHandler.SendToSession(sessionID, "session_cancelled", gin.H{
+				"sessionId":   sessionID,
+				"status":      entity.SessionStatusCancelled,
+				"cancelledBy": userID,
+			})
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// subscribeToSeries подписывает пользователя: он будет автоматически добавляться в каждый экземпляр серии
+func (h *SessionHandler) subscribeToSeries(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	seriesID := c.Param("seriesId")
+	if err := h.seriesService.Subscribe(seriesID, userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"seriesId":   seriesID,
+		"subscribed": true,
+	})
+}
+
+func (h *SessionHandler) unsubscribeFromSeries(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	seriesID := c.Param("seriesId")
+	if err := h.seriesService.Unsubscribe(seriesID, userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"seriesId":   seriesID,
+		"subscribed": false,
+	})
+}
+
+// joinSeriesByInviteLink присоединяет к ближайшему экземпляру серии по постоянной ссылке
+func (h *SessionHandler) joinSeriesByInviteLink(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		InviteLink string `json:"inviteLink" binding:"required"`
+		Subscribe  bool   `json:"subscribe"` // подписаться и на следующие экземпляры
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "inviteLink is required")
+		return
+	}
+
+	session, err := h.seriesService.JoinByInviteLink(req.InviteLink, userID, req.Subscribe)
+	if err != nil {
+		if h.respondDeferredJoin(c, err) {
+			return
+		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	broadcastParticipantJoined(h.wsHandler, session, userID)
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session": h.sessionToMap(session),
+	})
+}
+
+func seriesToMap(series *entity.SessionSeries) gin.H {
+	seriesMap := gin.H{
+		"id":            series.ID,
+		"creatorId":     series.CreatorID,
+		"isPrivate":     series.IsPrivate,
+		"focusDuration": series.FocusDuration,
+		"breakDuration": series.BreakDuration,
+		"rrule":         series.RRule,
+		"timezone":      series.Timezone,
+		"startsAt":      series.StartsAt.Format(time.RFC3339),
+		"inviteLink":    series.InviteLink,
+		"createdAt":     series.CreatedAt.Format(time.RFC3339),
+	}
+	if series.GroupName != nil {
+		seriesMap["groupName"] = *series.GroupName
+	}
+	return seriesMap
+}
@@ -1,10 +1,10 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +15,8 @@ import (
 type SessionHandler struct {
 	*BaseHandler
 	sessionService     interfaces.SessionService
+	seriesService      interfaces.SessionSeriesService
+	presetService      interfaces.SessionPresetService
 	messageService     interfaces.MessageService
 	leaderboardService interfaces.LeaderboardService
 	wsHandler          *WebSocketHandler
@@ -23,6 +25,8 @@ type SessionHandler struct {
 func NewSessionHandler(
 	baseHandler *BaseHandler,
 	sessionService interfaces.SessionService,
+	seriesService interfaces.SessionSeriesService,
+	presetService interfaces.SessionPresetService,
 	messageService interfaces.MessageService,
 	leaderboardService interfaces.LeaderboardService,
 	wsHandler *WebSocketHandler,
@@ -30,6 +34,8 @@ func NewSessionHandler(
 	return &SessionHandler{
 		BaseHandler:        baseHandler,
 		sessionService:     sessionService,
+		seriesService:      seriesService,
+		presetService:      presetService,
 		messageService:     messageService,
 		leaderboardService: leaderboardService,
 		wsHandler:          wsHandler,
@@ -49,12 +55,25 @@ func (h *SessionHandler) RegisterRoutes(router *gin.RouterGroup) {
 		session := sessions.Group("/:sessionId")
 		{
 			session.GET("", h.getSession)
+			session.DELETE("", h.deleteSession)
+			session.POST("/clone", h.cloneSession)
 			session.POST("/join", h.joinSession)
+			session.POST("/leave", h.leaveSession)
+			session.DELETE("/waitlist", h.leaveWaitlist)
+			session.POST("/transfer", h.transferOwnership)
+			session.PUT("/cohosts/:userId", h.addCoHost)
+			session.DELETE("/cohosts/:userId", h.removeCoHost)
+			session.GET("/join-requests", h.getJoinRequests)
+			session.POST("/join-requests/:requestId/approve", h.approveJoinRequest)
+			session.POST("/join-requests/:requestId/deny", h.denyJoinRequest)
 			session.PATCH("/ready", h.setReady)
 			session.POST("/start", h.startSession)
+			session.PATCH("/start-policy", h.updateStartPolicy)
+			session.POST("/countdown/cancel", h.cancelCountdown)
 			session.POST("/pause", h.pauseSession)
 			session.POST("/resume", h.resumeSession)
 			session.POST("/complete", h.completeSession)
+			session.POST("/cancel", h.cancelSession)
 			session.GET("/report", h.getSessionReport)
 
 			// Чат
@@ -69,6 +88,9 @@ func (h *SessionHandler) RegisterRoutes(router *gin.RouterGroup) {
 			// Прогресс участников
 			session.GET("/participants/progress", h.getParticipantsProgress)
 
+			// Модерация
+			session.DELETE("/participants/:userId", h.removeParticipant)
+
 			// Сообщения
 			session.GET("/messages", h.getMessages)
 			session.POST("/messages", h.sendMessage)
@@ -78,11 +100,107 @@ func (h *SessionHandler) RegisterRoutes(router *gin.RouterGroup) {
 		}
 	}
 
+	// Повторяющиеся серии
+	series := router.Group("/series")
+	{
+		series.POST("", h.createSeries)
+		series.POST("/join-by-invite", h.joinSeriesByInviteLink)
+		series.GET("/:seriesId", h.getSeries)
+		series.DELETE("/:seriesId", h.deleteSeries)
+		series.POST("/:seriesId/subscribe", h.subscribeToSeries)
+		series.DELETE("/:seriesId/subscribe", h.unsubscribeFromSeries)
+	}
+
 	// Глобальный лидерборд
 	router.GET("/leaderboard/global", h.getGlobalLeaderboard)
+	router.GET("/leaderboard/friends", h.getFriendsLeaderboard)
 }
 
-// createSession создает новую сессию
+// createSessionRequest — тело создания сессии. Поля-указатели (и списки) могут отсутствовать:
+// тогда берется значение из пресета presetId
+type createSessionRequest struct {
+	PresetID      string           `json:"presetId"`
+	Mode          *string          `json:"mode"`
+	Tasks         []string         `json:"tasks"`
+	FocusDuration *int             `json:"focusDuration"`
+	BreakDuration *int             `json:"breakDuration"`
+	GroupName     *string          `json:"groupName"`
+	IsPrivate     *bool            `json:"isPrivate"`
+	ScheduledAt   *time.Time       `json:"scheduledAt"` // RFC3339; сессия стартует автоматически в это время
+	StartPolicy   string           `json:"startPolicy"` // manual (по умолчанию), all_ready или quorum
+	StartQuorum   int              `json:"startQuorum"`
+	Countdown     *int             `json:"startCountdown"`  // секунды обратного отсчета перед автостартом, 0 — сразу
+	Capacity      int              `json:"maxParticipants"` // 0 — лимит по умолчанию
+	Approval      bool             `json:"requireApproval"` // только для приватных сессий
+	Cycles        *int             `json:"cycles"`          // 0 — без ограничения
+	LongBreak     *int             `json:"longBreak"`       // длинный перерыв в минутах
+	BreakEvery    *int             `json:"longBreakEvery"`  // по умолчанию каждые 4 цикла
+	Phases        entity.PhasePlan `json:"phases"`          // явная последовательность фаз вместо циклов
+}
+
+// missingField возвращает первое обязательное без пресета поле, которого нет в запросе
+func (r *createSessionRequest) missingField() string {
+	switch {
+	case r.Mode == nil:
+		return "mode"
+	case r.Tasks == nil:
+		return "tasks"
+	case r.FocusDuration == nil || *r.FocusDuration == 0:
+		return "focusDuration"
+	case r.BreakDuration == nil || *r.BreakDuration == 0:
+		return "breakDuration"
+	}
+	return ""
+}
+
+// settings накладывает переданные поля запроса на настройки пресета (или на пустые настройки).
+// План циклов перекрывается целиком: явные фазы и циклы с длинными перерывами не смешиваются
+func (r *createSessionRequest) settings(base entity.SessionSettings) entity.SessionSettings {
+	settings := base
+	if r.Mode != nil {
+		settings.Mode = entity.SessionMode(*r.Mode)
+	}
+	if r.Tasks != nil {
+		settings.Tasks = r.Tasks
+	}
+	if r.FocusDuration != nil {
+		settings.FocusDuration = *r.FocusDuration
+	}
+	if r.BreakDuration != nil {
+		settings.BreakDuration = *r.BreakDuration
+	}
+	if r.GroupName != nil {
+		settings.GroupName = r.GroupName
+	}
+	if r.IsPrivate != nil {
+		settings.IsPrivate = *r.IsPrivate
+	}
+
+	if r.Phases != nil {
+		settings.Plan = entity.CyclePlan{Phases: r.Phases}
+	} else if r.Cycles != nil || r.LongBreak != nil || r.BreakEvery != nil {
+		settings.Plan.Phases = nil
+		if r.Cycles != nil {
+			settings.Plan.Cycles = *r.Cycles
+		}
+		if r.LongBreak != nil {
+			settings.Plan.LongBreak = *r.LongBreak
+		}
+		if r.BreakEvery != nil {
+			settings.Plan.LongBreakEvery = *r.BreakEvery
+		}
+	}
+
+	settings.ScheduledAt = r.ScheduledAt
+	settings.StartPolicy = entity.StartPolicy(r.StartPolicy)
+	settings.StartQuorum = r.StartQuorum
+	settings.StartCountdown = r.Countdown
+	settings.Capacity = r.Capacity
+	settings.Approval = r.Approval
+	return settings
+}
+
+// createSession создает новую сессию, в том числе из пресета пользователя
 func (h *SessionHandler) createSession(c *gin.Context) {
 	userID := h.GetUserID(c)
 	if userID == "" {
@@ -90,45 +208,82 @@ func (h *SessionHandler) createSession(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Mode          string   `json:"mode" binding:"required"`
-		Tasks         []string `json:"tasks" binding:"required"`
-		FocusDuration int      `json:"focusDuration" binding:"required"`
-		BreakDuration int      `json:"breakDuration" binding:"required"`
-		GroupName     *string  `json:"groupName"`
-		IsPrivate     bool     `json:"isPrivate"`
-	}
-
+	var req createSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body: "+err.Error())
 		return
 	}
 
-	mode := entity.SessionMode(req.Mode)
-	if mode != entity.SessionModeSolo && mode != entity.SessionModeGroup {
+	var base entity.SessionSettings
+	if req.PresetID != "" {
+		preset, err := h.presetService.GetPreset(req.PresetID, userID)
+		if err != nil {
+			h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+			return
+		}
+		base = preset.Settings()
+	} else if field := req.missingField(); field != "" {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body: "+field+" is required")
+		return
+	}
+
+	settings := req.settings(base)
+	if settings.Mode != entity.SessionModeSolo && settings.Mode != entity.SessionModeGroup {
 		h.ErrorResponse(c, http.StatusBadRequest, "invalid mode: must be 'solo' or 'group'")
 		return
 	}
 
-	session, err := h.sessionService.CreateSession(
-		userID,
-		mode,
-		req.Tasks,
-		req.FocusDuration,
-		req.BreakDuration,
-		req.GroupName,
-		req.IsPrivate,
-	)
+	session, err := h.sessionService.CreateSession(userID, settings)
 	if err != nil {
-		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	if h.wsHandler != nil {
+		h.wsHandler.SubscribeUser(session.ID, userID)
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"session": h.sessionToMap(session),
 	})
 }
 
+// cloneSession повторяет сессию из истории: новая ожидающая сессия с теми же настройками.
+// tasks=all|incomplete|none (по умолчанию incomplete) — какие свои задачи перенести,
+// reinvite=true — пригласить прежних участников
+func (h *SessionHandler) cloneSession(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+
+	session, invited, err := h.sessionService.CloneSession(sessionID, userID, entity.CloneOptions{
+		Tasks:    entity.TaskCopyMode(c.Query("tasks")),
+		Reinvite: c.Query("reinvite") == "true",
+	})
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SubscribeUser(session.ID, userID)
+	}
+
+	if invited == nil {
+		invited = []string{}
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session":        h.sessionToMap(session),
+		"clonedFrom":     sessionID,
+		"invitedUserIds": invited,
+	})
+}
+
 // getHistory возвращает историю сессий
 func (h *SessionHandler) getHistory(c *gin.Context) {
 	userID := h.GetUserID(c)
@@ -225,7 +380,7 @@ func (h *SessionHandler) getActiveSession(c *gin.Context) {
 
 	session, err := h.sessionService.GetActiveSession(userID)
 	if err != nil {
-		h.ErrorResponse(c, http.StatusNotFound, err.Error())
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -248,13 +403,7 @@ func (h *SessionHandler) getSession(c *gin.Context) {
 
 	session, err := h.sessionService.GetSession(sessionID, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else if strings.Contains(err.Error(), "access denied") {
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -274,42 +423,104 @@ func (h *SessionHandler) joinSession(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	session, err := h.sessionService.JoinSession(sessionID, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "already started") {
-			h.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if h.respondDeferredJoin(c, err) {
+			return
 		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	// Broadcast participant_joined event via WebSocket
-	if h.wsHandler != nil {
-		// Find the participant who just joined
-		var joinedParticipant *entity.Participant
-		for _, p := range session.Participants {
-			if p.UserID == userID {
-				joinedParticipant = &p
-				break
-			}
-		}
+	broadcastParticipantJoined(h.wsHandler, session, userID)
 
-		if joinedParticipant != nil {
-			h.wsHandler.BroadcastMessage("participant_joined", gin.H{
-				"sessionId": sessionID,
-				"participant": gin.H{
-					"userId":    joinedParticipant.UserID,
-					"userName":  joinedParticipant.UserName,
-					"avatarUrl": joinedParticipant.AvatarURL,
-					"isReady":   joinedParticipant.IsReady,
-					"joinedAt":  joinedParticipant.JoinedAt.Format(time.RFC3339),
-				},
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session": h.sessionToMap(session),
+	})
+}
+
+// broadcastParticipantJoined подписывает вошедшего участника на события сессии и сообщает о нем остальным
+func broadcastParticipantJoined(wsHandler *WebSocketHandler, session *entity.Session, userID string) {
+	if wsHandler == nil {
+		return
+	}
+
+	wsHandler.SubscribeUser(session.ID, userID)
+
+	joinedParticipant := findSessionParticipant(session, userID)
+	if joinedParticipant == nil {
+		return
+	}
+
+	wsHandler.SendToSession(session.ID, "participant_joined", gin.H{
+		"sessionId": session.ID,
+		"participant": gin.H{
+			"userId":    joinedParticipant.UserID,
+			"userName":  joinedParticipant.UserName,
+			"avatarUrl": joinedParticipant.AvatarURL,
+			"isReady":   joinedParticipant.IsReady,
+			"joinedAt":  joinedParticipant.JoinedAt.Format(time.RFC3339),
+		},
+	})
+}
+
+// respondDeferredJoin отвечает 202, если вход отложен: мест нет (лист ожидания)
+// или сессия требует одобрения (заявка отправлена ведущим)
+func (h *SessionHandler) respondDeferredJoin(c *gin.Context, err error) bool {
+	var waitlisted *entity.WaitlistedError
+	if errors.As(err, &waitlisted) {
+		h.SuccessResponse(c, http.StatusAccepted, gin.H{
+			"sessionId":  waitlisted.SessionID,
+			"waitlisted": true,
+			"position":   waitlisted.Position,
+		})
+		return true
+	}
+
+	var pending *entity.JoinRequestPendingError
+	if errors.As(err, &pending) {
+		if h.wsHandler != nil {
+			h.wsHandler.SendToSession(pending.SessionID, "join_request_created", gin.H{
+				"sessionId": pending.SessionID,
+				"requestId": pending.RequestID,
+				"userId":    h.GetUserID(c),
 			})
 		}
+		h.SuccessResponse(c, http.StatusAccepted, gin.H{
+			"sessionId":        pending.SessionID,
+			"approvalRequired": true,
+			"requestId":        pending.RequestID,
+		})
+		return true
 	}
 
-	h.SuccessResponse(c, http.StatusOK, gin.H{
-		"session": h.sessionToMap(session),
-	})
+	return false
+}
+
+// broadcastPromoted сообщает о пользователях, получивших место из листа ожидания
+func (h *SessionHandler) broadcastPromoted(sessionID string, promoted []string) {
+	if h.wsHandler == nil || len(promoted) == 0 {
+		return
+	}
+
+	session, err := h.sessionService.GetSession(sessionID, promoted[0])
+	if err != nil {
+		return
+	}
+
+	for _, userID := range promoted {
+		broadcastParticipantJoined(h.wsHandler, session, userID)
+		h.wsHandler.SendToUser(userID, "waitlist_promoted", gin.H{
+			"sessionId": sessionID,
+		})
+	}
+}
+
+func findSessionParticipant(session *entity.Session, userID string) *entity.Participant {
+	for i := range session.Participants {
+		if session.Participants[i].UserID == userID {
+			return &session.Participants[i]
+		}
+	}
+	return nil
 }
 
 func (h *SessionHandler) joinByInviteLink(c *gin.Context) {
@@ -329,42 +540,133 @@ func (h *SessionHandler) joinByInviteLink(c *gin.Context) {
 
 	session, err := h.sessionService.JoinByInviteLink(req.InviteLink, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.ErrorResponse(c, http.StatusNotFound, "session not found by invite link")
-		} else if strings.Contains(err.Error(), "already started") {
-			h.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if h.respondDeferredJoin(c, err) {
+			return
 		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	broadcastParticipantJoined(h.wsHandler, session, userID)
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session": h.sessionToMap(session),
+	})
+}
+
+// leaveSession выводит участника из сессии (вернуться можно через join, пока сессия не завершена)
+func (h *SessionHandler) leaveSession(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+
+	newCreatorID, promoted, err := h.sessionService.LeaveSession(sessionID, userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	// Broadcast participant_joined event via WebSocket
 	if h.wsHandler != nil {
-		var joinedParticipant *entity.Participant
-		for _, p := range session.Participants {
-			if p.UserID == userID {
-				joinedParticipant = &p
-				break
-			}
+		h.wsHandler.UnsubscribeUser(sessionID, userID)
+		h.wsHandler.SendToSession(sessionID, "participant_left", gin.H{
+			"sessionId": sessionID,
+			"userId":    userID,
+		})
+		if newCreatorID != "" {
+			h.wsHandler.SendToSession(sessionID, "ownership_transferred",
+				ownershipTransferredEvent(sessionID, userID, newCreatorID, "creator_left"))
 		}
+	}
+	h.broadcastPromoted(sessionID, promoted)
 
-		if joinedParticipant != nil {
-			h.wsHandler.BroadcastMessage("participant_joined", gin.H{
-				"sessionId": session.ID,
-				"participant": gin.H{
-					"userId":    joinedParticipant.UserID,
-					"userName":  joinedParticipant.UserName,
-					"avatarUrl": joinedParticipant.AvatarURL,
-					"isReady":   joinedParticipant.IsReady,
-					"joinedAt":  joinedParticipant.JoinedAt.Format(time.RFC3339),
-				},
-			})
+	c.Status(http.StatusOK)
+}
+
+// leaveWaitlist убирает пользователя из листа ожидания заполненной сессии
+func (h *SessionHandler) leaveWaitlist(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.sessionService.LeaveWaitlist(c.Param("sessionId"), userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// removeParticipant исключает участника (создателем сессии); ?ban=true запрещает вернуться по ссылке
+func (h *SessionHandler) removeParticipant(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	targetUserID := c.Param("userId")
+	ban := c.Query("ban") == "true"
+
+	promoted, err := h.sessionService.RemoveParticipant(sessionID, userID, targetUserID, ban)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		event := gin.H{
+			"sessionId": sessionID,
+			"userId":    targetUserID,
+			"banned":    ban,
 		}
+		h.wsHandler.UnsubscribeUser(sessionID, targetUserID)
+		h.wsHandler.SendToSession(sessionID, "participant_removed", event)
+		// Исключенный участник уже отписан от комнаты — сообщаем ему напрямую
+		h.wsHandler.SendToUser(targetUserID, "participant_removed", event)
+	}
+	h.broadcastPromoted(sessionID, promoted)
+
+	c.Status(http.StatusOK)
+}
+
+// transferOwnership передает права создателя другому участнику
+func (h *SessionHandler) transferOwnership(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "userId is required")
+		return
+	}
+
+	if err := h.sessionService.TransferOwnership(sessionID, userID, req.UserID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "ownership_transferred",
+			ownershipTransferredEvent(sessionID, userID, req.UserID, "transfer"))
 	}
 
 	h.SuccessResponse(c, http.StatusOK, gin.H{
-		"session": h.sessionToMap(session),
+		"sessionId": sessionID,
+		"creatorId": req.UserID,
 	})
 }
 
@@ -378,8 +680,9 @@ func (h *SessionHandler) setReady(c *gin.Context) {
 
 	sessionID := c.Param("sessionId")
 
+	// Указатель: required отклоняет нулевое значение, а false — допустимая отметка «не готов»
 	var req struct {
-		IsReady bool `json:"isReady" binding:"required"`
+		IsReady *bool `json:"isReady" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -387,18 +690,14 @@ func (h *SessionHandler) setReady(c *gin.Context) {
 		return
 	}
 
-	if err := h.sessionService.SetReady(sessionID, userID, req.IsReady); err != nil {
-		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	if err := h.sessionService.SetReady(sessionID, userID, *req.IsReady); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	// Broadcast participant_ready event via WebSocket
 	if h.wsHandler != nil {
-		h.wsHandler.BroadcastMessage("participant_ready", gin.H{
-			"sessionId": sessionID,
-			"userId":    userID,
-			"isReady":   req.IsReady,
-		})
+		h.wsHandler.SendToSession(sessionID, "participant_ready", participantReadyEvent(sessionID, userID, *req.IsReady))
 	}
 
 	c.Status(http.StatusOK)
@@ -413,13 +712,12 @@ func (h *SessionHandler) startSession(c *gin.Context) {
 	}
 
 	sessionID := c.Param("sessionId")
+	// force=true — начать, не дожидаясь готовности всех по политике автостарта
+	force := c.Query("force") == "true"
 
-	if err := h.sessionService.StartSession(sessionID, userID); err != nil {
-		if strings.Contains(err.Error(), "only creator") {
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+	late, err := h.sessionService.StartSession(sessionID, userID, force)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -432,9 +730,18 @@ func (h *SessionHandler) startSession(c *gin.Context) {
 
 	// Broadcast session_started event via WebSocket
 	if h.wsHandler != nil {
-		h.wsHandler.BroadcastMessage("session_started", gin.H{
+		h.wsHandler.SendToSession(sessionID, "session_started", gin.H{
 			"sessionId": sessionID,
+			"phase":     phaseToMap(session),
 		})
+
+		// Тем, кто не успел подтвердить готовность, отдельно сообщаем, что сессия уже идет
+		for _, lateUserID := range late {
+			h.wsHandler.SendToUser(lateUserID, "missed_start", gin.H{
+				"sessionId": sessionID,
+				"startedAt": session.StartedAt.Format(time.RFC3339),
+			})
+		}
 	}
 
 	h.SuccessResponse(c, http.StatusOK, gin.H{
@@ -442,10 +749,74 @@ func (h *SessionHandler) startSession(c *gin.Context) {
 			"id":        session.ID,
 			"status":    session.Status,
 			"startedAt": session.StartedAt.Format(time.RFC3339),
+			"phase":     phaseToMap(session),
 		},
 	})
 }
 
+// updateStartPolicy меняет политику автостарта ожидающей сессии
+func (h *SessionHandler) updateStartPolicy(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		StartPolicy string `json:"startPolicy" binding:"required"`
+		StartQuorum int    `json:"startQuorum"`
+		Countdown   *int   `json:"startCountdown"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "startPolicy is required")
+		return
+	}
+
+	session, err := h.sessionService.UpdateStartPolicy(c.Param("sessionId"), userID, entity.StartPolicy(req.StartPolicy), req.StartQuorum, req.Countdown)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(session.ID, "start_policy_updated", gin.H{
+			"sessionId":      session.ID,
+			"startPolicy":    session.StartPolicy,
+			"startQuorum":    session.StartQuorum,
+			"startCountdown": int(session.CountdownDuration().Seconds()),
+		})
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session": h.sessionToMap(session),
+	})
+}
+
+// cancelCountdown останавливает обратный отсчет автостарта; готовность участников сбрасывается
+func (h *SessionHandler) cancelCountdown(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	if err := h.sessionService.CancelAutoStart(sessionID, userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "start_countdown_cancelled", gin.H{
+			"sessionId":  sessionID,
+			"reason":     "cancelled_by_creator",
+			"readyReset": true,
+		})
+	}
+
+	c.Status(http.StatusOK)
+}
+
 // pauseSession ставит сессию на паузу
 func (h *SessionHandler) pauseSession(c *gin.Context) {
 	userID := h.GetUserID(c)
@@ -457,11 +828,7 @@ func (h *SessionHandler) pauseSession(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 
 	if err := h.sessionService.PauseSession(sessionID, userID); err != nil {
-		if strings.Contains(err.Error(), "not authorized") || strings.Contains(err.Error(), "only creator") {
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -472,6 +839,10 @@ func (h *SessionHandler) pauseSession(c *gin.Context) {
 		return
 	}
 
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "session_paused", sessionStatusEvent(session))
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"session": gin.H{
 			"id":     session.ID,
@@ -491,11 +862,7 @@ func (h *SessionHandler) resumeSession(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 
 	if err := h.sessionService.ResumeSession(sessionID, userID); err != nil {
-		if strings.Contains(err.Error(), "not authorized") || strings.Contains(err.Error(), "only creator") {
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -506,6 +873,10 @@ func (h *SessionHandler) resumeSession(c *gin.Context) {
 		return
 	}
 
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "session_resumed", sessionStatusEvent(session))
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"session": gin.H{
 			"id":     session.ID,
@@ -526,15 +897,77 @@ func (h *SessionHandler) completeSession(c *gin.Context) {
 
 	report, err := h.sessionService.CompleteSession(sessionID, userID)
 	if err != nil {
-		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	if h.wsHandler != nil {
+		for _, achievement := range report.Achievements {
+			h.wsHandler.SendToUser(achievement.UserID, "achievement_unlocked", achievementToMap(achievement))
+		}
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"report": h.buildReportResponse(report),
 	})
 }
 
+// cancelSession отменяет незавершенную сессию
+func (h *SessionHandler) cancelSession(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+
+	if err := h.sessionService.CancelSession(sessionID, userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "session_cancelled", gin.H{
+			"sessionId":   sessionID,
+			"status":      entity.SessionStatusCancelled,
+			"cancelledBy": userID,
+		})
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"session": gin.H{
+			"id":     sessionID,
+			"status": entity.SessionStatusCancelled,
+		},
+	})
+}
+
+// deleteSession удаляет сессию вместе с задачами, участниками и чатом Telegram
+func (h *SessionHandler) deleteSession(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+
+	if err := h.sessionService.DeleteSession(sessionID, userID); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendToSession(sessionID, "session_deleted", gin.H{
+			"sessionId": sessionID,
+			"deletedBy": userID,
+		})
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func (h *SessionHandler) getSessionReport(c *gin.Context) {
 	userID := h.GetUserID(c)
 	if userID == "" {
@@ -550,14 +983,7 @@ func (h *SessionHandler) getSessionReport(c *gin.Context) {
 
 	report, err := h.sessionService.GetSessionReport(sessionID, userID)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "not found"):
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		case strings.Contains(err.Error(), "access denied"):
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		default:
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -569,17 +995,8 @@ func (h *SessionHandler) getSessionReport(c *gin.Context) {
 // sessionToMap конвертирует сессию в map для JSON ответа
 func (h *SessionHandler) sessionToMap(session *entity.Session) gin.H {
 	tasksList := make([]gin.H, 0, len(session.Tasks))
-	for _, task := range session.Tasks {
-		taskMap := gin.H{
-			"id":        task.ID,
-			"title":     task.Title,
-			"completed": task.Completed,
-			"createdAt": task.CreatedAt.Format(time.RFC3339),
-		}
-		if task.CompletedAt != nil {
-			taskMap["completedAt"] = task.CompletedAt.Format(time.RFC3339)
-		}
-		tasksList = append(tasksList, taskMap)
+	for i := range session.Tasks {
+		tasksList = append(tasksList, taskToMap(&session.Tasks[i]))
 	}
 
 	participantsList := make([]gin.H, 0, len(session.Participants))
@@ -593,43 +1010,166 @@ func (h *SessionHandler) sessionToMap(session *entity.Session) gin.H {
 		if p.AvatarURL != nil {
 			participantMap["avatarUrl"] = *p.AvatarURL
 		}
+		if p.LeftAt != nil {
+			participantMap["leftAt"] = p.LeftAt.Format(time.RFC3339)
+		}
+		if p.BannedAt != nil {
+			participantMap["bannedAt"] = p.BannedAt.Format(time.RFC3339)
+		}
+		if p.IsCoHost {
+			participantMap["isCoHost"] = true
+		}
 		participantsList = append(participantsList, participantMap)
 	}
 
 	sessionMap := gin.H{
-		"id":            session.ID,
-		"mode":          session.Mode,
-		"status":        session.Status,
-		"tasks":         tasksList,
-		"focusDuration": session.FocusDuration,
-		"breakDuration": session.BreakDuration,
-		"isPrivate":     session.IsPrivate,
-		"creatorId":     session.CreatorID,
-		"participants":  participantsList,
-		"inviteLink":    session.InviteLink,
-		"createdAt":     session.CreatedAt.Format(time.RFC3339),
-		"currentCycle":  session.CurrentCycle,
+		"id":             session.ID,
+		"mode":           session.Mode,
+		"status":         session.Status,
+		"tasks":          tasksList,
+		"focusDuration":  session.FocusDuration,
+		"breakDuration":  session.BreakDuration,
+		"isPrivate":      session.IsPrivate,
+		"creatorId":      session.CreatorID,
+		"participants":   participantsList,
+		"inviteLink":     session.InviteLink,
+		"createdAt":      session.CreatedAt.Format(time.RFC3339),
+		"currentCycle":   session.CurrentCycle,
+		"totalPauseTime": session.TotalPauseTime,
 	}
 
 	if session.GroupName != nil {
 		sessionMap["groupName"] = *session.GroupName
 	}
+	sessionMap["plan"] = planToMap(session)
+	if session.RequireApproval {
+		sessionMap["requireApproval"] = true
+	}
+	if session.MaxParticipants > 0 {
+		sessionMap["maxParticipants"] = session.MaxParticipants
+	}
+	if session.SeriesID != nil {
+		sessionMap["seriesId"] = *session.SeriesID
+	}
+	if session.ScheduledAt != nil {
+		sessionMap["scheduledAt"] = session.ScheduledAt.Format(time.RFC3339)
+	}
+	if session.StartPolicy != "" && session.StartPolicy != entity.StartPolicyManual {
+		sessionMap["startPolicy"] = session.StartPolicy
+		sessionMap["startQuorum"] = session.StartQuorum
+		sessionMap["startCountdown"] = int(session.CountdownDuration().Seconds())
+	}
+	if session.AutoStartAt != nil {
+		sessionMap["autoStartAt"] = session.AutoStartAt.Format(time.RFC3339)
+	}
 	if session.StartedAt != nil {
 		sessionMap["startedAt"] = session.StartedAt.Format(time.RFC3339)
 	}
 	if session.CompletedAt != nil {
 		sessionMap["completedAt"] = session.CompletedAt.Format(time.RFC3339)
 	}
+	if session.PausedAt != nil {
+		sessionMap["pausedAt"] = session.PausedAt.Format(time.RFC3339)
+	}
 	if session.TelegramChatID != nil {
 		sessionMap["telegramChatId"] = *session.TelegramChatID
 	}
 	if session.TelegramChatLink != nil {
 		sessionMap["telegramChatLink"] = *session.TelegramChatLink
 	}
+	if session.CurrentPhase != "" {
+		sessionMap["phase"] = phaseToMap(session)
+	}
 
 	return sessionMap
 }
 
+// phaseToMap описывает текущую фазу сессии; serverTime нужен клиентам для синхронизации часов
+func phaseToMap(session *entity.Session) gin.H {
+	phaseMap := gin.H{
+		"phase":        session.CurrentPhase,
+		"currentCycle": session.CurrentCycle,
+		"phaseIndex":   session.PhaseIndex,
+		"serverTime":   time.Now().Format(time.RFC3339),
+	}
+	if cycles := session.PlannedCycles(); cycles > 0 {
+		phaseMap["totalCycles"] = cycles
+	}
+	if next, ok := session.PlannedPhaseAt(session.PhaseIndex + 1); ok {
+		phaseMap["nextPhase"] = next
+	}
+	if session.PhaseStartedAt != nil {
+		phaseMap["phaseStartedAt"] = session.PhaseStartedAt.Format(time.RFC3339)
+	}
+	if session.PhaseEndsAt != nil {
+		phaseMap["phaseEndsAt"] = session.PhaseEndsAt.Format(time.RFC3339)
+	}
+	return phaseMap
+}
+
+// planToMap описывает план циклов сессии; phases — весь план по фазам, если он конечен
+func planToMap(session *entity.Session) gin.H {
+	planMap := gin.H{
+		"cycles": session.PlannedCycles(),
+	}
+	if session.LongBreak > 0 {
+		planMap["longBreak"] = session.LongBreak
+		planMap["longBreakEvery"] = session.LongBreakEvery
+	}
+	if phases := session.PlannedPhases(); phases != nil {
+		planMap["phases"] = phases
+	}
+	return planMap
+}
+
+func taskToMap(task *entity.Task) gin.H {
+	taskMap := gin.H{
+		"id":        task.ID,
+		"title":     task.Title,
+		"completed": task.Completed,
+		"createdAt": task.CreatedAt.Format(time.RFC3339),
+	}
+	if task.CompletedAt != nil {
+		taskMap["completedAt"] = task.CompletedAt.Format(time.RFC3339)
+	}
+	return taskMap
+}
+
+// participantReadyEvent payload события participant_ready (одинаковый для REST и WebSocket)
+func participantReadyEvent(sessionID string, userID string, isReady bool) gin.H {
+	return gin.H{
+		"sessionId": sessionID,
+		"userId":    userID,
+		"isReady":   isReady,
+	}
+}
+
+// ownershipTransferredEvent payload события ownership_transferred
+func ownershipTransferredEvent(sessionID string, previousCreatorID string, creatorID string, reason string) gin.H {
+	return gin.H{
+		"sessionId":         sessionID,
+		"previousCreatorId": previousCreatorID,
+		"creatorId":         creatorID,
+		"reason":            reason,
+	}
+}
+
+// sessionStatusEvent payload событий session_paused/session_resumed
+func sessionStatusEvent(session *entity.Session) gin.H {
+	data := gin.H{
+		"sessionId":      session.ID,
+		"status":         session.Status,
+		"totalPauseTime": session.TotalPauseTime,
+	}
+	if session.PausedAt != nil {
+		data["pausedAt"] = session.PausedAt.Format(time.RFC3339)
+	}
+	if session.CurrentPhase != "" {
+		data["phase"] = phaseToMap(session)
+	}
+	return data
+}
+
 func (h *SessionHandler) buildReportResponse(report *entity.SessionReport) gin.H {
 	participantsList := make([]gin.H, 0, len(report.Participants))
 	for _, p := range report.Participants {
@@ -637,6 +1177,7 @@ func (h *SessionHandler) buildReportResponse(report *entity.SessionReport) gin.H
 			"userId":         p.UserID,
 			"userName":       p.UserName,
 			"tasksCompleted": p.TasksCompleted,
+			"tasksTotal":     p.TasksTotal,
 			"focusTime":      p.FocusTime,
 		}
 		if p.AvatarURL != nil {
@@ -654,6 +1195,8 @@ func (h *SessionHandler) buildReportResponse(report *entity.SessionReport) gin.H
 		"focusTime":       report.FocusTime,
 		"breakTime":       report.BreakTime,
 		"cyclesCompleted": report.CyclesCompleted,
+		"cyclesPlanned":   report.CyclesPlanned,
+		"planCompleted":   report.PlanCompleted,
 		"participants":    participantsList,
 		"completedAt":     completedAt,
 	}
@@ -680,17 +1223,16 @@ func (h *SessionHandler) addTask(c *gin.Context) {
 
 	task, err := h.sessionService.AddTask(sessionID, userID, req.Title)
 	if err != nil {
-		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	if h.wsHandler != nil {
+		h.wsHandler.BroadcastProgress(sessionID, userID)
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
-		"task": gin.H{
-			"id":        task.ID,
-			"title":     task.Title,
-			"completed": task.Completed,
-			"createdAt": task.CreatedAt.Format(time.RFC3339),
-		},
+		"task": taskToMap(task),
 	})
 }
 
@@ -716,26 +1258,16 @@ func (h *SessionHandler) updateTask(c *gin.Context) {
 
 	task, err := h.sessionService.UpdateTask(sessionID, taskID, userID, req.Completed)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	taskMap := gin.H{
-		"id":        task.ID,
-		"title":     task.Title,
-		"completed": task.Completed,
-		"createdAt": task.CreatedAt.Format(time.RFC3339),
-	}
-	if task.CompletedAt != nil {
-		taskMap["completedAt"] = task.CompletedAt.Format(time.RFC3339)
+	if h.wsHandler != nil {
+		h.wsHandler.BroadcastProgress(sessionID, userID)
 	}
 
 	h.SuccessResponse(c, http.StatusOK, gin.H{
-		"task": taskMap,
+		"task": taskToMap(task),
 	})
 }
 
@@ -751,14 +1283,14 @@ func (h *SessionHandler) deleteTask(c *gin.Context) {
 	taskID := c.Param("taskId")
 
 	if err := h.sessionService.DeleteTask(sessionID, taskID, userID); err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	if h.wsHandler != nil {
+		h.wsHandler.BroadcastProgress(sessionID, userID)
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
@@ -815,11 +1347,7 @@ func (h *SessionHandler) getMessages(c *gin.Context) {
 
 	messages, err := h.messageService.GetMessages(sessionID, userID, before, limit)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "chat") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -864,11 +1392,7 @@ func (h *SessionHandler) sendMessage(c *gin.Context) {
 
 	message, err := h.messageService.SendMessage(sessionID, userID, req.Text)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "chat") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -900,11 +1424,7 @@ func (h *SessionHandler) getChatInfo(c *gin.Context) {
 
 	chatInfo, err := h.messageService.GetChatInfo(sessionID, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "chat") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -927,13 +1447,7 @@ func (h *SessionHandler) deleteChat(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 
 	if err := h.sessionService.DeleteChatAfterDiscussion(sessionID, userID); err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.ErrorResponse(c, http.StatusNotFound, err.Error())
-		} else if strings.Contains(err.Error(), "only creator") {
-			h.ErrorResponse(c, http.StatusForbidden, err.Error())
-		} else {
-			h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -952,7 +1466,7 @@ func (h *SessionHandler) getSessionLeaderboard(c *gin.Context) {
 
 	entries, err := h.leaderboardService.GetSessionLeaderboard(sessionID, userID)
 	if err != nil {
-		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -965,6 +1479,7 @@ func (h *SessionHandler) getSessionLeaderboard(c *gin.Context) {
 			"tasksCompleted": entry.TasksCompleted,
 			"focusTime":      entry.FocusTime,
 			"score":          entry.Score,
+			"breakdown":      scoreBreakdownToMap(entry.Breakdown),
 		}
 		if entry.AvatarURL != nil {
 			entryMap["avatarUrl"] = *entry.AvatarURL
@@ -1004,21 +1519,70 @@ func (h *SessionHandler) getGlobalLeaderboard(c *gin.Context) {
 
 	entriesList := make([]gin.H, 0, len(entries))
 	for _, entry := range entries {
-		entryMap := gin.H{
-			"rank":           entry.Rank,
-			"userId":         entry.UserID,
-			"userName":       entry.UserName,
-			"tasksCompleted": entry.TasksCompleted,
-			"focusTime":      entry.FocusTime,
-			"score":          entry.Score,
-		}
-		if entry.AvatarURL != nil {
-			entryMap["avatarUrl"] = *entry.AvatarURL
+		entriesList = append(entriesList, leaderboardEntryToMap(entry))
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"leaderboard": entriesList,
+	})
+}
+
+// getFriendsLeaderboard возвращает лидерборд пользователя среди его контактов
+func (h *SessionHandler) getFriendsLeaderboard(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	period := entity.LeaderboardPeriod(c.DefaultQuery("period", "week"))
+
+	entries, err := h.leaderboardService.GetFriendsLeaderboard(userID, period)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	myRank := 0
+	entriesList := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		if entry.UserID == userID {
+			myRank = entry.Rank
 		}
-		entriesList = append(entriesList, entryMap)
+		entriesList = append(entriesList, leaderboardEntryToMap(entry))
 	}
 
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"leaderboard": entriesList,
+		"myRank":      myRank,
 	})
 }
+
+// leaderboardEntryToMap конвертирует запись лидерборда в map для JSON ответа
+func leaderboardEntryToMap(entry *entity.LeaderboardEntry) gin.H {
+	entryMap := gin.H{
+		"rank":              entry.Rank,
+		"userId":            entry.UserID,
+		"userName":          entry.UserName,
+		"sessionsCompleted": entry.SessionsCompleted,
+		"tasksCompleted":    entry.TasksCompleted,
+		"focusTime":         entry.FocusTime,
+		"currentStreak":     entry.CurrentStreak,
+		"score":             entry.Score,
+		"breakdown":         scoreBreakdownToMap(entry.Breakdown),
+	}
+	if entry.AvatarURL != nil {
+		entryMap["avatarUrl"] = *entry.AvatarURL
+	}
+	return entryMap
+}
+
+// scoreBreakdownToMap конвертирует разбивку счета в map для JSON ответа
+func scoreBreakdownToMap(breakdown entity.ScoreBreakdown) gin.H {
+	return gin.H{
+		"strategy":    breakdown.Strategy,
+		"taskPoints":  breakdown.TaskPoints,
+		"focusPoints": breakdown.FocusPoints,
+		"multiplier":  breakdown.Multiplier,
+	}
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+)
+
+func TestCreateSessionRequestSettings(t *testing.T) {
+	groupName := "Reading"
+	preset := entity.SessionSettings{
+		Mode:          entity.SessionModeGroup,
+		Tasks:         []string{"chapter 1"},
+		FocusDuration: 45,
+		BreakDuration: 15,
+		GroupName:     &groupName,
+		IsPrivate:     true,
+		Plan:          entity.CyclePlan{Cycles: 4, LongBreak: 30, LongBreakEvery: 2},
+	}
+	phases := entity.PhasePlan{{Phase: entity.SessionPhaseFocus, Duration: 90}}
+
+	tests := []struct {
+		name string
+		body string
+		want func(s *entity.SessionSettings)
+	}{
+		{name: "preset as is", body: `{"presetId": "p"}`, want: func(s *entity.SessionSettings) {}},
+		{name: "durations override", body: `{"presetId": "p", "focusDuration": 25, "breakDuration": 5}`, want: func(s *entity.SessionSettings) {
+			s.FocusDuration = 25
+			s.BreakDuration = 5
+		}},
+		{name: "empty task list overrides", body: `{"presetId": "p", "tasks": []}`, want: func(s *entity.SessionSettings) { s.Tasks = []string{} }},
+		{name: "false overrides privacy", body: `{"presetId": "p", "isPrivate": false}`, want: func(s *entity.SessionSettings) { s.IsPrivate = false }},
+		{name: "cycles keep the rest of the plan", body: `{"presetId": "p", "cycles": 2}`, want: func(s *entity.SessionSettings) { s.Plan.Cycles = 2 }},
+		{name: "phases replace the plan", body: `{"presetId": "p", "phases": [{"phase": "focus", "duration": 90}]}`, want: func(s *entity.SessionSettings) {
+			s.Plan = entity.CyclePlan{Phases: phases}
+		}},
+		{name: "start settings are never taken from the preset", body: `{"presetId": "p", "startPolicy": "quorum", "startQuorum": 3, "maxParticipants": 5}`, want: func(s *entity.SessionSettings) {
+			s.StartPolicy = entity.StartPolicyQuorum
+			s.StartQuorum = 3
+			s.Capacity = 5
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req createSessionRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			want := preset
+			want.Tasks = append([]string(nil), preset.Tasks...)
+			tt.want(&want)
+
+			if got := req.settings(preset); !reflect.DeepEqual(got, want) {
+				t.Errorf("settings() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCreateSessionRequestMissingField(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: `{"mode": "solo", "tasks": [], "focusDuration": 25, "breakDuration": 5}`, want: ""},
+		{body: `{"tasks": [], "focusDuration": 25, "breakDuration": 5}`, want: "mode"},
+		{body: `{"mode": "solo", "focusDuration": 25, "breakDuration": 5}`, want: "tasks"},
+		{body: `{"mode": "solo", "tasks": [], "focusDuration": 0, "breakDuration": 5}`, want: "focusDuration"},
+		{body: `{"mode": "solo", "tasks": [], "focusDuration": 25}`, want: "breakDuration"},
+	}
+
+	for _, tt := range tests {
+		var req createSessionRequest
+		if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+			t.Fatalf("unmarshal %s: %v", tt.body, err)
+		}
+		if got := req.missingField(); got != tt.want {
+			t.Errorf("missingField(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -2,20 +2,24 @@ package v1
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rnegic/synchronous/internal/entity"
 	"github.com/rnegic/synchronous/internal/interfaces"
 )
 
 type UserHandler struct {
 	*BaseHandler
-	userService interfaces.UserService
+	userService   interfaces.UserService
+	presetService interfaces.SessionPresetService
 }
 
-func NewUserHandler(baseHandler *BaseHandler, userService interfaces.UserService) *UserHandler {
+func NewUserHandler(baseHandler *BaseHandler, userService interfaces.UserService, presetService interfaces.SessionPresetService) *UserHandler {
 	return &UserHandler{
-		BaseHandler: baseHandler,
-		userService: userService,
+		BaseHandler:   baseHandler,
+		userService:   userService,
+		presetService: presetService,
 	}
 }
 
@@ -23,7 +27,19 @@ func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
 		users.GET("/me", h.getMe)
+		users.PATCH("/me/settings", h.updateSettings)
+		users.GET("/me/analytics", h.getAnalytics)
 		users.GET("/contacts", h.getContacts)
+		users.POST("/contacts", h.followUser)
+		users.DELETE("/contacts/:contactId", h.removeContact)
+		users.GET("/me/invite", h.getInvite)
+
+		// Личные пресеты сессий
+		users.GET("/me/presets", h.getPresets)
+		users.POST("/me/presets", h.createPreset)
+		users.GET("/me/presets/:presetId", h.getPreset)
+		users.PUT("/me/presets/:presetId", h.updatePreset)
+		users.DELETE("/me/presets/:presetId", h.deletePreset)
 	}
 }
 
@@ -40,16 +56,128 @@ func (h *UserHandler) getMe(c *gin.Context) {
 		return
 	}
 
+	achievements, err := h.userService.GetAchievements(userID)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	badges := make([]gin.H, 0, len(achievements))
+	for _, achievement := range achievements {
+		badges = append(badges, achievementToMap(achievement))
+	}
+
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"id":        user.ID,
 		"name":      user.Name,
 		"avatarUrl": user.AvatarURL,
+		"timezone":  user.Timezone,
 		"stats": gin.H{
 			"totalSessions":  stats.TotalSessions,
 			"totalFocusTime": stats.TotalFocusTime,
 			"currentStreak":  stats.CurrentStreak,
 		},
-		"createdAt": user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		"achievements": badges,
+		"createdAt":    user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// updateSettings меняет настройки пользователя
+func (h *UserHandler) updateSettings(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		Timezone *string `json:"timezone"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdateSettings(userID, entity.UserSettings{Timezone: req.Timezone})
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"settings": gin.H{
+			"timezone": user.Timezone,
+		},
+	})
+}
+
+// getAnalytics возвращает личную аналитику фокуса за период: ?from=YYYY-MM-DD&to=YYYY-MM-DD&granularity=day|week
+func (h *UserHandler) getAnalytics(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := entity.AnalyticsQuery{
+		Granularity: entity.AnalyticsGranularity(c.DefaultQuery("granularity", string(entity.AnalyticsGranularityDay))),
+	}
+	for param, date := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(analyticsDateLayout, value)
+		if err != nil {
+			h.ErrorResponse(c, http.StatusBadRequest, "invalid "+param+" date, expected YYYY-MM-DD")
+			return
+		}
+		*date = parsed
+	}
+
+	analytics, err := h.userService.GetAnalytics(userID, query)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	buckets := make([]gin.H, 0, len(analytics.Buckets))
+	for _, bucket := range analytics.Buckets {
+		data := focusSummaryToMap(bucket.FocusSummary)
+		data["start"] = bucket.Start.Format(analyticsDateLayout)
+		buckets = append(buckets, data)
+	}
+
+	// Самый продуктивный час — с наибольшим фокусом; nil, если сессий не было
+	hours := make([]gin.H, 0, len(analytics.Hours))
+	var peakHour *int
+	for hour, focusTime := range analytics.Hours {
+		hours = append(hours, gin.H{"hour": hour, "focusTime": focusTime})
+		if focusTime > 0 && (peakHour == nil || focusTime > analytics.Hours[*peakHour]) {
+			peak := hour
+			peakHour = &peak
+		}
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"from":        analytics.From.Format(analyticsDateLayout),
+		"to":          analytics.To.Format(analyticsDateLayout),
+		"granularity": analytics.Granularity,
+		"buckets":     buckets,
+		"summary":     focusSummaryToMap(analytics.Summary),
+		"modes": gin.H{
+			"solo":  focusSummaryToMap(analytics.Solo),
+			"group": focusSummaryToMap(analytics.Group),
+		},
+		"hours":    hours,
+		"peakHour": peakHour,
+		"comparison": gin.H{
+			"previous":            focusSummaryToMap(analytics.Previous),
+			"focusTimeDelta":      analytics.Summary.FocusTime - analytics.Previous.FocusTime,
+			"sessionsDelta":       analytics.Summary.Sessions - analytics.Previous.Sessions,
+			"tasksCompletedDelta": analytics.Summary.TasksCompleted - analytics.Previous.TasksCompleted,
+			"completionRateDelta": analytics.Summary.CompletionRate() - analytics.Previous.CompletionRate(),
+		},
 	})
 }
 
@@ -68,15 +196,115 @@ func (h *UserHandler) getContacts(c *gin.Context) {
 
 	contactsList := make([]gin.H, 0, len(contacts))
 	for _, contact := range contacts {
-		contactsList = append(contactsList, gin.H{
-			"id":           contact.ID,
-			"name":         contact.Name,
-			"avatarUrl":    contact.AvatarURL,
-			"isRegistered": true, // В реальности нужно проверить
-		})
+		contactsList = append(contactsList, contactToMap(contact))
 	}
 
 	h.SuccessResponse(c, http.StatusOK, gin.H{
 		"contacts": contactsList,
 	})
 }
+
+// followUser добавляет пользователя в контакты по Telegram ID или коду приглашения
+func (h *UserHandler) followUser(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		TelegramUserID int64  `json:"telegramUserId"`
+		InviteCode     string `json:"inviteCode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	contact, err := h.userService.FollowUser(userID, entity.FollowTarget{
+		TelegramUserID: req.TelegramUserID,
+		InviteCode:     req.InviteCode,
+	})
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"contact": contactToMap(contact),
+	})
+}
+
+// removeContact удаляет контакт
+func (h *UserHandler) removeContact(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.userService.RemoveContact(userID, c.Param("contactId")); err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getInvite возвращает личный код приглашения в контакты
+func (h *UserHandler) getInvite(c *gin.Context) {
+	userID := h.GetUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	inviteCode, err := h.userService.GetInviteCode(userID)
+	if err != nil {
+		h.DomainErrorResponse(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	h.SuccessResponse(c, http.StatusOK, gin.H{
+		"inviteCode": inviteCode,
+		"startParam": entity.ContactInvitePrefix + inviteCode,
+	})
+}
+
+// contactToMap конвертирует контакт в map для JSON ответа
+func contactToMap(contact *entity.UserContact) gin.H {
+	return gin.H{
+		"id":           contact.Contact.ID,
+		"name":         contact.Contact.Name,
+		"avatarUrl":    contact.Contact.AvatarURL,
+		"isRegistered": true,
+		"source":       contact.Source,
+		"since":        contact.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// analyticsDateLayout — формат дней периода аналитики
+const analyticsDateLayout = "2006-01-02"
+
+// focusSummaryToMap конвертирует итоги сессий за период в map для JSON ответа
+func focusSummaryToMap(summary entity.FocusSummary) gin.H {
+	return gin.H{
+		"sessions":       summary.Sessions,
+		"focusTime":      summary.FocusTime,
+		"tasksCompleted": summary.TasksCompleted,
+		"tasksTotal":     summary.TasksTotal,
+		"completionRate": summary.CompletionRate(),
+		"averageCycles":  summary.AverageCycles(),
+	}
+}
+
+// achievementToMap конвертирует значок в map для JSON ответа
+func achievementToMap(achievement *entity.UserAchievement) gin.H {
+	return gin.H{
+		"code":        achievement.Code,
+		"title":       achievement.Code.Title(),
+		"description": achievement.Code.Description(),
+		"sessionId":   achievement.SessionID,
+		"unlockedAt":  achievement.UnlockedAt.Format(time.RFC3339),
+	}
+}
@@ -15,6 +15,7 @@ type WebhookHandler struct {
 	sessionService     interfaces.SessionService
 	telegramAPIService interfaces.TelegramAPIService
 	authService        interfaces.AuthService
+	wsHandler          *WebSocketHandler
 }
 
 const welcomeMessage = `👋 Привет! Это бот Синхрон - твой помощник для фокус-сессий и синхронной работы с командой.
@@ -43,12 +44,13 @@ const restartMessage = `🔄 Авторизация сброшена!
 
 Если возникли проблемы, напиши /start для получения помощи.`
 
-func NewWebhookHandler(baseHandler *BaseHandler, sessionService interfaces.SessionService, telegramAPIService interfaces.TelegramAPIService, authService interfaces.AuthService) *WebhookHandler {
+func NewWebhookHandler(baseHandler *BaseHandler, sessionService interfaces.SessionService, telegramAPIService interfaces.TelegramAPIService, authService interfaces.AuthService, wsHandler *WebSocketHandler) *WebhookHandler {
 	return &WebhookHandler{
 		BaseHandler:        baseHandler,
 		sessionService:     sessionService,
 		telegramAPIService: telegramAPIService,
 		authService:        authService,
+		wsHandler:          wsHandler,
 	}
 }
 
@@ -109,6 +111,25 @@ func (h *WebhookHandler) handleWebhook(c *gin.Context) {
 		log.Printf("[Webhook] ✅ Chat created successfully: chatID=%d", u.Chat.ChatID)
 		h.SuccessResponse(c, http.StatusOK, gin.H{"status": "processed"})
 
+	case *telegramapi.MessageCallbackUpdate:
+		// Нажатие на кнопку: пока это только "принять"/"отклонить" под заявкой на вход
+		log.Printf("[Webhook] 🔘 Received callback from user=%d payload=%q", u.Callback.User.UserID, u.Callback.Payload)
+
+		decision, err := h.sessionService.HandleJoinRequestCallback(update)
+		if err != nil {
+			// Ведущий уже получил ответ на нажатие; повторять доставку webhook'а бессмысленно
+			log.Printf("[Webhook] ⚠️ Failed to handle callback: %v", err)
+			h.SuccessResponse(c, http.StatusOK, gin.H{"status": "rejected"})
+			return
+		}
+		if decision == nil {
+			h.SuccessResponse(c, http.StatusOK, gin.H{"status": "ignored"})
+			return
+		}
+
+		announceJoinDecision(h.wsHandler, decision)
+		h.SuccessResponse(c, http.StatusOK, gin.H{"status": "processed"})
+
 	default:
 		// Другие типы обновлений пока не обрабатываем
 		log.Printf("[Webhook] Received unhandled update type: %T", update)
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wsCommand is an inbound command sent over an open socket:
+//
+//	{"id": "req-1", "command": "set_ready", "payload": {"sessionId": "...", "isReady": true}}
+//
+// Every command is answered with an "ack" or "command_error" event carrying the same id
+type wsCommand struct {
+	ID      string          `json:"id"`
+	Command string          `json:"command"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type wsCommandAck struct {
+	ID      string      `json:"id"`
+	Command string      `json:"command"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+type wsCommandError struct {
+	ID      string `json:"id"`
+	Command string `json:"command"`
+	Error   string `json:"error"`
+}
+
+// wsCommandHandler executes a command on behalf of the user and returns the ack result
+type wsCommandHandler func(h *WebSocketHandler, userID string, payload json.RawMessage) (interface{}, error)
+
+var wsCommandHandlers = map[string]wsCommandHandler{
+	"set_ready":   (*WebSocketHandler).commandSetReady,
+	"pause":       (*WebSocketHandler).commandPause,
+	"resume":      (*WebSocketHandler).commandResume,
+	"toggle_task": (*WebSocketHandler).commandToggleTask,
+	"add_task":    (*WebSocketHandler).commandAddTask,
+}
+
+func (h *WebSocketHandler) handleCommand(client *wsClient, cmd wsCommand) {
+	handler, ok := wsCommandHandlers[cmd.Command]
+	if !ok {
+		h.sendToClient(client, "command_error", wsCommandError{
+			ID:      cmd.ID,
+			Command: cmd.Command,
+			Error:   "unknown command",
+		})
+		return
+	}
+
+	result, err := handler(h, client.userID, cmd.Payload)
+	if err != nil {
+		log.Printf("[WebSocket] ❌ Command %s from %s failed: %v\n", cmd.Command, client.userID, err)
+		h.sendToClient(client, "command_error", wsCommandError{
+			ID:      cmd.ID,
+			Command: cmd.Command,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.sendToClient(client, "ack", wsCommandAck{
+		ID:      cmd.ID,
+		Command: cmd.Command,
+		Result:  result,
+	})
+}
+
+func (h *WebSocketHandler) commandSetReady(userID string, payload json.RawMessage) (interface{}, error) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+		IsReady   *bool  `json:"isReady"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil || req.SessionID == "" || req.IsReady == nil {
+		return nil, fmt.Errorf("sessionId and isReady are required")
+	}
+
+	if err := h.sessionService.SetReady(req.SessionID, userID, *req.IsReady); err != nil {
+		return nil, err
+	}
+
+	h.SendToSession(req.SessionID, "participant_ready", participantReadyEvent(req.SessionID, userID, *req.IsReady))
+
+	return gin.H{"sessionId": req.SessionID, "isReady": *req.IsReady}, nil
+}
+
+func (h *WebSocketHandler) commandPause(userID string, payload json.RawMessage) (interface{}, error) {
+	sessionID, err := parseSessionIDPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.sessionService.PauseSession(sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	return h.sessionStatusChanged(sessionID, userID, "session_paused")
+}
+
+func (h *WebSocketHandler) commandResume(userID string, payload json.RawMessage) (interface{}, error) {
+	sessionID, err := parseSessionIDPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.sessionService.ResumeSession(sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	return h.sessionStatusChanged(sessionID, userID, "session_resumed")
+}
+
+func (h *WebSocketHandler) commandToggleTask(userID string, payload json.RawMessage) (interface{}, error) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+		TaskID    string `json:"taskId"`
+		Completed *bool  `json:"completed"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil || req.SessionID == "" || req.TaskID == "" || req.Completed == nil {
+		return nil, fmt.Errorf("sessionId, taskId and completed are required")
+	}
+
+	task, err := h.sessionService.UpdateTask(req.SessionID, req.TaskID, userID, *req.Completed)
+	if err != nil {
+		return nil, err
+	}
+
+	h.BroadcastProgress(req.SessionID, userID)
+
+	return gin.H{"task": taskToMap(task)}, nil
+}
+
+func (h *WebSocketHandler) commandAddTask(userID string, payload json.RawMessage) (interface{}, error) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+		Title     string `json:"title"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil || req.SessionID == "" || req.Title == "" {
+		return nil, fmt.Errorf("sessionId and title are required")
+	}
+
+	task, err := h.sessionService.AddTask(req.SessionID, userID, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	h.BroadcastProgress(req.SessionID, userID)
+
+	return gin.H{"task": taskToMap(task)}, nil
+}
+
+// sessionStatusChanged рассылает участникам новое состояние сессии после паузы/возобновления
+func (h *WebSocketHandler) sessionStatusChanged(sessionID string, userID string, event string) (interface{}, error) {
+	session, err := h.sessionService.GetSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := sessionStatusEvent(session)
+	h.SendToSession(sessionID, event, data)
+
+	return data, nil
+}
+
+// BroadcastProgress рассылает участникам актуальный прогресс по задачам (без названий задач)
+func (h *WebSocketHandler) BroadcastProgress(sessionID string, userID string) {
+	progress, err := h.sessionService.GetParticipantsProgress(sessionID, userID)
+	if err != nil {
+		log.Printf("[WebSocket] Failed to load participants progress for session %s: %v\n", sessionID, err)
+		return
+	}
+
+	h.SendToSession(sessionID, "participant_progress", gin.H{
+		"sessionId": sessionID,
+		"progress":  progress,
+	})
+}
+
+func parseSessionIDPayload(payload json.RawMessage) (string, error) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil || req.SessionID == "" {
+		return "", fmt.Errorf("sessionId is required")
+	}
+	return req.SessionID, nil
+}
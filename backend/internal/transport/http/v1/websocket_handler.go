@@ -9,6 +9,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/interfaces"
 )
 
 var upgrader = websocket.Upgrader{
@@ -21,20 +23,75 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = 30 * time.Second
+	wsSendBufferSize = 64
+
+	// Per-session replay buffer for clients that reconnect after a drop
+	wsEventBufferSize = 100
+	wsEventLogTTL     = time.Hour
+)
+
+// wsClient is a single WebSocket connection. All writes go through send,
+// so writePump is the only goroutine writing to conn
+type wsClient struct {
+	conn     *websocket.Conn
+	userID   string
+	send     chan []byte
+	sessions map[string]bool // rooms this client is subscribed to, guarded by WebSocketHandler.mu
+}
+
+// sessionEventLog numbers a session's events and keeps the most recent ones for replay
+type sessionEventLog struct {
+	seq         int64
+	events      []sequencedEvent // oldest first, at most wsEventBufferSize
+	lastEventAt time.Time
+}
+
+type sequencedEvent struct {
+	seq     int64
+	message []byte
+}
+
+// sessionEventEnvelope is the wire format of session-scoped events
+type sessionEventEnvelope struct {
+	Event     string          `json:"event"`
+	SessionID string          `json:"sessionId"`
+	Seq       int64           `json:"seq"`
+	Data      json.RawMessage `json:"data"`
+}
+
 type WebSocketHandler struct {
 	*BaseHandler
-	clients   map[*websocket.Conn]string // conn -> userID
-	broadcast chan []byte
-	mu        sync.RWMutex
+	sessionService interfaces.SessionService
+	clients        map[*wsClient]bool
+	rooms          map[string]map[*wsClient]bool // sessionID -> subscribed clients
+	eventLogs      map[string]*sessionEventLog   // sessionID -> sequenced events
+	backplane      interfaces.EventBackplane
+	broadcast      chan []byte
+	mu             sync.RWMutex
 }
 
-func NewWebSocketHandler(baseHandler *BaseHandler) *WebSocketHandler {
+func NewWebSocketHandler(
+	baseHandler *BaseHandler,
+	sessionService interfaces.SessionService,
+	backplane interfaces.EventBackplane,
+) *WebSocketHandler {
 	handler := &WebSocketHandler{
-		BaseHandler: baseHandler,
-		clients:     make(map[*websocket.Conn]string),
-		broadcast:   make(chan []byte, 256),
+		BaseHandler:    baseHandler,
+		sessionService: sessionService,
+		clients:        make(map[*wsClient]bool),
+		rooms:          make(map[string]map[*wsClient]bool),
+		eventLogs:      make(map[string]*sessionEventLog),
+		backplane:      backplane,
+		broadcast:      make(chan []byte, 256),
 	}
 
+	// Events published by any node (this one included) reach local sockets through the backplane
+	backplane.Subscribe(handler.deliver)
+
 	// Start broadcast goroutine
 	go handler.handleBroadcasts()
 
@@ -61,28 +118,38 @@ func (h *WebSocketHandler) handleWebSocket(c *gin.Context) {
 		return
 	}
 
+	client := &wsClient{
+		conn:     conn,
+		userID:   userID,
+		send:     make(chan []byte, wsSendBufferSize),
+		sessions: make(map[string]bool),
+	}
+
 	// Register client
 	h.mu.Lock()
-	h.clients[conn] = userID
+	h.clients[client] = true
+	clientCount := len(h.clients)
 	h.mu.Unlock()
 
-	log.Printf("[WebSocket] ✅ Client connected: userID=%s, total=%d\n", userID, len(h.clients))
+	go h.writePump(client)
+
+	log.Printf("[WebSocket] ✅ Client connected: userID=%s, total=%d\n", userID, clientCount)
 
 	// Handle client disconnect
 	defer func() {
-		h.mu.Lock()
-		delete(h.clients, conn)
-		clientCount := len(h.clients)
-		h.mu.Unlock()
-
-		conn.Close()
-		log.Printf("[WebSocket] 🔌 Client disconnected: userID=%s, total=%d\n", userID, clientCount)
+		h.unregister(client)
+		log.Printf("[WebSocket] 🔌 Client disconnected: userID=%s\n", userID)
 	}()
 
+	// Auto-subscribe to the user's active session, if any
+	if session, err := h.sessionService.GetActiveSession(userID); err == nil && session != nil {
+		h.subscribe(client, session.ID)
+	}
+
 	// Configure connection
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
 	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
 		return nil
 	})
 
@@ -96,116 +163,498 @@ func (h *WebSocketHandler) handleWebSocket(c *gin.Context) {
 			break
 		}
 
-		// Parse message
-		var msg map[string]interface{}
+		// Parse message: either a control event ({"event": ...}) or a command envelope ({"command": ...})
+		var msg struct {
+			Event string          `json:"event"`
+			Data  json.RawMessage `json:"data"`
+			wsCommand
+		}
 		if err := json.Unmarshal(message, &msg); err != nil {
 			log.Printf("[WebSocket] Failed to parse message: %v\n", err)
 			continue
 		}
 
-		// Handle ping
-		if event, ok := msg["event"].(string); ok && event == "ping" {
-			h.sendToClient(conn, map[string]interface{}{
-				"event": "pong",
-				"data":  map[string]interface{}{},
-			})
+		if msg.Command != "" {
+			h.handleCommand(client, msg.wsCommand)
 			continue
 		}
 
-		log.Printf("[WebSocket] 📨 Received from %s: %v\n", userID, msg)
+		switch msg.Event {
+		case "ping":
+			h.sendToClient(client, "pong", map[string]interface{}{})
+		case "subscribe":
+			h.handleSubscribe(client, msg.Data)
+		case "unsubscribe":
+			h.handleUnsubscribe(client, msg.Data)
+		case "resume":
+			h.handleResume(client, msg.Data)
+		default:
+			log.Printf("[WebSocket] 📨 Received from %s: %s\n", userID, string(message))
+		}
 	}
 }
 
-// Send message to specific client
-func (h *WebSocketHandler) sendToClient(conn *websocket.Conn, message map[string]interface{}) error {
-	data, err := json.Marshal(message)
-	if err != nil {
-		return err
+// handleSubscribe adds the client to a session room after checking membership
+func (h *WebSocketHandler) handleSubscribe(client *wsClient, data json.RawMessage) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.SessionID == "" {
+		h.sendToClient(client, "error", map[string]interface{}{"message": "sessionId is required"})
+		return
+	}
+
+	if !h.canSubscribe(client, req.SessionID) {
+		return
 	}
 
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return conn.WriteMessage(websocket.TextMessage, data)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.clients[client] {
+		return
+	}
+	h.addToRoom(client, req.SessionID)
+	h.enqueueEvent(client, "subscribed", map[string]interface{}{
+		"sessionId": req.SessionID,
+		"seq":       h.currentSeq(req.SessionID),
+	})
 }
 
-// Broadcast message to all clients
-func (h *WebSocketHandler) BroadcastMessage(event string, data interface{}) {
-	message := map[string]interface{}{
-		"event": event,
-		"data":  data,
+// handleResume re-subscribes a reconnected client and replays the events it missed.
+// If the missed events are no longer buffered the client is told to resync over REST
+func (h *WebSocketHandler) handleResume(client *wsClient, data json.RawMessage) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+		LastSeq   int64  `json:"lastSeq"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.SessionID == "" {
+		h.sendToClient(client, "error", map[string]interface{}{"message": "sessionId is required"})
+		return
+	}
+
+	if !h.canSubscribe(client, req.SessionID) {
+		return
+	}
+
+	// Subscribe and replay under the same lock so no event is lost or duplicated in between
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.clients[client] {
+		return
+	}
+	h.addToRoom(client, req.SessionID)
+
+	currentSeq := h.currentSeq(req.SessionID)
+	missed, ok := h.eventsSince(req.SessionID, req.LastSeq)
+	if !ok {
+		h.enqueueEvent(client, "resync_required", map[string]interface{}{
+			"sessionId": req.SessionID,
+			"seq":       currentSeq,
+		})
+		return
+	}
+
+	for _, event := range missed {
+		h.enqueue(client, event.message)
+	}
+	h.enqueueEvent(client, "resumed", map[string]interface{}{
+		"sessionId": req.SessionID,
+		"seq":       currentSeq,
+		"replayed":  len(missed),
+	})
+}
+
+// canSubscribe checks that the user takes part in the session and reports an error otherwise
+func (h *WebSocketHandler) canSubscribe(client *wsClient, sessionID string) bool {
+	session, err := h.sessionService.GetSession(sessionID, client.userID)
+	if err != nil || session == nil {
+		h.sendToClient(client, "error", map[string]interface{}{
+			"sessionId": sessionID,
+			"message":   "session not found",
+		})
+		return false
+	}
+
+	isParticipant := session.CreatorID == client.userID
+	for _, p := range session.Participants {
+		if p.UserID == client.userID && p.LeftAt == nil {
+			isParticipant = true
+			break
+		}
+	}
+	if !isParticipant {
+		h.sendToClient(client, "error", map[string]interface{}{
+			"sessionId": sessionID,
+			"message":   "only participants can subscribe to session events",
+		})
+		return false
+	}
+
+	return true
+}
+
+// currentSeq must be called with h.mu held
+func (h *WebSocketHandler) currentSeq(sessionID string) int64 {
+	if eventLog, ok := h.eventLogs[sessionID]; ok {
+		return eventLog.seq
+	}
+	return 0
+}
+
+// eventsSince returns buffered events after lastSeq; ok is false when some of
+// them were already evicted or the client is ahead of the server (e.g. after a restart).
+// Must be called with h.mu held
+func (h *WebSocketHandler) eventsSince(sessionID string, lastSeq int64) ([]sequencedEvent, bool) {
+	eventLog, exists := h.eventLogs[sessionID]
+	if !exists {
+		return nil, lastSeq == 0
+	}
+	if lastSeq > eventLog.seq {
+		return nil, false
+	}
+	if lastSeq == eventLog.seq {
+		return nil, true
+	}
+	if len(eventLog.events) == 0 || eventLog.events[0].seq > lastSeq+1 {
+		return nil, false
+	}
+
+	start := len(eventLog.events) - int(eventLog.seq-lastSeq)
+	return eventLog.events[start:], true
+}
+
+func (h *WebSocketHandler) handleUnsubscribe(client *wsClient, data json.RawMessage) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.SessionID == "" {
+		h.sendToClient(client, "error", map[string]interface{}{"message": "sessionId is required"})
+		return
+	}
+
+	h.unsubscribe(client, req.SessionID)
+	h.sendToClient(client, "unsubscribed", map[string]interface{}{"sessionId": req.SessionID})
+}
+
+func (h *WebSocketHandler) subscribe(client *wsClient, sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[client] {
+		h.addToRoom(client, sessionID)
+	}
+}
+
+func (h *WebSocketHandler) unsubscribe(client *wsClient, sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeFromRoom(client, sessionID)
+}
+
+// addToRoom must be called with h.mu held
+func (h *WebSocketHandler) addToRoom(client *wsClient, sessionID string) {
+	if h.rooms[sessionID] == nil {
+		h.rooms[sessionID] = make(map[*wsClient]bool)
+	}
+	h.rooms[sessionID][client] = true
+	client.sessions[sessionID] = true
+}
+
+// removeFromRoom must be called with h.mu held
+func (h *WebSocketHandler) removeFromRoom(client *wsClient, sessionID string) {
+	delete(client.sessions, sessionID)
+	if room, ok := h.rooms[sessionID]; ok {
+		delete(room, client)
+		if len(room) == 0 {
+			delete(h.rooms, sessionID)
+		}
+	}
+}
+
+func (h *WebSocketHandler) unregister(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.clients[client] {
+		return
+	}
+	for sessionID := range client.sessions {
+		h.removeFromRoom(client, sessionID)
+	}
+	delete(h.clients, client)
+	close(client.send)
+}
+
+// writePump delivers queued messages and keeps the connection alive with pings
+func (h *WebSocketHandler) writePump(client *wsClient) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		client.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				client.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := client.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				log.Printf("[WebSocket] Failed to send to user %s: %v\n", client.userID, err)
+				return
+			}
+
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				log.Printf("[WebSocket] Failed to send ping: %v\n", err)
+				return
+			}
+		}
+	}
+}
+
+// enqueue must be called with h.mu held (read lock is enough).
+// Slow clients whose buffer is full are dropped instead of blocking everyone else
+func (h *WebSocketHandler) enqueue(client *wsClient, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		log.Printf("[WebSocket] ⚠️ Send buffer full, dropping client: userID=%s\n", client.userID)
+		go h.unregister(client)
 	}
+}
 
-	msgBytes, err := json.Marshal(message)
+// Send message to specific client
+func (h *WebSocketHandler) sendToClient(client *wsClient, event string, data interface{}) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.clients[client] {
+		h.enqueueEvent(client, event, data)
+	}
+}
+
+// enqueueEvent must be called with h.mu held
+func (h *WebSocketHandler) enqueueEvent(client *wsClient, event string, data interface{}) {
+	msgBytes, err := marshalEvent(event, data)
 	if err != nil {
-		log.Printf("[WebSocket] Failed to marshal broadcast message: %v\n", err)
+		log.Printf("[WebSocket] Failed to marshal message: %v\n", err)
 		return
 	}
+	h.enqueue(client, msgBytes)
+}
 
-	h.broadcast <- msgBytes
+func marshalEvent(event string, data interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"event": event,
+		"data":  data,
+	})
 }
 
 // Handle broadcast messages
 func (h *WebSocketHandler) handleBroadcasts() {
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(wsEventLogTTL)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case message := <-h.broadcast:
 			h.mu.RLock()
-			for conn := range h.clients {
-				conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-				if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
-					log.Printf("[WebSocket] Failed to send broadcast: %v\n", err)
-					conn.Close()
-				}
+			for client := range h.clients {
+				h.enqueue(client, message)
 			}
 			h.mu.RUnlock()
 
 		case <-ticker.C:
-			// Send ping to all clients
-			h.mu.RLock()
-			for conn := range h.clients {
-				conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-					log.Printf("[WebSocket] Failed to send ping: %v\n", err)
-					conn.Close()
-				}
-			}
-			h.mu.RUnlock()
+			h.pruneEventLogs()
 		}
 	}
 }
 
-// Send message to specific user
+// pruneEventLogs drops replay buffers of sessions that have been quiet for a while
+func (h *WebSocketHandler) pruneEventLogs() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := time.Now().Add(-wsEventLogTTL)
+	for sessionID, eventLog := range h.eventLogs {
+		if eventLog.lastEventAt.Before(cutoff) && len(h.rooms[sessionID]) == 0 {
+			delete(h.eventLogs, sessionID)
+		}
+	}
+}
+
+// Broadcast message to all clients
+func (h *WebSocketHandler) BroadcastMessage(event string, data interface{}) {
+	h.publish(&entity.RealtimeEvent{Event: event}, data)
+}
+
+// Send message to specific user (to every connection of that user)
 func (h *WebSocketHandler) SendToUser(userID string, event string, data interface{}) {
-	message := map[string]interface{}{
-		"event": event,
-		"data":  data,
+	h.publish(&entity.RealtimeEvent{UserID: userID, Event: event}, data)
+}
+
+// Send message to session participants subscribed to the session room.
+// The backplane numbers session events; every node keeps the latest ones for replay
+func (h *WebSocketHandler) SendToSession(sessionID string, event string, data interface{}) {
+	h.publish(&entity.RealtimeEvent{SessionID: sessionID, Event: event}, data)
+}
+
+// publish hands the event to the backplane, which delivers it to every node including this one
+func (h *WebSocketHandler) publish(event *entity.RealtimeEvent, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[WebSocket] Failed to marshal %s message: %v\n", event.Event, err)
+		return
 	}
+	event.Data = payload
+
+	if err := h.backplane.Publish(event); err != nil {
+		log.Printf("[WebSocket] ❌ Failed to publish %s: %v\n", event.Event, err)
+	}
+}
 
-	msgBytes, err := json.Marshal(message)
+// deliver sends an event received from the backplane to the sockets held by this node
+func (h *WebSocketHandler) deliver(event *entity.RealtimeEvent) {
+	if event.Control {
+		h.applyControl(event)
+		return
+	}
+
+	if event.SessionID != "" {
+		h.deliverToSession(event)
+		return
+	}
+
+	msgBytes, err := marshalEvent(event.Event, event.Data)
 	if err != nil {
 		log.Printf("[WebSocket] Failed to marshal message: %v\n", err)
 		return
 	}
 
+	if event.UserID == "" {
+		h.broadcast <- msgBytes
+		return
+	}
+
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	for conn, connUserID := range h.clients {
-		if connUserID == userID {
-			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.WriteMessage(websocket.TextMessage, msgBytes); err != nil {
-				log.Printf("[WebSocket] Failed to send to user %s: %v\n", userID, err)
-			}
-			break
+	for client := range h.clients {
+		if client.userID == event.UserID {
+			h.enqueue(client, msgBytes)
 		}
 	}
 }
 
-// Send message to session participants
-func (h *WebSocketHandler) SendToSession(sessionID string, event string, data interface{}) {
-	// This would require session participant tracking
-	// For now, broadcast to all clients
-	h.BroadcastMessage(event, data)
+func (h *WebSocketHandler) deliverToSession(event *entity.RealtimeEvent) {
+	msgBytes, err := json.Marshal(sessionEventEnvelope{
+		Event:     event.Event,
+		SessionID: event.SessionID,
+		Seq:       event.Seq,
+		Data:      event.Data,
+	})
+	if err != nil {
+		log.Printf("[WebSocket] Failed to marshal session message: %v\n", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	eventLog, exists := h.eventLogs[event.SessionID]
+	if !exists {
+		eventLog = &sessionEventLog{}
+		h.eventLogs[event.SessionID] = eventLog
+	}
+
+	if exists && event.Seq != eventLog.seq+1 {
+		if event.Seq <= eventLog.seq {
+			// Already delivered
+			return
+		}
+
+		// This node missed events (e.g. while the backplane reconnected): the buffer
+		// can't be replayed across the gap, and live clients have to resync
+		log.Printf("[WebSocket] ⚠️ Session %s: event gap %d..%d, resetting replay buffer\n", event.SessionID, eventLog.seq+1, event.Seq-1)
+		eventLog.events = nil
+		for client := range h.rooms[event.SessionID] {
+			h.enqueueEvent(client, "resync_required", map[string]interface{}{
+				"sessionId": event.SessionID,
+				"seq":       event.Seq - 1,
+			})
+		}
+	}
+
+	eventLog.seq = event.Seq
+	eventLog.lastEventAt = time.Now()
+	eventLog.events = append(eventLog.events, sequencedEvent{seq: event.Seq, message: msgBytes})
+	if len(eventLog.events) > wsEventBufferSize {
+		eventLog.events = eventLog.events[len(eventLog.events)-wsEventBufferSize:]
+	}
+
+	for client := range h.rooms[event.SessionID] {
+		h.enqueue(client, msgBytes)
+	}
+}
+
+// SubscribeUser adds every open connection of the user to the session room
+// (called after the user creates or joins a session over REST).
+// Goes through the backplane so connections held by other nodes are subscribed too
+func (h *WebSocketHandler) SubscribeUser(sessionID string, userID string) {
+	h.publishControl(wsControlSubscribe, sessionID, userID)
+}
+
+// UnsubscribeUser removes every connection of the user from the session room
+func (h *WebSocketHandler) UnsubscribeUser(sessionID string, userID string) {
+	h.publishControl(wsControlUnsubscribe, sessionID, userID)
+}
+
+const (
+	wsControlSubscribe   = "subscribe"
+	wsControlUnsubscribe = "unsubscribe"
+)
+
+// publishControl sends a room membership change to every node.
+// The session goes in the payload so control events don't take session sequence numbers
+func (h *WebSocketHandler) publishControl(action string, sessionID string, userID string) {
+	h.publish(&entity.RealtimeEvent{
+		UserID:  userID,
+		Event:   action,
+		Control: true,
+	}, map[string]interface{}{"sessionId": sessionID})
+}
+
+func (h *WebSocketHandler) applyControl(event *entity.RealtimeEvent) {
+	var data struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.SessionID == "" {
+		log.Printf("[WebSocket] Invalid control event %s: %v\n", event.Event, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch event.Event {
+	case wsControlSubscribe:
+		for client := range h.clients {
+			if client.userID == event.UserID {
+				h.addToRoom(client, data.SessionID)
+			}
+		}
+	case wsControlUnsubscribe:
+		for client := range h.rooms[data.SessionID] {
+			if client.userID == event.UserID {
+				h.removeFromRoom(client, data.SessionID)
+			}
+		}
+	}
 }
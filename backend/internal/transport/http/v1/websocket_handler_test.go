@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rnegic/synchronous/internal/entity"
+	"github.com/rnegic/synchronous/internal/repository/memory"
+)
+
+func TestEventsSince(t *testing.T) {
+	handler := NewWebSocketHandler(nil, nil, memory.NewEventBackplane())
+	for i := 0; i < 3; i++ {
+		handler.SendToSession("recent", "phase_changed", map[string]int{"n": i})
+	}
+	for i := 0; i < wsEventBufferSize+5; i++ {
+		handler.SendToSession("long", "phase_changed", map[string]int{"n": i})
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		lastSeq   int64
+		wantSeqs  []int64
+		wantOK    bool
+	}{
+		{name: "from the start", sessionID: "recent", lastSeq: 0, wantSeqs: []int64{1, 2, 3}, wantOK: true},
+		{name: "missed events", sessionID: "recent", lastSeq: 1, wantSeqs: []int64{2, 3}, wantOK: true},
+		{name: "up to date", sessionID: "recent", lastSeq: 3, wantOK: true},
+		{name: "client ahead of server", sessionID: "recent", lastSeq: 5},
+		{name: "missed events evicted", sessionID: "long", lastSeq: 1},
+		{name: "oldest buffered event", sessionID: "long", lastSeq: wsEventBufferSize + 3, wantSeqs: []int64{wsEventBufferSize + 4, wsEventBufferSize + 5}, wantOK: true},
+		{name: "unknown session", sessionID: "unknown", lastSeq: 0, wantOK: true},
+		{name: "unknown session after restart", sessionID: "unknown", lastSeq: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler.mu.Lock()
+			events, ok := handler.eventsSince(tt.sessionID, tt.lastSeq)
+			handler.mu.Unlock()
+
+			if ok != tt.wantOK {
+				t.Fatalf("eventsSince(%s, %d) ok = %v, want %v", tt.sessionID, tt.lastSeq, ok, tt.wantOK)
+			}
+			var seqs []int64
+			for _, event := range events {
+				seqs = append(seqs, event.seq)
+			}
+			if fmt.Sprint(seqs) != fmt.Sprint(tt.wantSeqs) {
+				t.Errorf("eventsSince(%s, %d) = %v, want %v", tt.sessionID, tt.lastSeq, seqs, tt.wantSeqs)
+			}
+		})
+	}
+}
+
+func TestDeliverResetsReplayAfterGap(t *testing.T) {
+	handler := NewWebSocketHandler(nil, nil, memory.NewEventBackplane())
+	handler.SendToSession("session", "phase_changed", nil)
+
+	// This node missed events 2..4 while the backplane reconnected
+	handler.deliver(&entity.RealtimeEvent{SessionID: "session", Seq: 5, Event: "phase_changed"})
+	// A duplicate of a delivered event is ignored
+	handler.deliver(&entity.RealtimeEvent{SessionID: "session", Seq: 5, Event: "phase_changed"})
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	if seq := handler.currentSeq("session"); seq != 5 {
+		t.Errorf("currentSeq = %d, want 5", seq)
+	}
+	if _, ok := handler.eventsSince("session", 1); ok {
+		t.Error("events across the gap can be replayed")
+	}
+	if events, ok := handler.eventsSince("session", 4); !ok || len(events) != 1 {
+		t.Errorf("eventsSince(4) = %d events, ok = %v, want the event after the gap", len(events), ok)
+	}
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Текущая фаза помодоро-цикла и её дедлайн (фазами управляет сервер)
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS current_phase VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS phase_started_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS phase_ends_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_phase_ends_at ON sessions(phase_ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_phase_ends_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS phase_ends_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS phase_started_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS current_phase;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Сквозные номера realtime-событий сессий, общие для всех инстансов бэкенда
+CREATE TABLE IF NOT EXISTS realtime_event_seqs (
+    session_id VARCHAR(36) PRIMARY KEY,
+    seq BIGINT NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS realtime_event_seqs;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Отрезки фаз без пауз: по ним считается фактическое время фокуса и перерывов
+CREATE TABLE IF NOT EXISTS session_phase_segments (
+    id VARCHAR(36) PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL,
+    phase VARCHAR(20) NOT NULL,
+    cycle INTEGER NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_phase_segments_session_id ON session_phase_segments(session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_phase_segments;
+-- +goose StatementEnd
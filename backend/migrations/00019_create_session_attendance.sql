@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Интервалы присутствия участников: по ним считается личное время фокуса
+CREATE TABLE IF NOT EXISTS session_attendance (
+    id VARCHAR(36) PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    joined_at TIMESTAMP NOT NULL,
+    left_at TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_session_user ON session_attendance(session_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_attendance;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Участник, исключенный создателем с запретом на повторный вход
+ALTER TABLE session_participants ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE session_participants DROP COLUMN IF EXISTS banned_at;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Плановое время старта: планировщик запускает сессию автоматически и рассылает напоминания
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_scheduled_at ON sessions(scheduled_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_scheduled_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS reminder_sent_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS scheduled_at;
+-- +goose StatementEnd
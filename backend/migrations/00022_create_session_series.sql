@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Повторяющиеся серии сессий: экземпляры создаются заранее по правилу повторения
+CREATE TABLE IF NOT EXISTS session_series (
+    id VARCHAR(36) PRIMARY KEY,
+    creator_id VARCHAR(36) NOT NULL,
+    group_name VARCHAR(255),
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    focus_duration INTEGER NOT NULL,
+    break_duration INTEGER NOT NULL,
+    rrule VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    invite_link VARCHAR(50) NOT NULL,
+    generated_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (creator_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_creator_id ON session_series(creator_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_invite_link ON session_series(invite_link);
+CREATE INDEX IF NOT EXISTS idx_session_series_deleted_at ON session_series(deleted_at);
+
+-- Подписчики серии автоматически добавляются в каждый новый экземпляр
+CREATE TABLE IF NOT EXISTS session_series_subscribers (
+    series_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    subscribed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (series_id, user_id),
+    FOREIGN KEY (series_id) REFERENCES session_series(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_subscribers_user_id ON session_series_subscribers(user_id);
+
+-- Экземпляр серии; повторение не может быть создано дважды
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS series_id VARCHAR(36) REFERENCES session_series(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_series_id ON sessions(series_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_series_occurrence ON sessions(series_id, scheduled_at) WHERE series_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sessions_series_occurrence;
+DROP INDEX IF EXISTS idx_sessions_series_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS session_series_subscribers;
+DROP TABLE IF EXISTS session_series;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Политика автостарта: manual | all_ready | quorum; auto_start_at — дедлайн идущего обратного отсчета
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS start_policy VARCHAR(20) NOT NULL DEFAULT 'manual';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS start_quorum INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS start_countdown INTEGER NOT NULL DEFAULT 0; -- в секундах
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS auto_start_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_sessions_start_policy ON sessions(start_policy) WHERE status = 'pending' AND start_policy <> 'manual';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sessions_start_policy;
+ALTER TABLE sessions DROP COLUMN IF EXISTS auto_start_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS start_countdown;
+ALTER TABLE sessions DROP COLUMN IF EXISTS start_quorum;
+ALTER TABLE sessions DROP COLUMN IF EXISTS start_policy;
+-- +goose StatementEnd
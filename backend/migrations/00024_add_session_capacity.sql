@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Лимит участников сессии; 0 — без ограничения (сессии, созданные до появления лимита)
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS max_participants INTEGER NOT NULL DEFAULT 0;
+
+-- Лист ожидания заполненных сессий: места освобождаются по очереди записи
+CREATE TABLE IF NOT EXISTS session_waitlist (
+    session_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, user_id),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_waitlist_user_id ON session_waitlist(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_waitlist;
+ALTER TABLE sessions DROP COLUMN IF EXISTS max_participants;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Вход в сессию по заявке, которую одобряет создатель или со-ведущий
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS require_approval BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE session_participants ADD COLUMN IF NOT EXISTS is_co_host BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS session_join_requests (
+    id VARCHAR(36) PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by VARCHAR(36),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_join_requests_session_id ON session_join_requests(session_id);
+-- У пользователя может быть только одна нерассмотренная заявка в сессию
+CREATE UNIQUE INDEX IF NOT EXISTS idx_join_requests_pending ON session_join_requests(session_id, user_id) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_join_requests;
+ALTER TABLE session_participants DROP COLUMN IF EXISTS is_co_host;
+ALTER TABLE sessions DROP COLUMN IF EXISTS require_approval;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- План помодоро-циклов: число циклов (0 — без ограничения), длинный перерыв каждые N циклов
+-- или явная последовательность фаз. phase_index — позиция текущей фазы в плане
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS cycles INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS long_break INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS long_break_every INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS phase_plan JSONB;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS phase_index INTEGER NOT NULL DEFAULT 0;
+
+-- Идущие сессии продолжают с текущей фазы: без плана фазы чередуются фокус/перерыв по два на цикл
+UPDATE sessions
+SET phase_index = (GREATEST(current_cycle, 1) - 1) * 2 + CASE WHEN current_phase = 'focus' THEN 0 ELSE 1 END
+WHERE status IN ('active', 'paused') AND current_phase <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN IF EXISTS phase_index;
+ALTER TABLE sessions DROP COLUMN IF EXISTS phase_plan;
+ALTER TABLE sessions DROP COLUMN IF EXISTS long_break_every;
+ALTER TABLE sessions DROP COLUMN IF EXISTS long_break;
+ALTER TABLE sessions DROP COLUMN IF EXISTS cycles;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Личные пресеты сессий: именованные настройки, из которых создаются новые сессии
+CREATE TABLE IF NOT EXISTS session_presets (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    mode session_mode NOT NULL,
+    focus_duration INTEGER NOT NULL,
+    break_duration INTEGER NOT NULL,
+    cycles INTEGER NOT NULL DEFAULT 0,
+    long_break INTEGER NOT NULL DEFAULT 0,
+    long_break_every INTEGER NOT NULL DEFAULT 0,
+    phase_plan JSONB,
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    group_name VARCHAR(255),
+    tasks JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Имена пресетов уникальны в пределах пользователя
+CREATE UNIQUE INDEX IF NOT EXISTS idx_session_presets_user_name ON session_presets(user_id, name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_presets;
+-- +goose StatementEnd
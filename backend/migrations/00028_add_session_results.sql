@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- session_leaderboard хранит итог завершенной сессии по каждому участнику: запись по паре сессия/участник
+-- одна, поэтому статистика за сессию учитывается один раз. total_tasks_completed — выполненные задачи за все сессии
+ALTER TABLE session_leaderboard ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE user_stats ADD COLUMN IF NOT EXISTS total_tasks_completed INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_session_leaderboard_completed_at ON session_leaderboard(completed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_session_leaderboard_completed_at;
+ALTER TABLE user_stats DROP COLUMN IF EXISTS total_tasks_completed;
+ALTER TABLE session_leaderboard DROP COLUMN IF EXISTS completed_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Часовой пояс пользователя (IANA, например Europe/Moscow): по нему считаются серии дней,
+-- окно дневного лидерборда и даты отчетов. Пустая строка — UTC
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_users_timezone ON users(timezone);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_timezone;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
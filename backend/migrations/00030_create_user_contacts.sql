@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Контакты пользователей: участники общей завершенной сессии (в обе стороны) и подписки по Telegram ID или приглашению
+CREATE TABLE IF NOT EXISTS user_contacts (
+    user_id VARCHAR(36) NOT NULL,
+    contact_id VARCHAR(36) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, contact_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (contact_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_contacts_contact_id ON user_contacts(contact_id);
+
+-- Личный код приглашения в контакты
+ALTER TABLE users ADD COLUMN IF NOT EXISTS invite_code VARCHAR(16);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_invite_code ON users(invite_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_invite_code;
+ALTER TABLE users DROP COLUMN IF EXISTS invite_code;
+DROP TABLE IF EXISTS user_contacts;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Полученные пользователями значки; каждый значок выдается один раз
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id VARCHAR(36) NOT NULL,
+    code VARCHAR(32) NOT NULL,
+    session_id VARCHAR(36),
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, code),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Задачи участника в сессии, включая невыполненные
+ALTER TABLE session_leaderboard ADD COLUMN IF NOT EXISTS tasks_total INTEGER NOT NULL DEFAULT 0;
+
+-- Сессии подряд, в которых пользователь выполнил все свои задачи
+ALTER TABLE user_stats ADD COLUMN IF NOT EXISTS perfect_streak INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_stats DROP COLUMN IF EXISTS perfect_streak;
+ALTER TABLE session_leaderboard DROP COLUMN IF EXISTS tasks_total;
+DROP TABLE IF EXISTS user_achievements;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL — стандартный обратный отсчет, 0 — автостарт без отсчета. Раньше 0 означал стандартный отсчет
+ALTER TABLE sessions ALTER COLUMN start_countdown DROP NOT NULL;
+ALTER TABLE sessions ALTER COLUMN start_countdown DROP DEFAULT;
+UPDATE sessions SET start_countdown = NULL WHERE start_countdown = 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE sessions SET start_countdown = 0 WHERE start_countdown IS NULL;
+ALTER TABLE sessions ALTER COLUMN start_countdown SET DEFAULT 0;
+ALTER TABLE sessions ALTER COLUMN start_countdown SET NOT NULL;
+-- +goose StatementEnd
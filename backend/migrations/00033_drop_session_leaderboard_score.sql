@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Очки считает стратегия из конфига при чтении лидерборда, столбец score больше не заполняется
+DROP INDEX IF EXISTS idx_score;
+ALTER TABLE session_leaderboard DROP COLUMN IF EXISTS score;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE session_leaderboard ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_score ON session_leaderboard(score);
+-- +goose StatementEnd
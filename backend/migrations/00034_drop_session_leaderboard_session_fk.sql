@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Журнал итогов переживает удаление сессии: без каскада по session_id пересчет статистики
+-- не теряет итоги удаленных сессий. session_id остается частью первичного ключа
+ALTER TABLE session_leaderboard DROP CONSTRAINT IF EXISTS session_leaderboard_session_id_fkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM session_leaderboard WHERE session_id NOT IN (SELECT id FROM sessions);
+ALTER TABLE session_leaderboard ADD CONSTRAINT session_leaderboard_session_id_fkey
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- События, не поместившиеся в payload NOTIFY (8000 байт): по каналу уходит только id строки,
+-- тело слушатели читают отсюда. Строки живут несколько минут — этого хватает всем инстансам
+CREATE TABLE IF NOT EXISTS realtime_event_payloads (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_realtime_event_payloads_created_at ON realtime_event_payloads(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS realtime_event_payloads;
+-- +goose StatementEnd
@@ -0,0 +1,173 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency — период повторения правила
+type Frequency string
+
+const (
+	Daily  Frequency = "DAILY"
+	Weekly Frequency = "WEEKLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule — подмножество RRULE (RFC 5545): FREQ=DAILY|WEEKLY, INTERVAL, BYDAY, UNTIL.
+// Время суток и первый день повторения берутся из dtstart
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday // только для WEEKLY; пусто — день недели dtstart
+	Until    *time.Time
+}
+
+// Parse разбирает строку вида "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,TU,WE,TH,FR"
+func Parse(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq := Frequency(strings.ToUpper(val))
+			if freq != Daily && freq != Weekly {
+				return nil, fmt.Errorf("unsupported FREQ %q: must be DAILY or WEEKLY", val)
+			}
+			rule.Freq = freq
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Freq == Daily && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown UNTIL format")
+}
+
+// Between возвращает повторения правила в интервале (after, until].
+// dtstart задаёт первое повторение, время суток и часовой пояс: повторения считаются
+// по локальному календарю, поэтому переход на летнее время не сдвигает время начала
+func (r *Rule) Between(dtstart, after, until time.Time) []time.Time {
+	loc := dtstart.Location()
+	if r.Until != nil && r.Until.Before(until) {
+		until = *r.Until
+	}
+
+	var occurrences []time.Time
+
+	first := dtstart
+	if after.After(first) {
+		first = after
+	}
+	day := startOfDay(first.In(loc))
+	base := startOfDay(dtstart)
+
+	for ; !day.After(until); day = day.AddDate(0, 0, 1) {
+		if !r.matches(base, day, dtstart.Weekday()) {
+			continue
+		}
+
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, loc)
+		if occurrence.Before(dtstart) || !occurrence.After(after) || occurrence.After(until) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences
+}
+
+func (r *Rule) matches(base, day time.Time, startWeekday time.Weekday) bool {
+	days := daysBetween(base, day)
+
+	switch r.Freq {
+	case Daily:
+		return days%r.Interval == 0
+	case Weekly:
+		// Недели считаются от понедельника недели dtstart
+		weeks := (days + mondayOffset(base.Weekday())) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == startWeekday
+		}
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween считает календарные дни, не завися от длины суток при смене времени
+func daysBetween(from, to time.Time) int {
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,179 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Rule
+		wantErr bool
+	}{
+		{name: "daily", value: "FREQ=DAILY", want: Rule{Freq: Daily, Interval: 1}},
+		{name: "rrule prefix", value: "RRULE:FREQ=DAILY;INTERVAL=2", want: Rule{Freq: Daily, Interval: 2}},
+		{name: "weekdays", value: "freq=weekly;byday=mo,we", want: Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Monday, time.Wednesday}}},
+		{name: "empty", value: "", wantErr: true},
+		{name: "no freq", value: "INTERVAL=2", wantErr: true},
+		{name: "monthly", value: "FREQ=MONTHLY", wantErr: true},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "unknown day", value: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "daily byday", value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "bad until", value: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+		{name: "unsupported part", value: "FREQ=DAILY;COUNT=3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %+v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.value, err)
+			}
+			if got.Freq != tt.want.Freq || got.Interval != tt.want.Interval || !equalWeekdays(got.ByDay, tt.want.ByDay) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;UNTIL=20260105T090000Z")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	if rule.Until == nil || !rule.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", rule.Until, want)
+	}
+}
+
+func TestRuleBetween(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	// Понедельник, 9:00
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+		until   time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "daily includes dtstart",
+			rule:    "FREQ=DAILY",
+			dtstart: monday,
+			after:   monday.Add(-time.Hour),
+			until:   monday.AddDate(0, 0, 2),
+			want:    []time.Time{monday, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2)},
+		},
+		{
+			name:    "after is exclusive",
+			rule:    "FREQ=DAILY",
+			dtstart: monday,
+			after:   monday,
+			until:   monday.AddDate(0, 0, 1),
+			want:    []time.Time{monday.AddDate(0, 0, 1)},
+		},
+		{
+			name:    "daily interval counts from dtstart",
+			rule:    "FREQ=DAILY;INTERVAL=3",
+			dtstart: monday,
+			after:   monday.AddDate(0, 0, 1),
+			until:   monday.AddDate(0, 0, 7),
+			want:    []time.Time{monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 6)},
+		},
+		{
+			name:    "weekly defaults to dtstart weekday",
+			rule:    "FREQ=WEEKLY",
+			dtstart: monday,
+			after:   monday.Add(-time.Hour),
+			until:   monday.AddDate(0, 0, 14),
+			want:    []time.Time{monday, monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 14)},
+		},
+		{
+			name:    "weekly byday",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,FR",
+			dtstart: monday,
+			after:   monday.Add(-time.Hour),
+			until:   monday.AddDate(0, 0, 7),
+			want:    []time.Time{monday, monday.AddDate(0, 0, 4), monday.AddDate(0, 0, 7)},
+		},
+		{
+			name:    "every other week from the week of dtstart",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			dtstart: monday.AddDate(0, 0, 2), // среда
+			after:   monday,
+			until:   monday.AddDate(0, 0, 21),
+			want:    []time.Time{monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 14), monday.AddDate(0, 0, 16)},
+		},
+		{
+			name:    "until of the rule",
+			rule:    "FREQ=DAILY;UNTIL=20260106T090000Z",
+			dtstart: monday,
+			after:   monday.Add(-time.Hour),
+			until:   monday.AddDate(0, 0, 5),
+			want:    []time.Time{monday, monday.AddDate(0, 0, 1)},
+		},
+		{
+			name:    "nothing before dtstart",
+			rule:    "FREQ=DAILY",
+			dtstart: monday,
+			after:   monday.AddDate(0, 0, -5),
+			until:   monday.Add(-time.Minute),
+			want:    nil,
+		},
+		{
+			name:    "daylight saving keeps local time",
+			rule:    "FREQ=DAILY",
+			dtstart: time.Date(2026, 3, 28, 9, 0, 0, 0, berlin),
+			after:   time.Date(2026, 3, 28, 0, 0, 0, 0, berlin),
+			until:   time.Date(2026, 3, 30, 0, 0, 0, 0, berlin),
+			want:    []time.Time{time.Date(2026, 3, 28, 9, 0, 0, 0, berlin), time.Date(2026, 3, 29, 9, 0, 0, 0, berlin)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+
+			got := rule.Between(tt.dtstart, tt.after, tt.until)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Between()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func equalWeekdays(a, b []time.Weekday) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
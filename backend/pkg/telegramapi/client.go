@@ -64,6 +64,35 @@ type SendMessageRequest struct {
 	Attachments []interface{} `json:"attachments,omitempty"`
 }
 
+// CallbackButton — кнопка inline-клавиатуры; нажатие приходит в webhook как MessageCallbackUpdate с Payload
+type CallbackButton struct {
+	Text    string
+	Payload string // callback_data, не длиннее 64 байт
+}
+
+// InlineKeyboard собирает вложение с inline-клавиатурой для SendMessageRequest.Attachments
+func InlineKeyboard(rows ...[]CallbackButton) map[string]interface{} {
+	buttons := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		line := make([]interface{}, 0, len(row))
+		for _, button := range row {
+			line = append(line, map[string]interface{}{
+				"type":    "callback",
+				"text":    button.Text,
+				"payload": button.Payload,
+			})
+		}
+		buttons = append(buttons, line)
+	}
+
+	return map[string]interface{}{
+		"type": "inline_keyboard",
+		"payload": map[string]interface{}{
+			"buttons": buttons,
+		},
+	}
+}
+
 type SendMessageResponse struct {
 	Message Message `json:"message"`
 }
@@ -120,6 +149,9 @@ func (c *Client) SendMessage(chatID int64, message *SendMessageRequest) (*SendMe
 	if message != nil && message.Text != "" {
 		msg.Text = message.Text
 	}
+	if markup := inlineKeyboardMarkup(message.Attachments); markup != nil {
+		msg.ReplyMarkup = *markup
+	}
 
 	sentMsg, err := c.bot.Send(msg)
 	if err != nil {
@@ -137,6 +169,9 @@ func (c *Client) SendMessageToUser(userID int64, message *SendMessageRequest) (*
 	if message != nil && message.Text != "" {
 		msg.Text = message.Text
 	}
+	if markup := inlineKeyboardMarkup(message.Attachments); markup != nil {
+		msg.ReplyMarkup = *markup
+	}
 
 	sentMsg, err := c.bot.Send(msg)
 	if err != nil {
@@ -148,6 +183,12 @@ func (c *Client) SendMessageToUser(userID int64, message *SendMessageRequest) (*
 	}, nil
 }
 
+// AnswerCallback подтверждает нажатие на inline-кнопку; text показывается пользователю всплывающим уведомлением
+func (c *Client) AnswerCallback(callbackID string, text string) error {
+	_, err := c.bot.Request(tgbotapi.NewCallback(callbackID, text))
+	return err
+}
+
 func (c *Client) GetChat(chatID int64) (*Chat, error) {
 	chatConfig := tgbotapi.ChatInfoConfig{
 		ChatConfig: tgbotapi.ChatConfig{
@@ -252,6 +293,49 @@ func (c *Client) RemoveMember(chatID int64, userID int64) error {
 
 // --- Helpers ----------------------------------------------------------------
 
+// inlineKeyboardMarkup собирает inline-клавиатуру Telegram из вложений "inline_keyboard".
+// Поддерживаются кнопки "callback" и "link"; остальные пропускаются
+func inlineKeyboardMarkup(attachments []interface{}) *tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for _, attachment := range attachments {
+		keyboard, ok := attachment.(map[string]interface{})
+		if !ok || keyboard["type"] != "inline_keyboard" {
+			continue
+		}
+		payload, _ := keyboard["payload"].(map[string]interface{})
+		buttonRows, _ := payload["buttons"].([][]interface{})
+
+		for _, buttonRow := range buttonRows {
+			var row []tgbotapi.InlineKeyboardButton
+			for _, item := range buttonRow {
+				button, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				text, _ := button["text"].(string)
+				switch button["type"] {
+				case "callback":
+					data, _ := button["payload"].(string)
+					row = append(row, tgbotapi.NewInlineKeyboardButtonData(text, data))
+				case "link":
+					url, _ := button["url"].(string)
+					row = append(row, tgbotapi.NewInlineKeyboardButtonURL(text, url))
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &markup
+}
+
 func convertChat(chat *tgbotapi.Chat) *Chat {
 	if chat == nil {
 		return nil